@@ -1,8 +1,20 @@
 package api
 
 import (
+	"spay/endpoints/api/approvals"
+	"spay/endpoints/api/audit"
+	"spay/endpoints/api/disputes"
+	"spay/endpoints/api/files"
+	"spay/endpoints/api/fraud"
+	"spay/endpoints/api/installments"
+	"spay/endpoints/api/limits"
+	"spay/endpoints/api/paymentlinks"
 	"spay/endpoints/api/providers"
+	"spay/endpoints/api/rates"
+	"spay/endpoints/api/reconciliations"
 	"spay/endpoints/api/services"
+	"spay/endpoints/api/settlements"
+	"spay/endpoints/api/subscriptions"
 	"spay/endpoints/api/transactions"
 	"spay/endpoints/api/users"
 
@@ -24,5 +36,41 @@ func AttachAPI(server *echo.Echo, log *zerolog.Logger) {
 
 		// Transaction Endpoints: /api/transactions
 		transactions.AttachAPI(apiServer)
+
+		// Reconciliation Endpoints: /api/reconciliations
+		reconciliations.AttachAPI(apiServer)
+
+		// Payment Link Endpoints: /api/payment-links
+		paymentlinks.AttachAPI(apiServer)
+
+		// Subscription Endpoints: /api/subscriptions
+		subscriptions.AttachAPI(apiServer)
+
+		// Installment Endpoints: /api/installments
+		installments.AttachAPI(apiServer)
+
+		// Limit Endpoints: /api/limits
+		limits.AttachAPI(apiServer)
+
+		// Fraud Endpoints: /api/fraud
+		fraud.AttachAPI(apiServer)
+
+		// Dispute Endpoints: /api/disputes
+		disputes.AttachAPI(apiServer)
+
+		// Exchange Rate Endpoints: /api/exchange-rates
+		rates.AttachAPI(apiServer)
+
+		// Settlement Endpoints: /api/settlements
+		settlements.AttachAPI(apiServer)
+
+		// Approval Endpoints: /api/approvals
+		approvals.AttachAPI(apiServer)
+
+		// Audit Log Endpoints: /api/audit-logs
+		audit.AttachAPI(apiServer)
+
+		// Signed File Endpoints: /api/files
+		files.AttachAPI(apiServer)
 	}
 }
@@ -0,0 +1,99 @@
+package approvals
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Approve
+// @Summary      	Approve request
+// @Description  	Approbation par un second utilisateur et exécution de l'opération,
+// @Description  	un échec de l'exécution est enregistré (FAILED) et l'opération doit être redemandée
+// @Tags         	Approvals
+// @Product       	json
+// @response      	200 {object} ResApprovalAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/:id/approve [post]
+func (s *ApprovalApiRessource) Approve() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		approval, ok := c.Get("APPROVAL").(*models.ApprovalRequestModel)
+		if !ok {
+			err := fmt.Errorf("demande d'approbation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if approval.State != models.APPROVAL_PENDING {
+			err := fmt.Errorf("demande déjà traitée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := approval.CanDecide(*loginUser); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := *approval
+
+		var object interface{}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			object, err = approval.Approve(tx, utils.AuditActor(c), *loginUser, time.Now())
+			if err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_APPROVAL_APPROVE, "approval", approval.ID, before, approval)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+
+			// L'exécution est annulée, la demande est close en échec
+			pending := before
+			failErr := db.Transaction(func(tx *gorm.DB) error {
+				return pending.Fail(tx, *loginUser, err, time.Now())
+			})
+			if failErr != nil {
+				log.Error().Err(failErr).Msgf("")
+			}
+
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if settlement, ok := object.(models.SettlementModel); ok {
+			utils.NotifySettlement(db, settlement)
+		}
+
+		type resData struct {
+			Approval models.ApprovalRequestModel `json:"approval"`
+		}
+
+		resp.SetData(resData{
+			Approval: *approval,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,73 @@
+package approvals
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Cancel
+// @Summary      	Cancel request
+// @Description  	Retrait d'une demande en attente par son initiateur
+// @Tags         	Approvals
+// @Product       	json
+// @response      	200 {object} ResApprovalAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/:id/cancel [post]
+func (s *ApprovalApiRessource) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		approval, ok := c.Get("APPROVAL").(*models.ApprovalRequestModel)
+		if !ok {
+			err := fmt.Errorf("demande d'approbation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := *approval
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := approval.Cancel(tx, *loginUser, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_APPROVAL_CANCEL, "approval", approval.ID, before, approval)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Approval models.ApprovalRequestModel `json:"approval"`
+		}
+
+		resp.SetData(resData{
+			Approval: *approval,
+		})
+
+		return resp.Send(c)
+	}
+}
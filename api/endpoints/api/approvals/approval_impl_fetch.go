@@ -0,0 +1,193 @@
+package approvals
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResApprovalAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Approvals  []models.ApprovalRequestModel `json:"approvals"`
+		Pagination models.PaginationModel        `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all approval requests paginate
+// @Description  	Récuperation des demandes d'approbation paginer
+// @Tags         	Approvals
+// @Product       	json
+// @Param        	filter-state query string false "Etats séparés par virgule (PENDING,APPROVED,REJECTED,CANCELED,FAILED)"
+// @Param        	filter-operation query string false "Opérations séparées par virgule (ex: transfer.create)"
+// @Param        	filter-service query string false "Identifiant de la boutique"
+// @response      	200 {object} ResApprovalAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/ [get]
+func (s *ApprovalApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := getLoginUser(db, claims)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := fetchRestricted(db.Model(&models.ApprovalRequestModel{}), *loginUser)
+
+		if filter := c.QueryParam("filter-state"); filter != "" {
+			reqDb = reqDb.Where("state IN (?)", strings.Split(strings.ToUpper(filter), ","))
+		}
+
+		if filter := c.QueryParam("filter-operation"); filter != "" {
+			reqDb = reqDb.Where("operation IN (?)", strings.Split(strings.ToLower(filter), ","))
+		}
+
+		if filter := c.QueryParam("filter-service"); filter != "" {
+			reqDb = reqDb.Where("service_id = ?", filter)
+		}
+
+		approvals := []models.ApprovalRequestModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &approvals, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Approvals  []models.ApprovalRequestModel `json:"approvals"`
+			Pagination models.PaginationModel        `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			approvals, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(approvals, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Approvals:  approvals,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// fetchRestricted restriction aux demandes de l'utilisateur et des boutiques qu'il gère
+func fetchRestricted(reqDb *gorm.DB, loginUser models.UserModel) *gorm.DB {
+	if serviceIds, all := loginUser.ServicesWith(models.PERM_APPROVALS_READ); !all {
+		reqDb = reqDb.Where("requested_by = ? OR service_id IN (?)", loginUser.ID, serviceIds)
+	}
+
+	return reqDb
+}
+
+func fetchExec[T any](reqDb *gorm.DB, items *[]T, params fetchParams, count *int64) error {
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		if len(params.Orders) == 0 {
+			params.Orders = []string{"created_at desc"}
+		}
+
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(items)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	Orders    []string
+	Query     string
+	Limit     int
+	Offset    int
+	Cursor    *models.Cursor
+	WithCount bool
+}
+
+func fetchParamsFromContext(c echo.Context) fetchParams {
+	params := fetchParams{}
+
+	params.Limit, _ = c.Get("LIMIT").(int)
+	params.Offset, _ = c.Get("OFFSET").(int)
+	params.Query, _ = c.Get("QUERY").(string)
+	params.Orders, _ = c.Get("ORDERS").([]string)
+	params.Cursor, _ = c.Get("CURSOR").(*models.Cursor)
+	params.WithCount, _ = c.Get("WITH_COUNT").(bool)
+
+	return params
+}
+
+func (params fetchParams) pagination(count *int64) models.PaginationModel {
+	pagination := models.PaginationModel{
+		Limit:  params.Limit,
+		Offset: params.Offset,
+		Query:  params.Query,
+	}
+
+	if params.WithCount {
+		pagination.Count = count
+	}
+
+	if params.Cursor != nil {
+		pagination.Offset = 0
+	}
+
+	return pagination
+}
@@ -0,0 +1,55 @@
+package approvals
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResApprovalAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Approval models.ApprovalRequestModel `json:"approval"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get approval request data
+// @Description  	Récuperation d'une demande d'approbation
+// @Tags         	Approvals
+// @Product       	json
+// @response      	200 {object} ResApprovalAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/:id/ [get]
+func (s *ApprovalApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		approval, ok := c.Get("APPROVAL").(*models.ApprovalRequestModel)
+		if !ok {
+			err := fmt.Errorf("demande d'approbation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Approval models.ApprovalRequestModel `json:"approval"`
+		}
+
+		resp.SetData(resData{
+			Approval: *approval,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,165 @@
+package approvals
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResApprovalPolicyAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Policies []models.ApprovalPolicyModel `json:"policies"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResApprovalPolicyAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Policy models.ApprovalPolicyModel `json:"policy"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type PolicyFormData struct {
+	Operation    string  `json:"operation" form:"operation" xml:"operation" validate:"required"`                                                         // settlement.payout, transfer.create, user.change_role, service.regenerate_client, dispute.refund, transaction.refund
+	Active       *bool   `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"`                                                       // Politique appliquée, true par défaut
+	MinAmount    float64 `json:"min_amount" form:"min_amount" xml:"min_amount" validate:"gte=0"`                                                         // Approbation à partir de ce montant, 0: toujours
+	ApproverRole string  `json:"approver_role" form:"approver_role" xml:"approver_role" validate:"required,oneof=USER_MANAGER USER_ADMIN SERVICE_ADMIN"` // Rôle du second utilisateur
+	Description  string  `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"`                                        // Description de la politique
+}
+
+// FetchPolicies
+// @Summary      	Fetch approval policies
+// @Description  	Récuperation des politiques d'approbation par opération
+// @Tags         	Approvals
+// @Product       	json
+// @response      	200 {object} ResApprovalPolicyAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/policies [get]
+func (s *ApprovalApiRessource) FetchPolicies() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		policies := []models.ApprovalPolicyModel{}
+		result := db.Order("operation asc").Find(&policies)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Policies []models.ApprovalPolicyModel `json:"policies"`
+		}
+
+		resp.SetData(resData{
+			Policies: policies,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// SetPolicy
+// @Summary      	Set approval policy
+// @Description  	Création ou modification de la politique d'approbation d'une opération, administrateurs uniquement
+// @Tags         	Approvals
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData PolicyFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResApprovalPolicyAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/approvals/policies [put]
+func (s *ApprovalApiRessource) SetPolicy() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(PolicyFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if !models.IsApprovalOperation(data.Operation) {
+			err := fmt.Errorf("opération inconnue %v", data.Operation)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		policy := models.ApprovalPolicyModel{}
+		result := db.Where("operation = ?", data.Operation).Limit(1).Find(&policy)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		var before *models.ApprovalPolicyModel
+		if result.RowsAffected > 0 {
+			previous := policy
+			before = &previous
+		}
+
+		policy.Operation = data.Operation
+		policy.Active = data.Active == nil || *data.Active
+		policy.MinAmount = data.MinAmount
+		policy.ApproverRole = data.ApproverRole
+		policy.Description = data.Description
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&policy).Error; err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_APPROVAL_POLICY_SET, "approval_policy", policy.ID, before, policy)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Policy models.ApprovalPolicyModel `json:"policy"`
+		}
+
+		resp.SetData(resData{
+			Policy: policy,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,45 @@
+package approvals
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ApprovalApiRessource struct {
+	*models.ApprovalRequestModel
+}
+
+// Colonnes autorisées pour le tri
+var approvalSorts = []string{"created_at", "updated_at", "operation", "state", "amount", "decided_at"}
+
+func AttachAPI(server *echo.Group) {
+	approvalApi := &ApprovalApiRessource{&models.ApprovalRequestModel{}}
+
+	// Approval
+	approvalApiService := server.Group("/approvals")
+	{
+		// Fetch
+		approvalApiService.GET("/", approvalApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(approvalSorts...))
+
+		// Approval Policies
+		approvalApiService.GET("/policies", approvalApi.FetchPolicies(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_APPROVALS))
+		approvalApiService.PUT("/policies", approvalApi.SetPolicy(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_APPROVALS_POLICIES))
+
+		approvalOneApiService := approvalApiService.Group("/:id", middlewares.GrantMid(), approvalApi.GetOnMid())
+		{
+			// Get Approval Info
+			approvalOneApiService.GET("/", approvalApi.GetInfo())
+
+			// Approve and execute
+			approvalOneApiService.POST("/approve", approvalApi.Approve())
+
+			// Reject
+			approvalOneApiService.POST("/reject", approvalApi.Reject())
+
+			// Cancel by requester
+			approvalOneApiService.POST("/cancel", approvalApi.Cancel())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package approvals
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (s *ApprovalApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+			if !ok {
+				err := fmt.Errorf("authentification obligatoire")
+				resp.SetStatus(http.StatusUnauthorized)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			db, err := models.GetDB()
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			approval, err := getApproval(db, c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if approval == nil {
+				err := fmt.Errorf("demande d'approbation inexistante")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			loginUser, err := getLoginUser(db, claims)
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			// Consultation réservée à l'initiateur, aux approbateurs et aux utilisateurs de la boutique
+			if approval.RequestedBy != loginUser.ID && approval.CanDecide(*loginUser) != nil &&
+				!loginUser.Can(models.PERM_APPROVALS_READ, approval.ServiceId) {
+				err := fmt.Errorf("permission non accordé")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("APPROVAL", approval)
+			c.Set("LOGIN_USER", loginUser)
+
+			return next(c)
+		}
+	}
+}
+
+func getApproval(db *gorm.DB, approvalId string) (*models.ApprovalRequestModel, error) {
+	id, err := uuid.FromString(approvalId)
+	if err != nil {
+		return nil, fmt.Errorf("identifiant demande invalide")
+	}
+
+	approval := models.ApprovalRequestModel{}
+	result := db.Where("id = ?", id.String()).Limit(1).Find(&approval)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &approval, nil
+}
+
+// getLoginUser utilisateur connecté et ses permissions
+func getLoginUser(db *gorm.DB, claims jwt.MapClaims) (*models.UserModel, error) {
+	loginUser := models.UserModel{}
+	loginUser.AuthId = claims["sub"].(string)
+
+	result := db.
+		Scopes(models.PreloadGrants).
+		Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msgf("")
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	return &loginUser, nil
+}
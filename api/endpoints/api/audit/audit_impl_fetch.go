@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResAuditAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Logs       []models.AuditLogModel `json:"logs"`
+		Pagination models.PaginationModel `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch audit logs paginate
+// @Description  	Récuperation du journal d'audit paginer, administrateurs uniquement
+// @Tags         	Audit
+// @Product       	json
+// @Param        	filter-actor query string false "Identifiant de connexion de l'auteur"
+// @Param        	filter-action query string false "Actions séparées par virgule (ex: user.change_role)"
+// @Param        	filter-entity-type query string false "Type d'objet (ex: service)"
+// @Param        	filter-entity-id query string false "Identifiant de l'objet"
+// @Param        	filter-request query string false "Identifiant de requête"
+// @Param        	filter-date-from query string false "Date minimum (ex: 2023-01-31)"
+// @Param        	filter-date-to query string false "Date maximum incluse (ex: 2023-01-31)"
+// @response      	200 {object} ResAuditAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/audit-logs/ [get]
+func (s *AuditApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb, err := fetchFilters(c, db.Model(&models.AuditLogModel{}))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		logs := []models.AuditLogModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &logs, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Logs       []models.AuditLogModel `json:"logs"`
+			Pagination models.PaginationModel `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			logs, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(logs, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Logs:       logs,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// fetchFilters filtres du journal
+func fetchFilters(c echo.Context, reqDb *gorm.DB) (*gorm.DB, error) {
+	if filter := c.QueryParam("filter-actor"); filter != "" {
+		reqDb = reqDb.Where("actor_id = ?", filter)
+	}
+
+	if filter := c.QueryParam("filter-action"); filter != "" {
+		reqDb = reqDb.Where("action IN (?)", strings.Split(strings.ToLower(filter), ","))
+	}
+
+	if filter := c.QueryParam("filter-entity-type"); filter != "" {
+		reqDb = reqDb.Where("entity_type = ?", filter)
+	}
+
+	if filter := c.QueryParam("filter-entity-id"); filter != "" {
+		reqDb = reqDb.Where("entity_id = ?", filter)
+	}
+
+	if filter := c.QueryParam("filter-request"); filter != "" {
+		reqDb = reqDb.Where("request_id = ?", filter)
+	}
+
+	if from := c.QueryParam("filter-date-from"); from != "" {
+		ok, date := utils.StringToDate(from, utils.LAYOUTS_TIME)
+		if !ok {
+			return nil, fmt.Errorf("filtre date de début invalide %v", from)
+		}
+
+		reqDb = reqDb.Where("created_at >= ?", date)
+	}
+
+	if to := c.QueryParam("filter-date-to"); to != "" {
+		ok, date := utils.StringToDate(to, utils.LAYOUTS_TIME)
+		if !ok {
+			return nil, fmt.Errorf("filtre date de fin invalide %v", to)
+		}
+
+		reqDb = reqDb.Where("created_at < ?", date.AddDate(0, 0, 1))
+	}
+
+	return reqDb, nil
+}
+
+func fetchExec[T any](reqDb *gorm.DB, items *[]T, params fetchParams, count *int64) error {
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		if len(params.Orders) == 0 {
+			params.Orders = []string{"sequence desc"}
+		}
+
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(items)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	Orders    []string
+	Query     string
+	Limit     int
+	Offset    int
+	Cursor    *models.Cursor
+	WithCount bool
+}
+
+func fetchParamsFromContext(c echo.Context) fetchParams {
+	params := fetchParams{}
+
+	params.Limit, _ = c.Get("LIMIT").(int)
+	params.Offset, _ = c.Get("OFFSET").(int)
+	params.Query, _ = c.Get("QUERY").(string)
+	params.Orders, _ = c.Get("ORDERS").([]string)
+	params.Cursor, _ = c.Get("CURSOR").(*models.Cursor)
+	params.WithCount, _ = c.Get("WITH_COUNT").(bool)
+
+	return params
+}
+
+func (params fetchParams) pagination(count *int64) models.PaginationModel {
+	pagination := models.PaginationModel{
+		Limit:  params.Limit,
+		Offset: params.Offset,
+		Query:  params.Query,
+	}
+
+	if params.WithCount {
+		pagination.Count = count
+	}
+
+	if params.Cursor != nil {
+		pagination.Offset = 0
+	}
+
+	return pagination
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResAuditAPIVerifySuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Valid    bool  `json:"valid"`
+		Checked  int64 `json:"checked"`
+		BrokenAt int64 `json:"broken_at,omitempty"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Verify
+// @Summary      	Verify audit log chain
+// @Description  	Contrôle de la chaîne d'empreintes du journal, renvoie la séquence de la première entrée altérée
+// @Tags         	Audit
+// @Product       	json
+// @response      	200 {object} ResAuditAPIVerifySuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/audit-logs/verify [get]
+func (s *AuditApiRessource) Verify() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		checked, brokenAt, err := models.VerifyAudit(db)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Valid    bool  `json:"valid"`
+			Checked  int64 `json:"checked"`
+			BrokenAt int64 `json:"broken_at,omitempty"`
+		}
+
+		resp.SetData(resData{
+			Valid:    brokenAt == 0,
+			Checked:  checked,
+			BrokenAt: brokenAt,
+		})
+
+		return resp.Send(c)
+	}
+}
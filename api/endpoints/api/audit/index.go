@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditApiRessource struct {
+	*models.AuditLogModel
+}
+
+// Colonnes autorisées pour le tri
+var auditSorts = []string{"sequence", "created_at", "action", "entity_type"}
+
+func AttachAPI(server *echo.Group) {
+	auditApi := &AuditApiRessource{&models.AuditLogModel{}}
+
+	// Audit Log
+	auditApiService := server.Group("/audit-logs")
+	{
+		// Fetch
+		auditApiService.GET("/", auditApi.Fetch(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_AUDIT), models.PaginationMid(auditSorts...))
+
+		// Verify hash chain
+		auditApiService.GET("/verify", auditApi.Verify(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_AUDIT))
+	}
+}
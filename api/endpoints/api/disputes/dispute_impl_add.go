@@ -0,0 +1,116 @@
+package disputes
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResDisputeAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Dispute models.DisputeModel `json:"dispute"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	TransactionId string  `json:"transaction_id" form:"transaction_id" xml:"transaction_id" validate:"required"` // Transaction contestée
+	Amount        float64 `json:"amount" form:"amount" xml:"amount" validate:"gte=0"`                            // Montant contesté, totalité par défaut
+	Reason        string  `json:"reason" form:"reason" xml:"reason" validate:"required"`                         // Motif de la contestation
+}
+
+// Add
+// @Summary      	Open dispute
+// @Description  	Ouverture d'une contestation sur un paiement, le montant est retenu sur le solde de la boutique
+// @Tags         	Disputes
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResDisputeAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/ [post]
+func (s *DisputeApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newDispute := models.DisputeModel{}
+		err := utils.BindValidate(c, data, &newDispute)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := getLoginUser(db, claims)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := loginUser.Authorize(models.PERM_PLATFORM_DISPUTES, ""); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		transaction := models.TransactionModel{}
+		result := db.Where("id = ?", newDispute.TransactionId).Limit(1).Find(&transaction)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("transaction inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newDispute.OpenedById = loginUser.ID
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return models.OpenDispute(tx, &newDispute, transaction)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		utils.NotifyDispute(db, newDispute)
+
+		type resData struct {
+			Dispute models.DisputeModel `json:"dispute"`
+		}
+
+		resp.SetData(resData{
+			Dispute: newDispute,
+		})
+
+		return resp.Send(c)
+	}
+}
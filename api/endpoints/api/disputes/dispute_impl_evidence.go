@@ -0,0 +1,235 @@
+package disputes
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type RequestEvidenceFormData struct {
+	DueAt *time.Time `json:"due_at" form:"due_at" xml:"due_at"` // Date limite de dépôt, DISPUTE_EVIDENCE_DAYS jours par défaut
+}
+
+// RequestEvidence
+// @Summary      	Request dispute evidence
+// @Description  	Demande de pièces justificatives à la boutique avant la date limite
+// @Tags         	Disputes
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData RequestEvidenceFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResDisputeAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/:id/request-evidence [post]
+func (s *DisputeApiRessource) RequestEvidence() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		dispute, ok := c.Get("DISPUTE").(*models.DisputeModel)
+		if !ok {
+			err := fmt.Errorf("contestation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(RequestEvidenceFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		now := time.Now()
+
+		dueAt := now.AddDate(0, 0, utils.DisputeEvidenceDays())
+		if data.DueAt != nil {
+			if !data.DueAt.After(now) {
+				err := fmt.Errorf("la date limite doit être dans le futur")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			dueAt = *data.DueAt
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return dispute.RequestEvidence(tx, dueAt)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		utils.NotifyDispute(db, *dispute)
+
+		type resData struct {
+			Dispute models.DisputeModel `json:"dispute"`
+		}
+
+		resp.SetData(resData{
+			Dispute: *dispute,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+type ResDisputeAPIEvidenceSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Evidence models.DisputeEvidenceModel `json:"evidence"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// AddEvidence
+// @Summary      	Upload dispute evidence
+// @Description  	Dépôt d'une pièce justificative (pdf, jpeg, png) par un gestionnaire de la boutique
+// @Tags         	Disputes
+// @accept 			mpfd
+// @Product       	json
+// @Param        	file formData file true "Pièce justificative"
+// @Param        	description formData string false "Description de la pièce"
+// @response      	200 {object} ResDisputeAPIEvidenceSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/:id/evidences [post]
+func (s *DisputeApiRessource) AddEvidence() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		dispute, ok := c.Get("DISPUTE").(*models.DisputeModel)
+		if !ok {
+			err := fmt.Errorf("contestation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if dispute.IsResolved() {
+			err := fmt.Errorf("contestation déjà tranchée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			err := fmt.Errorf("fichier obligatoire")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		name, err := uuid.NewV4()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		uploaded, err := utils.SaveUpload(file, "disputes/"+dispute.ID, name.String(), utils.DocumentContentTypes)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		evidence := models.DisputeEvidenceModel{
+			FileName:     uploaded.FileName,
+			ContentType:  uploaded.ContentType,
+			Size:         uploaded.Size,
+			Path:         uploaded.Path,
+			Description:  c.FormValue("description"),
+			UploadedById: loginUser.ID,
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return dispute.AddEvidence(tx, &evidence)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+
+			// Le fichier sans enregistrement est supprimé
+			utils.RemoveUpload(uploaded.Path)
+
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Evidence models.DisputeEvidenceModel `json:"evidence"`
+		}
+
+		resp.SetData(resData{
+			Evidence: evidence,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// GetEvidence
+// @Summary      	Download dispute evidence
+// @Description  	Téléchargement d'une pièce justificative
+// @Tags         	Disputes
+// @Product       	octet-stream
+// @response      	200 {file} binary
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/:id/evidences/:evidence_id [get]
+func (s *DisputeApiRessource) GetEvidence() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		dispute, ok := c.Get("DISPUTE").(*models.DisputeModel)
+		if !ok {
+			err := fmt.Errorf("contestation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		var evidence *models.DisputeEvidenceModel
+		for i := range dispute.Evidences {
+			if dispute.Evidences[i].ID == c.Param("evidence_id") {
+				evidence = &dispute.Evidences[i]
+				break
+			}
+		}
+
+		if evidence == nil {
+			err := fmt.Errorf("pièce justificative inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := utils.SendUpload(c, evidence.Path, evidence.FileName, evidence.ContentType, false); err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		return nil
+	}
+}
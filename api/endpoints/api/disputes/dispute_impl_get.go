@@ -0,0 +1,55 @@
+package disputes
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResDisputeAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Dispute models.DisputeModel `json:"dispute"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get dispute data
+// @Description  	Récuperation des informations de la contestation et de ses pièces justificatives
+// @Tags         	Disputes
+// @Product       	json
+// @response      	200 {object} ResDisputeAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/:id/ [get]
+func (s *DisputeApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		dispute, ok := c.Get("DISPUTE").(*models.DisputeModel)
+		if !ok {
+			err := fmt.Errorf("contestation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Dispute models.DisputeModel `json:"dispute"`
+		}
+
+		resp.SetData(resData{
+			Dispute: *dispute,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,122 @@
+package disputes
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResolveFormData struct {
+	Outcome    string `json:"outcome" form:"outcome" xml:"outcome" validate:"required,oneof=WON LOST won lost"` // Issue de la contestation (WON, LOST)
+	Resolution string `json:"resolution" form:"resolution" xml:"resolution" validate:"required"`                // Motif de la décision
+}
+
+// Resolve
+// @Summary      	Resolve dispute
+// @Description  	Décision sur la contestation: gagnée la retenue est levée, perdue le montant est débité du solde de la boutique,
+// @Description  	la rétrocession d'une contestation perdue peut nécessiter une approbation (202) selon la politique dispute.refund
+// @Tags         	Disputes
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData ResolveFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResDisputeAPIGetSuccess
+// @response      	202 {object} ResDisputeAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/disputes/:id/resolve [post]
+func (s *DisputeApiRessource) Resolve() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		dispute, ok := c.Get("DISPUTE").(*models.DisputeModel)
+		if !ok {
+			err := fmt.Errorf("contestation non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(ResolveFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		state := strings.ToUpper(data.Outcome)
+
+		var approval *models.ApprovalRequestModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Rétrocession au payeur soumise à la politique d'approbation
+			if state == models.DISPUTE_LOST {
+				if err := dispute.CheckTransition(state); err != nil {
+					return err
+				}
+
+				approval, err = models.RequestApproval(tx, utils.AuditActor(c), models.ApprovalRequestModel{
+					Operation:   models.APPROVAL_REFUND,
+					ServiceId:   dispute.ServiceId,
+					TargetId:    dispute.ID,
+					Amount:      dispute.Amount,
+					Currency:    dispute.Currency,
+					RequestedBy: loginUser.ID,
+				}, models.DisputeResolution{State: state, Resolution: data.Resolution})
+				if err != nil || approval != nil {
+					return err
+				}
+			}
+
+			before := *dispute
+			if err := dispute.Resolve(tx, state, data.Resolution, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_DISPUTE_RESOLVE, "dispute", dispute.ID, before, dispute)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+		} else {
+			utils.NotifyDispute(db, *dispute)
+		}
+
+		type resData struct {
+			Dispute  models.DisputeModel          `json:"dispute"`
+			Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
+		}
+
+		resp.SetData(resData{
+			Dispute:  *dispute,
+			Approval: approval,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,45 @@
+package disputes
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DisputeApiRessource struct {
+	*models.DisputeModel
+}
+
+// Colonnes autorisées pour le tri
+var disputeSorts = []string{"created_at", "updated_at", "amount", "state", "evidence_due_at"}
+
+func AttachAPI(server *echo.Group) {
+	disputeApi := &DisputeApiRessource{&models.DisputeModel{}}
+
+	// Dispute
+	disputeApiService := server.Group("/disputes")
+	{
+		// Fetch
+		disputeApiService.GET("/", disputeApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(disputeSorts...))
+
+		// Open Dispute
+		disputeApiService.POST("/", disputeApi.Add(), middlewares.GrantMid())
+
+		disputeOneApiService := disputeApiService.Group("/:id", middlewares.GrantMid(), disputeApi.GetOnMid())
+		{
+			// Get Dispute Info
+			disputeOneApiService.GET("/", disputeApi.GetInfo(), middlewares.PermMid(models.PERM_DISPUTES_READ))
+
+			// Request evidence from the service
+			disputeOneApiService.POST("/request-evidence", disputeApi.RequestEvidence(), middlewares.PermMid(models.PERM_PLATFORM_DISPUTES))
+
+			// Evidence files
+			disputeOneApiService.POST("/evidences", disputeApi.AddEvidence(), middlewares.PermMid(models.PERM_DISPUTES_EVIDENCE))
+			disputeOneApiService.GET("/evidences/:evidence_id", disputeApi.GetEvidence(), middlewares.PermMid(models.PERM_DISPUTES_READ))
+
+			// Resolve Dispute
+			disputeOneApiService.POST("/resolve", disputeApi.Resolve(), middlewares.PermMid(models.PERM_PLATFORM_DISPUTES))
+		}
+	}
+}
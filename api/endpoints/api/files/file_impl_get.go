@@ -0,0 +1,56 @@
+package files
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"spay/models"
+	"spay/utils"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// Get
+// @Summary      	Download signed file
+// @Description  	Téléchargement d'un fichier du stockage (logo, pièce KYC) par adresse signée,
+// @Description  	les images sont affichées dans le navigateur, les autres fichiers téléchargés
+// @Tags         	Files
+// @Product       	octet-stream
+// @Param        	name query string true "Nom du fichier"
+// @Param        	expires query string true "Expiration (timestamp unix)"
+// @Param        	signature query string true "Signature"
+// @response      	200 {file} binary
+// @response      	403 {object} models.ResFailure
+// @Router       	/api/files/* [get]
+func (s *FileApiRessource) Get() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		relative := path.Clean("/" + c.Param("*"))[1:]
+		fileName := c.QueryParam("name")
+
+		err := utils.CheckSignedUrl(relative, fileName, c.QueryParam("expires"), c.QueryParam("signature"))
+		if err != nil {
+			resp.SetStatus(http.StatusForbidden)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Seuls les types acceptés à l'envoi sont servis, les autres sont téléchargés
+		contentType := utils.UploadContentType(relative)
+
+		if err := utils.SendUpload(c, relative, fileName, contentType, strings.HasPrefix(contentType, "image/")); err != nil {
+			log.Error().Err(err).Msgf("")
+
+			err := fmt.Errorf("fichier inexistant")
+			resp.SetStatus(http.StatusNotFound)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+package files
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type FileApiRessource struct{}
+
+func AttachAPI(server *echo.Group) {
+	fileApi := &FileApiRessource{}
+
+	// Files, accès par adresse signée sans authentification
+	fileApiService := server.Group("/files")
+	{
+		// Download
+		fileApiService.GET("/*", fileApi.Get())
+	}
+}
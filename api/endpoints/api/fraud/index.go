@@ -0,0 +1,49 @@
+package fraud
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FraudApiRessource struct {
+	*models.FraudRuleModel
+}
+
+// Colonnes autorisées pour le tri
+var ruleSorts = []string{"created_at", "updated_at", "name", "kind", "score"}
+var listSorts = []string{"created_at", "kind", "value"}
+var reviewSorts = []string{"created_at", "updated_at", "score", "state"}
+
+func AttachAPI(server *echo.Group) {
+	fraudApi := &FraudApiRessource{&models.FraudRuleModel{}}
+
+	// Anti-fraude, réservé aux gestionnaires
+	fraudApiService := server.Group("/fraud", middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_FRAUD))
+	{
+		// Rules
+		fraudApiService.GET("/rules", fraudApi.FetchRules(), models.PaginationMid(ruleSorts...))
+		fraudApiService.POST("/rules", fraudApi.AddRule())
+		fraudApiService.PUT("/rules/:rule_id", fraudApi.UpdateRule(), fraudApi.GetRuleOnMid())
+		fraudApiService.DELETE("/rules/:rule_id", fraudApi.DeleteRule(), fraudApi.GetRuleOnMid())
+
+		// Blocked phones and IPs
+		fraudApiService.GET("/lists", fraudApi.FetchLists(), models.PaginationMid(listSorts...))
+		fraudApiService.POST("/lists", fraudApi.AddList())
+		fraudApiService.DELETE("/lists/:list_id", fraudApi.DeleteList())
+
+		// Review queue
+		fraudApiService.GET("/reviews", fraudApi.FetchReviews(), models.PaginationMid(reviewSorts...))
+
+		reviewOneApiService := fraudApiService.Group("/reviews/:review_id", fraudApi.GetReviewOnMid())
+		{
+			// Get Review Info
+			reviewOneApiService.GET("/", fraudApi.GetReview())
+
+			// Review decision
+			reviewOneApiService.POST("/approve", fraudApi.Approve())
+			reviewOneApiService.POST("/reject", fraudApi.Reject())
+		}
+	}
+}
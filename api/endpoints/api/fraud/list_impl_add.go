@@ -0,0 +1,103 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudListAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		List models.FraudListModel `json:"list"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddListFormData struct {
+	Kind   string `json:"kind" form:"kind" xml:"kind" validate:"required,oneof=PHONE IP"` // PHONE, IP
+	Value  string `json:"value" form:"value" xml:"value" validate:"required"`             // Téléphone ou adresse IP
+	Reason string `json:"reason" form:"reason" xml:"reason" validate:"omitempty"`         // Motif du blocage
+}
+
+// AddList
+// @Summary      	Block a phone or IP
+// @Description  	Ajout d'un téléphone ou d'une adresse IP à la liste de blocage
+// @Tags         	Fraud
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddListFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResFraudListAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/lists [post]
+func (s *FraudApiRessource) AddList() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddListFormData)
+
+		newList := models.FraudListModel{}
+		err := utils.BindValidate(c, data, &newList)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Le téléphone est enregistré au format des transactions
+		newList.Value = strings.TrimSpace(newList.Value)
+		if newList.Kind == models.FRAUD_LIST_PHONE {
+			newList.Value, err = utils.NormalizePhone(newList.Value)
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newList.CreatedById = loginUser.ID
+
+		result := db.Create(&newList)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			errMsg := result.Error.Error()
+			if strings.Contains(errMsg, "duplicate key value violates") {
+				errMsg = "valeur déjà bloquée"
+			}
+
+			return resp.SendError(c, errMsg, models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			List models.FraudListModel `json:"list"`
+		}
+
+		resp.SetData(resData{
+			List: newList,
+		})
+
+		return resp.Send(c)
+	}
+}
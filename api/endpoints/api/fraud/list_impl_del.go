@@ -0,0 +1,76 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudListAPIDeleteSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		List    models.FraudListModel `json:"list"`
+		Deleted bool                  `json:"deleted"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// DeleteList
+// @Summary      	Unblock a phone or IP
+// @Description  	Retrait d'un téléphone ou d'une adresse IP de la liste de blocage
+// @Tags         	Fraud
+// @Product       	json
+// @response      	200 {object} ResFraudListAPIDeleteSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/lists/:list_id [delete]
+func (s *FraudApiRessource) DeleteList() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		list := models.FraudListModel{}
+		err := getOne(c.Param("list_id"), &list)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if list.ID == "" {
+			err := fmt.Errorf("élément de liste inexistant")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Delete(&list)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			List    models.FraudListModel `json:"list"`
+			Deleted bool                  `json:"deleted"`
+		}
+
+		resp.SetData(resData{
+			List:    list,
+			Deleted: true,
+		})
+
+		return resp.Send(c)
+	}
+}
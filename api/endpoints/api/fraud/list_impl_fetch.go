@@ -0,0 +1,88 @@
+package fraud
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudListAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Lists      []models.FraudListModel `json:"lists"`
+		Pagination models.PaginationModel  `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchLists
+// @Summary      	Fetch blocked phones and IPs paginate
+// @Description  	Récuperation des téléphones et adresses IP bloqués paginer
+// @Tags         	Fraud
+// @Product       	json
+// @Param        	filter-kind query string false "PHONE ou IP"
+// @response      	200 {object} ResFraudListAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/lists [get]
+func (s *FraudApiRessource) FetchLists() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := db.Model(&models.FraudListModel{})
+
+		if len(params.Query) > 0 {
+			reqDb = reqDb.Where("value LIKE ?", "%"+params.Query+"%")
+		}
+
+		if filter := c.QueryParam("filter-kind"); filter != "" {
+			reqDb = reqDb.Where("kind = ?", strings.ToUpper(filter))
+		}
+
+		lists := []models.FraudListModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &lists, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Lists      []models.FraudListModel `json:"lists"`
+			Pagination models.PaginationModel  `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			lists, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(lists, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Lists:      lists,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,81 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *FraudApiRessource) GetRuleOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			rule := models.FraudRuleModel{}
+			err := getOne(c.Param("rule_id"), &rule)
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if rule.ID == "" {
+				err := fmt.Errorf("règle inexistante")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("FRAUD_RULE", &rule)
+
+			return next(c)
+		}
+	}
+}
+
+func (s *FraudApiRessource) GetReviewOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			review := models.FraudReviewModel{}
+			err := getOne(c.Param("review_id"), &review, "Transaction")
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if review.ID == "" {
+				err := fmt.Errorf("revue inexistante")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("FRAUD_REVIEW", &review)
+
+			return next(c)
+		}
+	}
+}
+
+// getOne chargement d'un élément par son identifiant, l'élément reste vide s'il n'existe pas
+func getOne(itemId string, item interface{}, preloads ...string) error {
+	id, err := uuid.FromString(itemId)
+	if err != nil {
+		return fmt.Errorf("identifiant invalide")
+	}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	for _, preload := range preloads {
+		db = db.Preload(preload)
+	}
+
+	return db.Where("id = ?", id.String()).Limit(1).Find(item).Error
+}
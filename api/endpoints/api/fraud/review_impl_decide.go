@@ -0,0 +1,121 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResFraudReviewAPIDecideSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Review models.FraudReviewModel `json:"review"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type DecideFormData struct {
+	Comment string `json:"comment" form:"comment" xml:"comment" validate:"omitempty"` // Motif de la décision
+}
+
+// Approve
+// @Summary      	Approve flagged transaction
+// @Description  	Approbation d'une transaction signalée, la transaction est envoyée au provider
+// @Tags         	Fraud
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData DecideFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResFraudReviewAPIDecideSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/reviews/:review_id/approve [post]
+func (s *FraudApiRessource) Approve() echo.HandlerFunc {
+	return s.decide(models.REVIEW_APPROVED)
+}
+
+// Reject
+// @Summary      	Reject flagged transaction
+// @Description  	Rejet d'une transaction signalée, la transaction passe en échec
+// @Tags         	Fraud
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData DecideFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResFraudReviewAPIDecideSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/reviews/:review_id/reject [post]
+func (s *FraudApiRessource) Reject() echo.HandlerFunc {
+	return s.decide(models.REVIEW_REJECTED)
+}
+
+func (s *FraudApiRessource) decide(state string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		review, ok := c.Get("FRAUD_REVIEW").(*models.FraudReviewModel)
+		if !ok {
+			err := fmt.Errorf("revue non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(DecideFormData)
+		decideForm := DecideFormData{}
+		err := utils.BindValidate(c, data, &decideForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return review.Decide(tx, state, loginUser.ID, decideForm.Comment)
+		})
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// La transaction libérée est envoyée au provider, un refus est visible dans son état
+		transaction := review.Transaction
+		if state == models.REVIEW_APPROVED && !transaction.IsFinal() {
+			provider := models.ProviderModel{}
+			result := db.Where("id = ?", transaction.ProviderId).First(&provider)
+			if result.Error != nil {
+				log.Error().Err(result.Error).Msgf("")
+			} else if err := utils.ProviderStartTransaction(db, provider, transaction); err != nil {
+				log.Error().Err(err).Msgf("")
+			}
+		}
+
+		type resData struct {
+			Review models.FraudReviewModel `json:"review"`
+		}
+
+		resp.SetData(resData{
+			Review: *review,
+		})
+
+		return resp.Send(c)
+	}
+}
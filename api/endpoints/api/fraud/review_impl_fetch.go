@@ -0,0 +1,92 @@
+package fraud
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudReviewAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Reviews    []models.FraudReviewModel `json:"reviews"`
+		Pagination models.PaginationModel    `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchReviews
+// @Summary      	Fetch fraud review queue paginate
+// @Description  	Récuperation de la file des transactions signalées paginer
+// @Tags         	Fraud
+// @Product       	json
+// @Param        	filter-service query string false "Identifiant de la boutique"
+// @Param        	filter-state query string false "Etats séparés par virgule (PENDING,APPROVED,REJECTED), PENDING par défaut"
+// @response      	200 {object} ResFraudReviewAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/reviews [get]
+func (s *FraudApiRessource) FetchReviews() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := db.Model(&models.FraudReviewModel{})
+
+		if filter := c.QueryParam("filter-service"); filter != "" {
+			reqDb = reqDb.Where("service_id = ?", filter)
+		}
+
+		states := []string{models.REVIEW_PENDING}
+		if filter := c.QueryParam("filter-state"); filter != "" {
+			states = strings.Split(strings.ToUpper(filter), ",")
+		}
+
+		reqDb = reqDb.Where("state IN (?)", states)
+
+		reviews := []models.FraudReviewModel{}
+
+		var count int64
+		err = fetchExec(reqDb.Preload("Transaction"), &reviews, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Reviews    []models.FraudReviewModel `json:"reviews"`
+			Pagination models.PaginationModel    `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			reviews, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(reviews, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Reviews:    reviews,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,55 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResFraudReviewAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Review models.FraudReviewModel `json:"review"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetReview
+// @Summary      	Get fraud review
+// @Description  	Récuperation d'une revue anti-fraude et de sa transaction
+// @Tags         	Fraud
+// @Product       	json
+// @response      	200 {object} ResFraudReviewAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/reviews/:review_id [get]
+func (s *FraudApiRessource) GetReview() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		review, ok := c.Get("FRAUD_REVIEW").(*models.FraudReviewModel)
+		if !ok {
+			err := fmt.Errorf("revue non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Review models.FraudReviewModel `json:"review"`
+		}
+
+		resp.SetData(resData{
+			Review: *review,
+		})
+
+		return resp.Send(c)
+	}
+}
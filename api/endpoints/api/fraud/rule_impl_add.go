@@ -0,0 +1,100 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudRuleAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rule models.FraudRuleModel `json:"rule"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddRuleFormData struct {
+	Name        string `json:"name" form:"name" xml:"name" validate:"required"`                                                                       // Nom de la règle
+	Description string `json:"description" form:"description" xml:"description" validate:"omitempty"`                                                 // Description de la règle
+	Kind        string `json:"kind" form:"kind" xml:"kind" validate:"required,oneof=PHONE_SERVICES NEAR_LIMIT AMOUNT_ABOVE BLOCKED_PHONE BLOCKED_IP"` // Type de règle
+
+	Threshold     int64   `json:"threshold" form:"threshold" xml:"threshold" validate:"gte=0"`                                // Nombre d'occurrences toléré
+	WindowMinutes int     `json:"window_minutes" form:"window_minutes" xml:"window_minutes" validate:"gte=0"`                 // Fenêtre d'observation en minutes
+	Amount        float64 `json:"amount" form:"amount" xml:"amount" validate:"gte=0"`                                         // Montant de référence
+	Currency      string  `json:"currency" form:"currency" xml:"currency" validate:"required_with=Amount,omitempty,currency"` // Devise du montant de référence (ex: XOF)
+	Ratio         float64 `json:"ratio" form:"ratio" xml:"ratio" validate:"gte=0,lte=1"`                                      // Part du montant de référence (NEAR_LIMIT)
+
+	Score  int    `json:"score" form:"score" xml:"score" validate:"gte=0"`                                // Score ajouté si la règle est déclenchée
+	Action string `json:"action" form:"action" xml:"action" validate:"required,oneof=ALLOW REVIEW BLOCK"` // ALLOW, REVIEW, BLOCK
+}
+
+// AddRule
+// @Summary      	Add fraud rule
+// @Description  	Création d'une règle anti-fraude
+// @Tags         	Fraud
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddRuleFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResFraudRuleAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/rules [post]
+func (s *FraudApiRessource) AddRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddRuleFormData)
+
+		newRule := models.FraudRuleModel{}
+		err := utils.BindValidate(c, data, &newRule)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newRule.Currency = strings.ToUpper(newRule.Currency)
+		newRule.Active = true
+		newRule.CreatedById = loginUser.ID
+
+		result := db.Create(&newRule)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Rule models.FraudRuleModel `json:"rule"`
+		}
+
+		resp.SetData(resData{
+			Rule: newRule,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,71 @@
+package fraud
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudRuleAPIDeleteSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rule    models.FraudRuleModel `json:"rule"`
+		Deleted bool                  `json:"deleted"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// DeleteRule
+// @Summary      	Delete fraud rule
+// @Description  	Suppression d'une règle anti-fraude
+// @Tags         	Fraud
+// @Product       	json
+// @response      	200 {object} ResFraudRuleAPIDeleteSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/rules/:rule_id [delete]
+func (s *FraudApiRessource) DeleteRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		rule, ok := c.Get("FRAUD_RULE").(*models.FraudRuleModel)
+		if !ok {
+			err := fmt.Errorf("règle non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Delete(rule)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Rule    models.FraudRuleModel `json:"rule"`
+			Deleted bool                  `json:"deleted"`
+		}
+
+		resp.SetData(resData{
+			Rule:    *rule,
+			Deleted: true,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,159 @@
+package fraud
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResFraudRuleAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rules      []models.FraudRuleModel `json:"rules"`
+		Pagination models.PaginationModel  `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchRules
+// @Summary      	Fetch fraud rules paginate
+// @Description  	Récuperation des règles anti-fraude paginer
+// @Tags         	Fraud
+// @Product       	json
+// @Param        	filter-kind query string false "Type de règle"
+// @response      	200 {object} ResFraudRuleAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/rules [get]
+func (s *FraudApiRessource) FetchRules() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := db.Model(&models.FraudRuleModel{})
+
+		if len(params.Query) > 0 {
+			reqDb = reqDb.Where("lower(name) LIKE ?", strings.ToLower("%"+params.Query+"%"))
+		}
+
+		if filter := c.QueryParam("filter-kind"); filter != "" {
+			reqDb = reqDb.Where("kind = ?", strings.ToUpper(filter))
+		}
+
+		rules := []models.FraudRuleModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &rules, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Rules      []models.FraudRuleModel `json:"rules"`
+			Pagination models.PaginationModel  `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			rules, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(rules, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Rules:      rules,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchExec[T any](reqDb *gorm.DB, items *[]T, params fetchParams, count *int64) error {
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(items)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	Orders    []string
+	Query     string
+	Limit     int
+	Offset    int
+	Cursor    *models.Cursor
+	WithCount bool
+}
+
+func fetchParamsFromContext(c echo.Context) fetchParams {
+	params := fetchParams{}
+
+	params.Limit, _ = c.Get("LIMIT").(int)
+	params.Offset, _ = c.Get("OFFSET").(int)
+	params.Query, _ = c.Get("QUERY").(string)
+	params.Orders, _ = c.Get("ORDERS").([]string)
+	params.Cursor, _ = c.Get("CURSOR").(*models.Cursor)
+	params.WithCount, _ = c.Get("WITH_COUNT").(bool)
+
+	return params
+}
+
+func (params fetchParams) pagination(count *int64) models.PaginationModel {
+	pagination := models.PaginationModel{
+		Limit:  params.Limit,
+		Offset: params.Offset,
+		Query:  params.Query,
+	}
+
+	if params.WithCount {
+		pagination.Count = count
+	}
+
+	if params.Cursor != nil {
+		pagination.Offset = 0
+	}
+
+	return pagination
+}
@@ -0,0 +1,122 @@
+package fraud
+
+import (
+	"encoding/json"
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResFraudRuleAPIUpdateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rule models.FraudRuleModel `json:"rule"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type UpdateRuleFormData struct {
+	Name        *string `json:"name,omitempty" form:"name" xml:"name" validate:"omitempty"`                      // Nom de la règle
+	Description *string `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"` // Description de la règle
+	Active      *bool   `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"`                // Activation / désactivation
+
+	Threshold     *int64   `json:"threshold,omitempty" form:"threshold" xml:"threshold" validate:"omitempty,gte=0"`                // Nombre d'occurrences toléré
+	WindowMinutes *int     `json:"window_minutes,omitempty" form:"window_minutes" xml:"window_minutes" validate:"omitempty,gte=0"` // Fenêtre d'observation en minutes
+	Amount        *float64 `json:"amount,omitempty" form:"amount" xml:"amount" validate:"omitempty,gte=0"`                         // Montant de référence
+	Currency      *string  `json:"currency,omitempty" form:"currency" xml:"currency" validate:"omitempty,currency"`                // Devise du montant de référence
+	Ratio         *float64 `json:"ratio,omitempty" form:"ratio" xml:"ratio" validate:"omitempty,gte=0,lte=1"`                      // Part du montant de référence
+
+	Score  *int    `json:"score,omitempty" form:"score" xml:"score" validate:"omitempty,gte=0"`                       // Score ajouté si la règle est déclenchée
+	Action *string `json:"action,omitempty" form:"action" xml:"action" validate:"omitempty,oneof=ALLOW REVIEW BLOCK"` // ALLOW, REVIEW, BLOCK
+}
+
+// UpdateRule
+// @Summary      	Update fraud rule
+// @Description  	Mise à jour ou désactivation d'une règle anti-fraude
+// @Tags         	Fraud
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData UpdateRuleFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResFraudRuleAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/fraud/rules/:rule_id [put]
+func (s *FraudApiRessource) UpdateRule() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		rule, ok := c.Get("FRAUD_RULE").(*models.FraudRuleModel)
+		if !ok {
+			err := fmt.Errorf("règle non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(UpdateRuleFormData)
+		updateForm := UpdateRuleFormData{}
+		err := utils.BindValidate(c, data, &updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Seuls les champs transmis sont modifiés
+		ruleFormJson, err := json.Marshal(updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = json.Unmarshal(ruleFormJson, rule)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		rule.Currency = strings.ToUpper(rule.Currency)
+
+		result := db.Model(&models.FraudRuleModel{}).
+			Where("id = ?", rule.ID).
+			Updates(map[string]interface{}{
+				"name":           rule.Name,
+				"description":    rule.Description,
+				"active":         rule.Active,
+				"threshold":      rule.Threshold,
+				"window_minutes": rule.WindowMinutes,
+				"amount":         rule.Amount,
+				"currency":       rule.Currency,
+				"ratio":          rule.Ratio,
+				"score":          rule.Score,
+				"action":         rule.Action,
+			})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Rule models.FraudRuleModel `json:"rule"`
+		}
+
+		resp.SetData(resData{
+			Rule: *rule,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,35 @@
+package installments
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InstallmentApiRessource struct {
+	*models.InstallmentPlanModel
+}
+
+// Colonnes autorisées pour le tri
+var installmentSorts = []string{"created_at", "updated_at", "reference_id", "total_due", "paid_amount", "status"}
+
+func AttachAPI(server *echo.Group) {
+	installmentApi := &InstallmentApiRessource{&models.InstallmentPlanModel{}}
+
+	// Installment Plan
+	installmentApiService := server.Group("/installments")
+	{
+		// Fetch
+		installmentApiService.GET("/", installmentApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(installmentSorts...))
+
+		// Add Installment Plan
+		installmentApiService.POST("/", installmentApi.Add(), middlewares.GrantMid())
+
+		installmentOneApiService := installmentApiService.Group("/:id", middlewares.GrantMid(), installmentApi.GetOnMid())
+		{
+			// Get Installment Plan Info
+			installmentOneApiService.GET("/", installmentApi.GetInfo(), middlewares.PermMid(models.PERM_INSTALLMENTS_READ))
+		}
+	}
+}
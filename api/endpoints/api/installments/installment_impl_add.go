@@ -0,0 +1,131 @@
+package installments
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResInstallmentAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Installment models.InstallmentPlanModel `json:"installment"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type DueFormData struct {
+	DueDate time.Time `json:"due_date" form:"due_date" xml:"due_date" validate:"required"` // Date limite de l'échéance (RFC3339)
+	Amount  float64   `json:"amount" form:"amount" xml:"amount" validate:"required,gt=0"`  // Montant de l'échéance
+}
+
+type AddFormData struct {
+	ServiceId   string        `json:"service_id" form:"service_id" xml:"service_id" validate:"required"`       // Identifiant de la boutique
+	ReferenceId string        `json:"reference_id" form:"reference_id" xml:"reference_id" validate:"required"` // Id facture, réference des transactions de paiement
+	Description string        `json:"description" form:"description" xml:"description" validate:"omitempty"`   // Libellé de l'échéancier
+	ModeLive    bool          `json:"mode_live" form:"mode_live" xml:"mode_live" validate:"-"`                 // Paiements réels ou sandbox
+	Currency    string        `json:"currency" form:"currency" xml:"currency" validate:"required,currency"`    // Devise (ex: XOF)
+	TotalDue    float64       `json:"total_due" form:"total_due" xml:"total_due" validate:"required,gt=0"`     // Total dû
+	Dues        []DueFormData `json:"dues" form:"dues" xml:"dues" validate:"required,min=1,dive"`              // Echéances, leur somme doit être égale au total dû
+}
+
+// Add
+// @Summary      	Add new installment plan
+// @Description  	Création d'un échéancier de paiement pour une réference de facture
+// @Tags         	Installments
+// @accept 			json,xml
+// @Product       	json
+// @Param        	data body AddFormData  true  "Contenu de la requete"
+// @response      	200 {object} ResInstallmentAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/installments/ [post]
+func (s *InstallmentApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newInstallment := models.InstallmentPlanModel{}
+		err := utils.BindValidate(c, data, &newInstallment)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Echéances par date
+		sort.SliceStable(newInstallment.Dues, func(i, j int) bool {
+			return newInstallment.Dues[i].DueDate.Before(newInstallment.Dues[j].DueDate)
+		})
+
+		if err := newInstallment.CheckSchedule(); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := checkServicePerm(db, newInstallment.ServiceId, claims, models.PERM_INSTALLMENTS_CREATE)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// La boutique doit exister
+		service := models.ServiceModel{}
+		result := db.Model(&service).Where("id = ?", newInstallment.ServiceId).First(&service)
+		if result.Error != nil {
+			err := fmt.Errorf("boutique inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newInstallment.Currency = strings.ToUpper(newInstallment.Currency)
+		newInstallment.CreatedById = loginUser.ID
+		newInstallment.Status = newInstallment.ComputeStatus(time.Now())
+
+		result = db.Create(&newInstallment)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			errMsg := result.Error.Error()
+			if strings.Contains(errMsg, "duplicate key value violates") {
+				errMsg = "un échéancier existe déjà pour cette réference"
+			}
+
+			return resp.SendError(c, errMsg, models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Installment models.InstallmentPlanModel `json:"installment"`
+		}
+
+		resp.SetData(resData{
+			Installment: newInstallment,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,200 @@
+package installments
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResInstallmentAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Installments []models.InstallmentPlanModel `json:"installments"`
+		Pagination   models.PaginationModel        `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all installment plans paginate
+// @Description  	Récuperation des échéanciers paginer
+// @Tags         	Installments
+// @Product       	json
+// @Param        	filter-service query string false "Identifiant de la boutique"
+// @Param        	filter-status query string false "Statuts séparés par virgule (PENDING,PARTIALLY_PAID,PAID,OVERDUE)"
+// @response      	200 {object} ResInstallmentAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/installments/ [get]
+func (s *InstallmentApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser := models.UserModel{}
+		loginUser.AuthId = claims["sub"].(string)
+
+		result := db.
+			Scopes(models.PreloadGrants).
+			Where(&loginUser).First(&loginUser)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			if strings.Contains(result.Error.Error(), "record not found") {
+				err := fmt.Errorf("utilisateur non reconnu")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		installments := []models.InstallmentPlanModel{}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		query, _ := c.Get("QUERY").(string)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		var count int64
+		err = fetchExec(
+			db.Model(&models.InstallmentPlanModel{}),
+			loginUser,
+			&installments,
+			fetchParams{
+				FilterService: c.QueryParam("filter-service"),
+				FilterStatus:  strings.ToUpper(c.QueryParam("filter-status")),
+				Orders:        orders,
+				Query:         query,
+				Limit:         limit,
+				Offset:        offset,
+				Cursor:        cursor,
+				WithCount:     withCount,
+			},
+			&count,
+		)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Installments []models.InstallmentPlanModel `json:"installments"`
+			Pagination   models.PaginationModel        `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			installments, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(installments, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Installments: installments,
+			Pagination:   pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchExec(
+	reqDb *gorm.DB,
+	loginUser models.UserModel,
+	installments *[]models.InstallmentPlanModel,
+	params fetchParams,
+	count *int64,
+) error {
+	// Restreindre aux boutiques de l'utilisateur
+	if serviceIds, all := loginUser.ServicesWith(models.PERM_INSTALLMENTS_READ); !all {
+		reqDb = reqDb.Where("service_id IN (?)", serviceIds)
+	}
+
+	// Query
+	if len(params.Query) > 0 {
+		reqDb = reqDb.Where("lower(reference_id) LIKE ? OR lower(description) LIKE ?", strings.ToLower("%"+params.Query+"%"), strings.ToLower("%"+params.Query+"%"))
+	}
+
+	if len(params.FilterService) > 0 {
+		reqDb = reqDb.Where("service_id = ?", params.FilterService)
+	}
+
+	if len(params.FilterStatus) > 0 {
+		reqDb = reqDb.Where("status IN (?)", strings.Split(params.FilterStatus, ","))
+	}
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(installments)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	FilterService string
+	FilterStatus  string
+	Orders        []string
+	Query         string
+	Limit         int
+	Offset        int
+	Cursor        *models.Cursor
+	WithCount     bool
+}
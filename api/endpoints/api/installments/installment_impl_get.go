@@ -0,0 +1,81 @@
+package installments
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResInstallmentAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Installment  models.InstallmentPlanModel `json:"installment"`
+		Outstanding  float64                     `json:"outstanding"`
+		Transactions []models.TransactionModel   `json:"transactions"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get installment plan info
+// @Description  	Récuperation d'un échéancier, de son solde restant dû et des transactions associées
+// @Tags         	Installments
+// @Product       	json
+// @response      	200 {object} ResInstallmentAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/installments/:id/ [get]
+func (s *InstallmentApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		installment, ok := c.Get("INSTALLMENT").(*models.InstallmentPlanModel)
+		if !ok {
+			err := fmt.Errorf("échéancier non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		transactions := []models.TransactionModel{}
+		result := db.
+			Where("installment_plan_id = ?", installment.ID).
+			Order("created_at asc").
+			Find(&transactions)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		// Statut à jour entre deux passages du job
+		installment.Status = installment.ComputeStatus(time.Now())
+
+		type resData struct {
+			Installment  models.InstallmentPlanModel `json:"installment"`
+			Outstanding  float64                     `json:"outstanding"`
+			Transactions []models.TransactionModel   `json:"transactions"`
+		}
+
+		resp.SetData(resData{
+			Installment:  *installment,
+			Outstanding:  installment.Outstanding(),
+			Transactions: transactions,
+		})
+
+		return resp.Send(c)
+	}
+}
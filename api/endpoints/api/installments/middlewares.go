@@ -0,0 +1,113 @@
+package installments
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (s *InstallmentApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			_, ok = c.Get("JWT_CLAIMS").(jwt.MapClaims)
+			if !ok {
+				err := fmt.Errorf("authentification obligatoire")
+				resp.SetStatus(http.StatusUnauthorized)
+				return resp.SendError(c, err.Error(), models.ResErrorAPI{
+					models.ErrorAPI{
+						Code:    "400",
+						Message: err.Error(),
+						Data:    err,
+					},
+				})
+			}
+
+			installment, err := getInstallment(c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if installment == nil {
+				err := fmt.Errorf("échéancier inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("INSTALLMENT", installment)
+			c.Set("SERVICE_ID", installment.ServiceId)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func getInstallment(installmentId string) (*models.InstallmentPlanModel, error) {
+	installment := models.InstallmentPlanModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := uuid.FromString(installmentId); err == nil {
+		db = db.Or("id = ?", id.String())
+	} else {
+		err := fmt.Errorf("identifiant échéancier invalide")
+		return nil, err
+	}
+
+	result := db.
+		Preload("Dues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("due_date asc")
+		}).
+		Where(&installment).
+		First(&installment)
+	if result.Error != nil {
+		return nil, err
+	}
+
+	return &installment, nil
+}
+
+// checkServicePerm contrôle des droits de l'utilisateur sur la boutique
+func checkServicePerm(db *gorm.DB, serviceId string, claims jwt.MapClaims, perm models.Permission) (*models.UserModel, error) {
+	loginUser := models.UserModel{}
+	loginUser.AuthId = claims["sub"].(string)
+
+	result := db.
+		Scopes(models.PreloadGrants).
+		Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msgf("")
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	if err := loginUser.Authorize(perm, serviceId); err != nil {
+		return nil, err
+	}
+
+	return &loginUser, nil
+}
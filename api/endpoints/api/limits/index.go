@@ -0,0 +1,44 @@
+package limits
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LimitApiRessource struct {
+	*models.LimitModel
+}
+
+// Colonnes autorisées pour le tri
+var limitSorts = []string{"created_at", "updated_at", "scope", "scope_id"}
+
+func AttachAPI(server *echo.Group) {
+	limitApi := &LimitApiRessource{&models.LimitModel{}}
+
+	// Limit
+	limitApiService := server.Group("/limits")
+	{
+		// Fetch
+		limitApiService.GET("/", limitApi.Fetch(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_LIMITS), models.PaginationMid(limitSorts...))
+
+		// Add Limit
+		limitApiService.POST("/", limitApi.Add(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_LIMITS))
+
+		limitOneApiService := limitApiService.Group("/:id", middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_LIMITS), limitApi.GetOnMid())
+		{
+			// Get Limit Info
+			limitOneApiService.GET("/", limitApi.GetInfo())
+
+			// Update Limit Info
+			limitOneApiService.PUT("/", limitApi.UpdateInfo())
+
+			// Delete Limit
+			limitOneApiService.DELETE("/", limitApi.Delete())
+
+			// Current consumption
+			limitOneApiService.GET("/usage", limitApi.Usage())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package limits
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResLimitAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limit models.LimitModel `json:"limit"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	Scope       string `json:"scope" form:"scope" xml:"scope" validate:"required,oneof=SERVICE PROVIDER PAYER"` // SERVICE, PROVIDER, PAYER
+	ScopeId     string `json:"scope_id" form:"scope_id" xml:"scope_id" validate:"omitempty"`                    // Boutique, provider ou téléphone, vide pour tous
+	ModeLive    bool   `json:"mode_live" form:"mode_live" xml:"mode_live" validate:"-"`                         // Limite des transactions réelles
+	Description string `json:"description" form:"description" xml:"description" validate:"omitempty"`           // Description de la limite
+	Currency    string `json:"currency" form:"currency" xml:"currency" validate:"required,currency"`            // Devise des plafonds (ex: XOF)
+
+	MaxAmount     float64 `json:"max_amount" form:"max_amount" xml:"max_amount" validate:"gte=0"`             // Montant maximum par transaction
+	DailyAmount   float64 `json:"daily_amount" form:"daily_amount" xml:"daily_amount" validate:"gte=0"`       // Total journalier
+	MonthlyAmount float64 `json:"monthly_amount" form:"monthly_amount" xml:"monthly_amount" validate:"gte=0"` // Total mensuel
+	HourlyCount   int64   `json:"hourly_count" form:"hourly_count" xml:"hourly_count" validate:"gte=0"`       // Nombre de transactions par heure
+}
+
+// Add
+// @Summary      	Add new limit
+// @Description  	Création d'une limite sur les transactions
+// @Tags         	Limits
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResLimitAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/ [post]
+func (s *LimitApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newLimit := models.LimitModel{}
+		err := utils.BindValidate(c, data, &newLimit)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Le téléphone est enregistré au format des transactions
+		if newLimit.Scope == models.LIMIT_SCOPE_PAYER && newLimit.ScopeId != "" {
+			newLimit.ScopeId, err = utils.NormalizePhone(newLimit.ScopeId)
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+		}
+
+		newLimit.Currency = strings.ToUpper(newLimit.Currency)
+		newLimit.Active = true
+		newLimit.CreatedById = loginUser.ID
+
+		result := db.Create(&newLimit)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Limit models.LimitModel `json:"limit"`
+		}
+
+		resp.SetData(resData{
+			Limit: newLimit,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,71 @@
+package limits
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResLimitAPIDeleteSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limit   models.LimitModel `json:"limit"`
+		Deleted bool              `json:"deleted"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Delete
+// @Summary      	Delete limit
+// @Description  	Suppression d'une limite
+// @Tags         	Limits
+// @Product       	json
+// @response      	200 {object} ResLimitAPIDeleteSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/:id/ [delete]
+func (s *LimitApiRessource) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		limit, ok := c.Get("LIMIT_RULE").(*models.LimitModel)
+		if !ok {
+			err := fmt.Errorf("limite non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Delete(limit)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Limit   models.LimitModel `json:"limit"`
+			Deleted bool              `json:"deleted"`
+		}
+
+		resp.SetData(resData{
+			Limit:   *limit,
+			Deleted: true,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,177 @@
+package limits
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResLimitAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limits     []models.LimitModel    `json:"limits"`
+		Pagination models.PaginationModel `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all limits paginate
+// @Description  	Récuperation des limites paginer
+// @Tags         	Limits
+// @Product       	json
+// @Param        	filter-scope query string false "SERVICE, PROVIDER ou PAYER"
+// @Param        	filter-scope-id query string false "Boutique, provider ou téléphone concerné"
+// @response      	200 {object} ResLimitAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/ [get]
+func (s *LimitApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limits := []models.LimitModel{}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		query, _ := c.Get("QUERY").(string)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.LimitModel{})
+
+		var count int64
+		err = fetchExec(
+			reqDb,
+			&limits,
+			fetchParams{
+				FilterScope:   strings.ToUpper(c.QueryParam("filter-scope")),
+				FilterScopeId: c.QueryParam("filter-scope-id"),
+				Orders:        orders,
+				Query:         query,
+				Limit:         limit,
+				Offset:        offset,
+				Cursor:        cursor,
+				WithCount:     withCount,
+			},
+			&count,
+		)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Limits     []models.LimitModel    `json:"limits"`
+			Pagination models.PaginationModel `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			limits, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(limits, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Limits:     limits,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchOrder(reqDb *gorm.DB, orders []string) *gorm.DB {
+	if len(orders) > 0 {
+		for _, order := range orders {
+			reqDb = reqDb.Order(order)
+		}
+	}
+
+	return reqDb
+}
+
+func fetchExec(
+	reqDb *gorm.DB,
+	limits *[]models.LimitModel,
+	params fetchParams,
+	count *int64,
+) error {
+	// Query
+	if len(params.Query) > 0 {
+		reqDb = reqDb.Where("lower(description) LIKE ?", strings.ToLower("%"+params.Query+"%"))
+	}
+
+	if len(params.FilterScope) > 0 {
+		reqDb = reqDb.Where("scope = ?", params.FilterScope)
+	}
+
+	if len(params.FilterScopeId) > 0 {
+		reqDb = reqDb.Where("scope_id = ?", params.FilterScopeId)
+	}
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(limits)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	FilterScope   string
+	FilterScopeId string
+	Orders        []string
+	Query         string
+	Limit         int
+	Offset        int
+	Cursor        *models.Cursor
+	WithCount     bool
+}
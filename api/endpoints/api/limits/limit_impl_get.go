@@ -0,0 +1,55 @@
+package limits
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResLimitAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limit models.LimitModel `json:"limit"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get limit
+// @Description  	Récuperation d'une limite
+// @Tags         	Limits
+// @Product       	json
+// @response      	200 {object} ResLimitAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/:id/ [get]
+func (s *LimitApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		limit, ok := c.Get("LIMIT_RULE").(*models.LimitModel)
+		if !ok {
+			err := fmt.Errorf("limite non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Limit models.LimitModel `json:"limit"`
+		}
+
+		resp.SetData(resData{
+			Limit: *limit,
+		})
+
+		return resp.Send(c)
+	}
+}
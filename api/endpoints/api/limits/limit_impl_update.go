@@ -0,0 +1,115 @@
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResLimitAPIUpdateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limit models.LimitModel `json:"limit"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type UpdateFormData struct {
+	Active      *bool   `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"`                // Activation / désactivation de la limite
+	Description *string `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"` // Description de la limite
+	Currency    *string `json:"currency,omitempty" form:"currency" xml:"currency" validate:"omitempty,currency"` // Devise des plafonds
+
+	MaxAmount     *float64 `json:"max_amount,omitempty" form:"max_amount" xml:"max_amount" validate:"omitempty,gte=0"`             // Montant maximum par transaction
+	DailyAmount   *float64 `json:"daily_amount,omitempty" form:"daily_amount" xml:"daily_amount" validate:"omitempty,gte=0"`       // Total journalier
+	MonthlyAmount *float64 `json:"monthly_amount,omitempty" form:"monthly_amount" xml:"monthly_amount" validate:"omitempty,gte=0"` // Total mensuel
+	HourlyCount   *int64   `json:"hourly_count,omitempty" form:"hourly_count" xml:"hourly_count" validate:"omitempty,gte=0"`       // Nombre de transactions par heure
+}
+
+// UpdateInfo
+// @Summary      	Update limit
+// @Description  	Mise à jour ou désactivation d'une limite
+// @Tags         	Limits
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData UpdateFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResLimitAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/:id/ [put]
+func (s *LimitApiRessource) UpdateInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		limit, ok := c.Get("LIMIT_RULE").(*models.LimitModel)
+		if !ok {
+			err := fmt.Errorf("limite non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(UpdateFormData)
+		updateForm := UpdateFormData{}
+		err := utils.BindValidate(c, data, &updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Seuls les champs transmis sont modifiés
+		limitFormJson, err := json.Marshal(updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = json.Unmarshal(limitFormJson, limit)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limit.Currency = strings.ToUpper(limit.Currency)
+
+		result := db.Model(&models.LimitModel{}).
+			Where("id = ?", limit.ID).
+			Updates(map[string]interface{}{
+				"active":         limit.Active,
+				"description":    limit.Description,
+				"currency":       limit.Currency,
+				"max_amount":     limit.MaxAmount,
+				"daily_amount":   limit.DailyAmount,
+				"monthly_amount": limit.MonthlyAmount,
+				"hourly_count":   limit.HourlyCount,
+			})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Limit models.LimitModel `json:"limit"`
+		}
+
+		resp.SetData(resData{
+			Limit: *limit,
+		})
+
+		return resp.Send(c)
+	}
+}
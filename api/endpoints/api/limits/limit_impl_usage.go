@@ -0,0 +1,90 @@
+package limits
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResLimitAPIUsageSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Limit models.LimitModel `json:"limit"`
+		Usage models.LimitUsage `json:"usage"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Usage
+// @Summary      	Get limit usage
+// @Description  	Consommation courante d'une limite (jour, mois, dernière heure)
+// @Tags         	Limits
+// @Product       	json
+// @Param        	scope_id query string false "Boutique, provider ou téléphone, obligatoire pour une limite générale"
+// @Param        	currency query string false "Devise de la consommation, obligatoire pour une limite sans devise"
+// @response      	200 {object} ResLimitAPIUsageSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/limits/:id/usage [get]
+func (s *LimitApiRessource) Usage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		limit, ok := c.Get("LIMIT_RULE").(*models.LimitModel)
+		if !ok {
+			err := fmt.Errorf("limite non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		scopeId := limit.ScopeId
+		if scopeId == "" {
+			scopeId = c.QueryParam("scope_id")
+		}
+
+		if scopeId == "" {
+			err := fmt.Errorf("scope_id obligatoire pour une limite générale")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		currency := limit.Currency
+		if currency == "" {
+			var err error
+			currency, err = models.NormalizeCurrency(c.QueryParam("currency"))
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		usage, err := limit.Usage(db, scopeId, currency, time.Now())
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Limit models.LimitModel `json:"limit"`
+			Usage models.LimitUsage `json:"usage"`
+		}
+
+		resp.SetData(resData{
+			Limit: *limit,
+			Usage: *usage,
+		})
+
+		return resp.Send(c)
+	}
+}
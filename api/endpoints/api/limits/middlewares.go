@@ -0,0 +1,68 @@
+package limits
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *LimitApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			limit, err := getLimit(c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if limit == nil {
+				err := fmt.Errorf("limite inexistante")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("LIMIT_RULE", limit)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func getLimit(limitId string) (*models.LimitModel, error) {
+	limit := models.LimitModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.FromString(limitId)
+	if err != nil {
+		return nil, fmt.Errorf("identifiant limite invalide")
+	}
+
+	result := db.Where("id = ?", id.String()).Limit(1).Find(&limit)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &limit, nil
+}
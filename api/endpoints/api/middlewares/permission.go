@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// PermMid permission requise par la route, placé après GrantMid.
+// La permission est contrôlée sur la boutique SERVICE_ID posée par le middleware de la ressource,
+// sur toute la plateforme sinon. L'utilisateur est disponible ensuite dans LOGIN_USER.
+func PermMid(perm models.Permission) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+			if !ok {
+				err := fmt.Errorf("authentification obligatoire")
+				resp.SetStatus(http.StatusUnauthorized)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			loginUser, err := LoginUser(claims)
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			serviceId, _ := c.Get("SERVICE_ID").(string)
+
+			if err := loginUser.Authorize(perm, serviceId); err != nil {
+				resp.SetStatus(http.StatusForbidden)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("LOGIN_USER", loginUser)
+
+			return next(c)
+		}
+	}
+}
+
+// LoginUser utilisateur connecté avec les permissions nécessaires au contrôle des droits
+func LoginUser(claims jwt.MapClaims) (*models.UserModel, error) {
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	loginUser := models.UserModel{}
+	loginUser.AuthId, _ = claims["sub"].(string)
+
+	if loginUser.AuthId == "" {
+		return nil, fmt.Errorf("utilisateur non reconnu")
+	}
+
+	result := models.PreloadGrants(db).Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	return &loginUser, nil
+}
@@ -0,0 +1,41 @@
+package paymentlinks
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentLinkApiRessource struct {
+	*models.PaymentLinkModel
+}
+
+// Colonnes autorisées pour le tri
+var paymentLinkSorts = []string{"created_at", "updated_at", "amount", "currency", "expires_at", "active"}
+
+func AttachAPI(server *echo.Group) {
+	paymentLinkApi := &PaymentLinkApiRessource{&models.PaymentLinkModel{}}
+
+	// Payment Link
+	paymentLinkApiService := server.Group("/payment-links")
+	{
+		// Fetch
+		paymentLinkApiService.GET("/", paymentLinkApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(paymentLinkSorts...))
+
+		// Add Payment Link
+		paymentLinkApiService.POST("/", paymentLinkApi.Add(), middlewares.GrantMid())
+
+		paymentLinkOneApiService := paymentLinkApiService.Group("/:id", middlewares.GrantMid(), paymentLinkApi.GetOnMid())
+		{
+			// Get Payment Link Info
+			paymentLinkOneApiService.GET("/", paymentLinkApi.GetInfo(), middlewares.PermMid(models.PERM_PAYMENT_LINKS_READ))
+
+			// Update Payment Link
+			paymentLinkOneApiService.PUT("/", paymentLinkApi.UpdateInfo(), middlewares.PermMid(models.PERM_PAYMENT_LINKS_MANAGE))
+
+			// Payment Link QR Code
+			paymentLinkOneApiService.GET("/qr", paymentLinkApi.QrCode(), middlewares.PermMid(models.PERM_PAYMENT_LINKS_READ))
+		}
+	}
+}
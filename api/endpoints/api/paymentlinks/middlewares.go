@@ -0,0 +1,114 @@
+package paymentlinks
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (s *PaymentLinkApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			_, ok = c.Get("JWT_CLAIMS").(jwt.MapClaims)
+			if !ok {
+				err := fmt.Errorf("authentification obligatoire")
+				resp.SetStatus(http.StatusUnauthorized)
+				return resp.SendError(c, err.Error(), models.ResErrorAPI{
+					models.ErrorAPI{
+						Code:    "400",
+						Message: err.Error(),
+						Data:    err,
+					},
+				})
+			}
+
+			paymentLink, err := getPaymentLink(c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if paymentLink == nil {
+				err := fmt.Errorf("lien de paiement inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("PAYMENT_LINK", paymentLink)
+			c.Set("SERVICE_ID", paymentLink.ServiceId)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func getPaymentLink(paymentLinkId string) (*models.PaymentLinkModel, error) {
+	paymentLink := models.PaymentLinkModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := uuid.FromString(paymentLinkId); err == nil {
+		db = db.Or("id = ?", id.String())
+	} else {
+		err := fmt.Errorf("identifiant lien de paiement invalide")
+		return nil, err
+	}
+
+	result := db.
+		Preload("Service").
+		Where(&paymentLink).
+		First(&paymentLink)
+	if result.Error != nil {
+		return nil, err
+	}
+
+	paymentLink.CheckoutUrl = utils.CheckoutUrl(paymentLink.Code)
+
+	return &paymentLink, nil
+}
+
+// checkPaymentLinkPerm contrôle des droits de l'utilisateur sur la boutique du lien
+func checkPaymentLinkPerm(db *gorm.DB, serviceId string, claims jwt.MapClaims, perm models.Permission) (*models.UserModel, error) {
+	loginUser := models.UserModel{}
+	loginUser.AuthId = claims["sub"].(string)
+
+	result := db.
+		Scopes(models.PreloadGrants).
+		Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msgf("")
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	if err := loginUser.Authorize(perm, serviceId); err != nil {
+		return nil, err
+	}
+
+	return &loginUser, nil
+}
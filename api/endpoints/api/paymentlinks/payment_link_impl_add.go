@@ -0,0 +1,120 @@
+package paymentlinks
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPaymentLinkAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		PaymentLink models.PaymentLinkModel `json:"payment_link"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	ServiceId   string     `json:"service_id" form:"service_id" xml:"service_id" validate:"required"`                        // Identifiant de la boutique
+	Amount      float64    `json:"amount" form:"amount" xml:"amount" validate:"required,gt=0"`                               // Montant à payer
+	Currency    string     `json:"currency" form:"currency" xml:"currency" validate:"required,currency"`                     // Devise (ex: XOF)
+	Description string     `json:"description" form:"description" xml:"description" validate:"omitempty"`                    // Libellé affiché au payeur
+	ModeLive    bool       `json:"mode_live" form:"mode_live" xml:"mode_live" validate:"-"`                                  // Paiement réel ou sandbox
+	SingleUse   bool       `json:"single_use" form:"single_use" xml:"single_use" validate:"-"`                               // Un seul paiement accepté
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" form:"expires_at" xml:"expires_at" validate:"omitempty"`             // Date d'expiration (RFC3339)
+	SuccessUrl  string     `json:"success_url,omitempty" form:"success_url" xml:"success_url" validate:"omitempty,http_url"` // Redirection après paiement réussi
+	CancelUrl   string     `json:"cancel_url,omitempty" form:"cancel_url" xml:"cancel_url" validate:"omitempty,http_url"`    // Redirection après abandon ou échec
+}
+
+// Add
+// @Summary      	Add new payment link
+// @Description  	Création d'un lien de paiement pour une boutique
+// @Tags         	PaymentLinks
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResPaymentLinkAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/payment-links/ [post]
+func (s *PaymentLinkApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newPaymentLink := models.PaymentLinkModel{}
+		err := utils.BindValidate(c, data, &newPaymentLink)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := checkPaymentLinkPerm(db, newPaymentLink.ServiceId, claims, models.PERM_PAYMENT_LINKS_MANAGE)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// La boutique doit exister
+		service := models.ServiceModel{}
+		result := db.Model(&service).Where("id = ?", newPaymentLink.ServiceId).First(&service)
+		if result.Error != nil {
+			err := fmt.Errorf("boutique inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if newPaymentLink.IsExpired(time.Now()) {
+			err := fmt.Errorf("date d'expiration déjà dépassée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newPaymentLink.Currency = strings.ToUpper(newPaymentLink.Currency)
+		newPaymentLink.Active = true
+		newPaymentLink.CreatedById = loginUser.ID
+
+		result = db.Create(&newPaymentLink)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		newPaymentLink.CheckoutUrl = utils.CheckoutUrl(newPaymentLink.Code)
+
+		type resData struct {
+			PaymentLink models.PaymentLinkModel `json:"payment_link"`
+		}
+
+		resp.SetData(resData{
+			PaymentLink: newPaymentLink,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,209 @@
+package paymentlinks
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResPaymentLinkAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		PaymentLinks []models.PaymentLinkModel `json:"payment_links"`
+		Pagination   models.PaginationModel    `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all payment links paginate
+// @Description  	Récuperation des liens de paiement paginer
+// @Tags         	PaymentLinks
+// @Product       	json
+// @Param        	filter-service query string false "Identifiant de la boutique"
+// @Param        	filter-active query bool false "Liens actifs ou désactivés"
+// @response      	200 {object} ResPaymentLinkAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/payment-links/ [get]
+func (s *PaymentLinkApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser := models.UserModel{}
+		loginUser.AuthId = claims["sub"].(string)
+
+		result := db.
+			Scopes(models.PreloadGrants).
+			Where(&loginUser).First(&loginUser)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			if strings.Contains(result.Error.Error(), "record not found") {
+				err := fmt.Errorf("utilisateur non reconnu")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		paymentLinks := []models.PaymentLinkModel{}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		query, _ := c.Get("QUERY").(string)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.PaymentLinkModel{})
+
+		var count int64
+		err = fetchExec(
+			reqDb,
+			loginUser,
+			&paymentLinks,
+			fetchParams{
+				FilterService: c.QueryParam("filter-service"),
+				FilterActive:  c.QueryParam("filter-active"),
+				Orders:        orders,
+				Query:         query,
+				Limit:         limit,
+				Offset:        offset,
+				Cursor:        cursor,
+				WithCount:     withCount,
+			},
+			&count,
+		)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		for i := range paymentLinks {
+			paymentLinks[i].CheckoutUrl = utils.CheckoutUrl(paymentLinks[i].Code)
+		}
+
+		type dataResponse struct {
+			PaymentLinks []models.PaymentLinkModel `json:"payment_links"`
+			Pagination   models.PaginationModel    `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			paymentLinks, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(paymentLinks, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			PaymentLinks: paymentLinks,
+			Pagination:   pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchExec(
+	reqDb *gorm.DB,
+	loginUser models.UserModel,
+	paymentLinks *[]models.PaymentLinkModel,
+	params fetchParams,
+	count *int64,
+) error {
+	// Restreindre aux boutiques de l'utilisateur
+	if serviceIds, all := loginUser.ServicesWith(models.PERM_PAYMENT_LINKS_READ); !all {
+		reqDb = reqDb.Where("service_id IN (?)", serviceIds)
+	}
+
+	// Query
+	if len(params.Query) > 0 {
+		reqDb = reqDb.Where("lower(description) LIKE ?", strings.ToLower("%"+params.Query+"%"))
+	}
+
+	if len(params.FilterService) > 0 {
+		reqDb = reqDb.Where("service_id = ?", params.FilterService)
+	}
+
+	if len(params.FilterActive) > 0 {
+		reqDb = reqDb.Where("active = ?", params.FilterActive == "true")
+	}
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.
+		Preload("Service").
+		Find(paymentLinks)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	FilterService string
+	FilterActive  string
+	Orders        []string
+	Query         string
+	Limit         int
+	Offset        int
+	Cursor        *models.Cursor
+	WithCount     bool
+}
@@ -0,0 +1,84 @@
+package paymentlinks
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPaymentLinkAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		PaymentLink models.PaymentLinkModel `json:"payment_link"`
+		PaidCount   int64                   `json:"paid_count"`
+		PaidAmount  float64                 `json:"paid_amount"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get payment link info
+// @Description  	Récuperation d'un lien de paiement et du total encaissé
+// @Tags         	PaymentLinks
+// @Product       	json
+// @response      	200 {object} ResPaymentLinkAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/payment-links/:id/ [get]
+func (s *PaymentLinkApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			err := fmt.Errorf("lien de paiement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Paiements réussis
+		type paidStat struct {
+			Count  int64
+			Amount float64
+		}
+
+		stat := paidStat{}
+		result := db.Model(&models.TransactionModel{}).
+			Select("count(*) as count, coalesce(sum(amount), 0) as amount").
+			Where("payment_link_id = ? AND operation_state = ?", paymentLink.ID, models.TRANSACTION_SUCCESS).
+			Scan(&stat)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			PaymentLink models.PaymentLinkModel `json:"payment_link"`
+			PaidCount   int64                   `json:"paid_count"`
+			PaidAmount  float64                 `json:"paid_amount"`
+		}
+
+		resp.SetData(resData{
+			PaymentLink: *paymentLink,
+			PaidCount:   stat.Count,
+			PaidAmount:  stat.Amount,
+		})
+
+		return resp.Send(c)
+	}
+}
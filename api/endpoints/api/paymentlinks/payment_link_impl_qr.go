@@ -0,0 +1,81 @@
+package paymentlinks
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// QrCode
+// @Summary      	Payment link QR code
+// @Description  	QR code du lien de paiement (page de paiement ou QR marchand du provider)
+// @Tags         	PaymentLinks
+// @Product       	png,svg
+// @Param        	format query string false "png (défaut) ou svg"
+// @Param        	size query int false "Taille en pixel (64 à 1024, défaut 256)"
+// @Param        	level query string false "Correction d'erreur L, M (défaut), Q, H"
+// @Param        	payload query string false "checkout (défaut) ou provider"
+// @Param        	provider_id query string false "Provider du QR marchand (payload=provider)"
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/payment-links/:id/qr [get]
+func (s *PaymentLinkApiRessource) QrCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			err := fmt.Errorf("lien de paiement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		options, err := utils.QrOptionsFromQuery(c)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		content := paymentLink.CheckoutUrl
+		if options.Payload == utils.QR_PAYLOAD_PROVIDER {
+			provider := models.ProviderModel{}
+			result := db.Model(&provider).Where("id = ?", c.QueryParam("provider_id")).First(&provider)
+			if result.Error != nil {
+				err := fmt.Errorf("provider inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			// Le QR marchand est demandé pour le montant du lien
+			content, err = utils.ProviderQrPayload(provider, models.TransactionModel{
+				ModeLive:      paymentLink.ModeLive,
+				Amount:        paymentLink.Amount,
+				OperationMode: models.OPERATION_CREDIT,
+				ServiceId:     paymentLink.ServiceId,
+				ReferenceId:   paymentLink.Code,
+				Currency:      paymentLink.Currency,
+			})
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+		}
+
+		image, contentType, err := utils.QrCode(content, options)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		return c.Blob(http.StatusOK, contentType, image)
+	}
+}
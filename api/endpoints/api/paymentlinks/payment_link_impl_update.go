@@ -0,0 +1,107 @@
+package paymentlinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPaymentLinkAPIUpdateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		PaymentLink models.PaymentLinkModel `json:"payment_link"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type UpdateFormData struct {
+	Description *string    `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"`          // Libellé affiché au payeur
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" form:"expires_at" xml:"expires_at" validate:"omitempty"`             // Date d'expiration (RFC3339)
+	Active      *bool      `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"`                         // Activation / désactivation du lien
+	SuccessUrl  *string    `json:"success_url,omitempty" form:"success_url" xml:"success_url" validate:"omitempty,http_url"` // Redirection après paiement réussi
+	CancelUrl   *string    `json:"cancel_url,omitempty" form:"cancel_url" xml:"cancel_url" validate:"omitempty,http_url"`    // Redirection après abandon ou échec
+}
+
+// UpdateInfo
+// @Summary      	Update payment link
+// @Description  	Mise à jour ou désactivation d'un lien de paiement
+// @Tags         	PaymentLinks
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData UpdateFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResPaymentLinkAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/payment-links/:id/ [put]
+func (s *PaymentLinkApiRessource) UpdateInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			err := fmt.Errorf("lien de paiement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(UpdateFormData)
+		updateForm := UpdateFormData{}
+		err := utils.BindValidate(c, data, &updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Seuls les champs transmis sont modifiés
+		linkFormJson, err := json.Marshal(updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = json.Unmarshal(linkFormJson, paymentLink)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Model(&models.PaymentLinkModel{}).
+			Where("id = ?", paymentLink.ID).
+			Updates(map[string]interface{}{
+				"description": paymentLink.Description,
+				"expires_at":  paymentLink.ExpiresAt,
+				"active":      paymentLink.Active,
+				"success_url": paymentLink.SuccessUrl,
+				"cancel_url":  paymentLink.CancelUrl,
+			})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			PaymentLink models.PaymentLinkModel `json:"payment_link"`
+		}
+
+		resp.SetData(resData{
+			PaymentLink: *paymentLink,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -11,6 +11,9 @@ type ProviderApiRessource struct {
 	*models.ProviderModel
 }
 
+// Colonnes autorisées pour le tri
+var providerSorts = []string{"name", "created_at", "updated_at"}
+
 func AttachAPI(server *echo.Group) {
 	providerApi := &ProviderApiRessource{&models.ProviderModel{}}
 
@@ -18,10 +21,13 @@ func AttachAPI(server *echo.Group) {
 	serviceApiService := server.Group("/providers")
 	{
 		// Fetch
-		serviceApiService.GET("/", providerApi.Fetch(), middlewares.GrantMid(), models.PaginationMid())
+		serviceApiService.GET("/", providerApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(providerSorts...))
+
+		// Suggestion des providers selon le numéro du payeur
+		serviceApiService.GET("/suggest", providerApi.Suggest(), middlewares.GrantMid())
 
 		// Add Provider
-		serviceApiService.POST("/", providerApi.Add(), middlewares.GrantMid())
+		serviceApiService.POST("/", providerApi.Add(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_PROVIDERS))
 
 		// serviceOneApiService := serviceApiService.Group("/:id", middlewares.GrantMid(), transactionApi.GetOnMid())
 		// {
@@ -60,7 +60,7 @@ func (s *ProviderApiRessource) Fetch() echo.HandlerFunc {
 		loginUser.AuthId = claims["sub"].(string)
 
 		result := db.
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where(&loginUser).First(&loginUser)
 		if result.Error != nil {
 			log.Error().Err(result.Error).Msgf("")
@@ -77,6 +77,8 @@ func (s *ProviderApiRessource) Fetch() echo.HandlerFunc {
 		offset, _ := c.Get("OFFSET").(int)
 		query, _ := c.Get("QUERY").(string)
 		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
 
 		reqDb := db.Model(&models.ProviderModel{})
 
@@ -92,6 +94,8 @@ func (s *ProviderApiRessource) Fetch() echo.HandlerFunc {
 				Query:          query,
 				Limit:          limit,
 				Offset:         offset,
+				Cursor:         cursor,
+				WithCount:      withCount,
 			},
 			&count,
 		)
@@ -105,14 +109,25 @@ func (s *ProviderApiRessource) Fetch() echo.HandlerFunc {
 			Pagination models.PaginationModel `json:"pagination"`
 		}
 
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			providers, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(providers, cursor, limit)
+			pagination.Offset = 0
+		}
+
 		resp.SetData(dataResponse{
-			Providers: providers,
-			Pagination: models.PaginationModel{
-				Count:  count,
-				Limit:  limit,
-				Offset: offset,
-				Query:  query,
-			},
+			Providers:  providers,
+			Pagination: pagination,
 		})
 
 		return resp.Send(c)
@@ -149,18 +164,27 @@ func fetchExec(
 	// Query
 	reqDb = fetchQuery(reqDb, params.Query)
 
-	result := reqDb.Count(count)
-	if result.Error != nil {
-		return result.Error
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
 	}
 
-	// Orders
-	reqDb = fetchOrder(reqDb, params.Orders)
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
 
-	result = reqDb.
+	result := reqDb.
 		// Preload("Transactions").
-		Limit(int(params.Limit)).
-		Offset(int(params.Offset)).
 		Find(providers)
 	if result.Error != nil {
 		return result.Error
@@ -175,4 +199,6 @@ type fetchParams struct {
 	Query          string
 	Limit          int
 	Offset         int
+	Cursor         *models.Cursor
+	WithCount      bool
 }
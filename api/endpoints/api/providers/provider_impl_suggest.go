@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+type ResProviderAPISuggestSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Phone     utils.PhoneNumber      `json:"phone"`
+		Providers []models.ProviderModel `json:"providers"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Suggest
+// @Summary      	Suggest providers for a phone number
+// @Description  	Validation du numéro du payeur et suggestion des providers correspondant à son opérateur
+// @Tags         	Providers
+// @Product       	json
+// @Param        	phone query string true "Numéro de téléphone du payeur"
+// @Param        	country query string true "Code pays du numéro (CIV)"
+// @response      	200 {object} ResProviderAPISuggestSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/providers/suggest [get]
+func (s *ProviderApiRessource) Suggest() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		_, ok = c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		number, err := utils.ParsePhone(c.QueryParam("country"), c.QueryParam("phone"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		providers, err := utils.SuggestProviders(db, *number)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Phone     utils.PhoneNumber      `json:"phone"`
+			Providers []models.ProviderModel `json:"providers"`
+		}
+
+		resp.SetData(resData{
+			Phone:     *number,
+			Providers: providers,
+		})
+
+		return resp.Send(c)
+	}
+}
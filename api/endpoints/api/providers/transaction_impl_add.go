@@ -10,6 +10,7 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type ResProviderAPICreateSuccess struct {
@@ -28,6 +29,7 @@ type ResProviderAPICreateSuccess struct {
 type AddFormData struct {
 	Name        string `json:"name" form:"name" xml:"name" validate:"required"`                       // Nom du provider
 	Description string `json:"description" form:"description" xml:"description" validate:"omitempty"` // Description du provider
+	Operator    string `json:"operator" form:"operator" xml:"operator" validate:"omitempty"`          // Opérateur mobile (ORANGE, MTN, MOOV)
 }
 
 // Add
@@ -66,7 +68,7 @@ func (s *ProviderApiRessource) Add() echo.HandlerFunc {
 		}
 
 		// Création de la boutique
-		err = createProviderDb(&newProvider)
+		err = createProviderDb(&newProvider, utils.AuditActor(c))
 		if err != nil {
 			log.Error().Err(err).Msgf("")
 			errMsg := err.Error()
@@ -89,21 +91,23 @@ func (s *ProviderApiRessource) Add() echo.HandlerFunc {
 	}
 }
 
-func createProviderDb(newProvider *models.ProviderModel) error {
+func createProviderDb(newProvider *models.ProviderModel, actor models.AuditActor) error {
 	// Connexion à la base de donnée
 	db, err := models.GetDB()
 	if err != nil {
 		return err
 	}
 
-	result := db.Create(&newProvider)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "duplicate key value violates") {
-			return fmt.Errorf("boutique existe déjà")
-		}
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Create(&newProvider)
+		if result.Error != nil {
+			if strings.Contains(result.Error.Error(), "duplicate key value violates") {
+				return fmt.Errorf("boutique existe déjà")
+			}
 
-		return result.Error
-	}
+			return result.Error
+		}
 
-	return nil
+		return models.Audit(tx, actor, models.AUDIT_PROVIDER_CREATE, "provider", newProvider.ID, nil, newProvider)
+	})
 }
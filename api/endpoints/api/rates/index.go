@@ -0,0 +1,35 @@
+package rates
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RateApiRessource struct {
+	*models.ExchangeRateModel
+}
+
+// Colonnes autorisées pour le tri
+var rateSorts = []string{"created_at", "effective_at", "base_currency", "quote_currency"}
+
+func AttachAPI(server *echo.Group) {
+	rateApi := &RateApiRessource{&models.ExchangeRateModel{}}
+
+	// Exchange Rate
+	rateApiService := server.Group("/exchange-rates")
+	{
+		// Fetch
+		rateApiService.GET("/", rateApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(rateSorts...))
+
+		// Add Rate
+		rateApiService.POST("/", rateApi.Add(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RATES))
+
+		// Import rates file (csv, json)
+		rateApiService.POST("/import", rateApi.Import(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RATES))
+
+		// Convert amount
+		rateApiService.GET("/convert", rateApi.Convert(), middlewares.GrantMid())
+	}
+}
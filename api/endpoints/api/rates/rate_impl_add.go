@@ -0,0 +1,82 @@
+package rates
+
+import (
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResRateAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rate models.ExchangeRateModel `json:"rate"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	BaseCurrency  string     `json:"base_currency" form:"base_currency" xml:"base_currency" validate:"required,currency"`    // Devise de base (ex: EUR)
+	QuoteCurrency string     `json:"quote_currency" form:"quote_currency" xml:"quote_currency" validate:"required,currency"` // Devise de cotation (ex: XOF)
+	Rate          float64    `json:"rate" form:"rate" xml:"rate" validate:"required,gt=0"`                                   // 1 base = rate quote
+	EffectiveAt   *time.Time `json:"effective_at,omitempty" form:"effective_at" xml:"effective_at" validate:"omitempty"`     // Date d'application, maintenant par défaut
+}
+
+// Add
+// @Summary      	Add exchange rate
+// @Description  	Saisie manuelle d'un taux de change, un taux existant à la même date est remplacé
+// @Tags         	ExchangeRates
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResRateAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/exchange-rates/ [post]
+func (s *RateApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newRate := models.ExchangeRateModel{}
+		err := utils.BindValidate(c, data, &newRate)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newRate.Source = models.RATE_SOURCE_MANUAL
+
+		rates := []models.ExchangeRateModel{newRate}
+		if err := models.SaveExchangeRates(db, rates); err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Rate models.ExchangeRateModel `json:"rate"`
+		}
+
+		resp.SetData(resData{
+			Rate: rates[0],
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,105 @@
+package rates
+
+import (
+	"fmt"
+	"spay/models"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResRateAPIConvertSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Amount          float64 `json:"amount"`
+		From            string  `json:"from"`
+		To              string  `json:"to"`
+		Rate            float64 `json:"rate"`
+		ConvertedAmount float64 `json:"converted_amount"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Convert
+// @Summary      	Convert amount
+// @Description  	Conversion d'un montant au dernier taux connu à la date indiquée
+// @Tags         	ExchangeRates
+// @Product       	json
+// @Param        	amount query number true "Montant à convertir"
+// @Param        	from query string true "Devise du montant (ex: EUR)"
+// @Param        	to query string true "Devise cible (ex: XOF)"
+// @Param        	date query string false "Date du taux (ex: 2023-01-31), maintenant par défaut"
+// @response      	200 {object} ResRateAPIConvertSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/exchange-rates/convert [get]
+func (s *RateApiRessource) Convert() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		amount, err := strconv.ParseFloat(c.QueryParam("amount"), 64)
+		if err != nil {
+			err := fmt.Errorf("montant invalide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		from, err := models.NormalizeCurrency(c.QueryParam("from"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		to, err := models.NormalizeCurrency(c.QueryParam("to"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		at := time.Now()
+		if date := c.QueryParam("date"); date != "" {
+			day, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				err := fmt.Errorf("date invalide %v", date)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			// Taux connus jusqu'à la fin de la journée
+			at = day.AddDate(0, 0, 1)
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		rate, err := models.FindExchangeRate(db, from, to, at)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Amount          float64 `json:"amount"`
+			From            string  `json:"from"`
+			To              string  `json:"to"`
+			Rate            float64 `json:"rate"`
+			ConvertedAmount float64 `json:"converted_amount"`
+		}
+
+		resp.SetData(resData{
+			Amount:          amount,
+			From:            from,
+			To:              to,
+			Rate:            rate,
+			ConvertedAmount: models.RoundAmount(amount * rate),
+		})
+
+		return resp.Send(c)
+	}
+}
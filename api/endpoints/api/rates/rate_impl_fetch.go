@@ -0,0 +1,160 @@
+package rates
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResRateAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Rates      []models.ExchangeRateModel `json:"rates"`
+		Pagination models.PaginationModel     `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all exchange rates paginate
+// @Description  	Récuperation des taux de change paginer
+// @Tags         	ExchangeRates
+// @Product       	json
+// @Param        	filter-base query string false "Devises de base séparées par virgule (ex: EUR)"
+// @Param        	filter-quote query string false "Devises de cotation séparées par virgule (ex: XOF)"
+// @response      	200 {object} ResRateAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/exchange-rates/ [get]
+func (s *RateApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := db.Model(&models.ExchangeRateModel{})
+
+		if filter := c.QueryParam("filter-base"); filter != "" {
+			reqDb = reqDb.Where("base_currency IN (?)", strings.Split(strings.ToUpper(filter), ","))
+		}
+
+		if filter := c.QueryParam("filter-quote"); filter != "" {
+			reqDb = reqDb.Where("quote_currency IN (?)", strings.Split(strings.ToUpper(filter), ","))
+		}
+
+		rates := []models.ExchangeRateModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &rates, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Rates      []models.ExchangeRateModel `json:"rates"`
+			Pagination models.PaginationModel     `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			rates, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(rates, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Rates:      rates,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchExec[T any](reqDb *gorm.DB, items *[]T, params fetchParams, count *int64) error {
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		for _, order := range params.Orders {
+			reqDb = reqDb.Order(order)
+		}
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(items)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	Orders    []string
+	Query     string
+	Limit     int
+	Offset    int
+	Cursor    *models.Cursor
+	WithCount bool
+}
+
+func fetchParamsFromContext(c echo.Context) fetchParams {
+	params := fetchParams{}
+
+	params.Limit, _ = c.Get("LIMIT").(int)
+	params.Offset, _ = c.Get("OFFSET").(int)
+	params.Query, _ = c.Get("QUERY").(string)
+	params.Orders, _ = c.Get("ORDERS").([]string)
+	params.Cursor, _ = c.Get("CURSOR").(*models.Cursor)
+	params.WithCount, _ = c.Get("WITH_COUNT").(bool)
+
+	return params
+}
+
+func (params fetchParams) pagination(count *int64) models.PaginationModel {
+	pagination := models.PaginationModel{
+		Limit:  params.Limit,
+		Offset: params.Offset,
+		Query:  params.Query,
+	}
+
+	if params.WithCount {
+		pagination.Count = count
+	}
+
+	if params.Cursor != nil {
+		pagination.Offset = 0
+	}
+
+	return pagination
+}
@@ -0,0 +1,94 @@
+package rates
+
+import (
+	"fmt"
+	"path/filepath"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResRateAPIImportSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Count int `json:"count"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Import
+// @Summary      	Import exchange rates
+// @Description  	Import d'un fichier de taux: csv (base,quote,rate[,date]) ou json ({"base","date","rates"})
+// @Tags         	ExchangeRates
+// @accept 			mpfd
+// @Product       	json
+// @Param        	file formData file true "Fichier de taux"
+// @Param        	format formData string false "csv ou json, déduit de l'extension par défaut"
+// @response      	200 {object} ResRateAPIImportSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/exchange-rates/import [post]
+func (s *RateApiRessource) Import() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			err := fmt.Errorf("fichier obligatoire")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if file.Size > utils.MAX_UPLOAD_SIZE {
+			err := fmt.Errorf("fichier trop volumineux, %v Mo maximum", utils.MAX_UPLOAD_SIZE>>20)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		format := c.FormValue("format")
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(file.Filename), ".")
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+		defer src.Close()
+
+		rates, err := utils.ParseRates(src, format, models.RATE_SOURCE_FILE)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := models.SaveExchangeRates(db, rates); err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Count int `json:"count"`
+		}
+
+		resp.SetData(resData{
+			Count: len(rates),
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,46 @@
+package reconciliations
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReconciliationApiRessource struct {
+	*models.ReconciliationModel
+}
+
+// Colonnes autorisées pour le tri
+var reconciliationSorts = []string{"created_at", "updated_at", "period_start", "period_end", "state", "unresolved_count"}
+var lineSorts = []string{"created_at", "line_number", "status", "amount", "operation_date"}
+
+func AttachAPI(server *echo.Group) {
+	reconciliationApi := &ReconciliationApiRessource{&models.ReconciliationModel{}}
+
+	// Reconciliation
+	reconciliationApiService := server.Group("/reconciliations")
+	{
+		// Fetch
+		reconciliationApiService.GET("/", reconciliationApi.Fetch(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RECONCILIATIONS), models.PaginationMid(reconciliationSorts...))
+
+		// Import provider statement
+		reconciliationApiService.POST("/", reconciliationApi.Import(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RECONCILIATIONS))
+
+		// Provider statement column mapping
+		reconciliationApiService.GET("/mappings/:provider_id", reconciliationApi.GetMapping(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RECONCILIATIONS))
+		reconciliationApiService.PUT("/mappings/:provider_id", reconciliationApi.SaveMapping(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RECONCILIATIONS))
+
+		reconciliationOneApiService := reconciliationApiService.Group("/:id", middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_RECONCILIATIONS), reconciliationApi.GetOnMid())
+		{
+			// Get Reconciliation Report
+			reconciliationOneApiService.GET("/", reconciliationApi.GetInfo())
+
+			// Fetch Reconciliation Lines
+			reconciliationOneApiService.GET("/lines", reconciliationApi.FetchLines(), models.PaginationMid(lineSorts...))
+
+			// Resolve Reconciliation Line
+			reconciliationOneApiService.POST("/lines/:line_id/resolve", reconciliationApi.Resolve())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package reconciliations
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *ReconciliationApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			reconciliation, err := getReconciliation(c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if reconciliation == nil {
+				err := fmt.Errorf("rapprochement inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("RECONCILIATION", reconciliation)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func getReconciliation(reconciliationId string) (*models.ReconciliationModel, error) {
+	reconciliation := models.ReconciliationModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := uuid.FromString(reconciliationId); err == nil {
+		db = db.Or("id = ?", id.String())
+	} else {
+		err := fmt.Errorf("identifiant rapprochement invalide")
+		return nil, err
+	}
+
+	result := db.
+		Preload("Provider").
+		Where(&reconciliation).
+		First(&reconciliation)
+	if result.Error != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
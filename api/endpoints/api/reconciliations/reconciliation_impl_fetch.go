@@ -0,0 +1,179 @@
+package reconciliations
+
+import (
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResReconciliationAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Reconciliations []models.ReconciliationModel `json:"reconciliations"`
+		Pagination      models.PaginationModel       `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Fetch
+// @Summary      	Fetch all reconciliations paginate
+// @Description  	Récuperation des rapprochements paginer
+// @Tags         	Reconciliations
+// @Product       	json
+// @Param        	filter-provider query string false "Identifiant du provider"
+// @Param        	filter-state query string false "OPEN ou RESOLVED"
+// @response      	200 {object} ResReconciliationAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/ [get]
+func (s *ReconciliationApiRessource) Fetch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		reconciliations := []models.ReconciliationModel{}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		query, _ := c.Get("QUERY").(string)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.ReconciliationModel{})
+
+		var count int64
+		err = fetchExec(
+			reqDb,
+			&reconciliations,
+			fetchParams{
+				FilterProvider: c.QueryParam("filter-provider"),
+				FilterState:    strings.ToUpper(c.QueryParam("filter-state")),
+				Orders:         orders,
+				Query:          query,
+				Limit:          limit,
+				Offset:         offset,
+				Cursor:         cursor,
+				WithCount:      withCount,
+			},
+			&count,
+		)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Reconciliations []models.ReconciliationModel `json:"reconciliations"`
+			Pagination      models.PaginationModel       `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			reconciliations, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(reconciliations, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Reconciliations: reconciliations,
+			Pagination:      pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchOrder(reqDb *gorm.DB, orders []string) *gorm.DB {
+	if len(orders) > 0 {
+		for _, order := range orders {
+			reqDb = reqDb.Order(order)
+		}
+	}
+
+	return reqDb
+}
+
+func fetchExec(
+	reqDb *gorm.DB,
+	reconciliations *[]models.ReconciliationModel,
+	params fetchParams,
+	count *int64,
+) error {
+	// Query
+	if len(params.Query) > 0 {
+		reqDb = reqDb.Where("lower(file_name) LIKE ?", strings.ToLower("%"+params.Query+"%"))
+	}
+
+	if len(params.FilterProvider) > 0 {
+		reqDb = reqDb.Where("provider_id = ?", params.FilterProvider)
+	}
+
+	if len(params.FilterState) > 0 {
+		reqDb = reqDb.Where("state = ?", params.FilterState)
+	}
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.
+		Preload("Provider").
+		Find(reconciliations)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+type fetchParams struct {
+	FilterProvider string
+	FilterState    string
+	Orders         []string
+	Query          string
+	Limit          int
+	Offset         int
+	Cursor         *models.Cursor
+	WithCount      bool
+}
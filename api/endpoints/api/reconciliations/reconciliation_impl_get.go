@@ -0,0 +1,55 @@
+package reconciliations
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResReconciliationAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Reconciliation models.ReconciliationModel `json:"reconciliation"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get reconciliation report
+// @Description  	Récuperation du rapport de rapprochement
+// @Tags         	Reconciliations
+// @Product       	json
+// @response      	200 {object} ResReconciliationAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/:id/ [get]
+func (s *ReconciliationApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		reconciliation, ok := c.Get("RECONCILIATION").(*models.ReconciliationModel)
+		if !ok {
+			err := fmt.Errorf("rapprochement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Reconciliation models.ReconciliationModel `json:"reconciliation"`
+		}
+
+		resp.SetData(resData{
+			Reconciliation: *reconciliation,
+		})
+
+		return resp.Send(c)
+	}
+}
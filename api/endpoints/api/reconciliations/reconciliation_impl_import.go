@@ -0,0 +1,473 @@
+package reconciliations
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"spay/models"
+	"spay/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Taille maximale d'un relevé
+const statementMaxSize = 50 << 20
+
+// Nombre de lignes enregistrées par lot
+const statementBatchSize = 500
+
+type ResReconciliationAPIImportSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Reconciliation models.ReconciliationModel `json:"reconciliation"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Import
+// @Summary      	Import provider statement
+// @Description  	Import d'un relevé provider (csv) et rapprochement avec les transactions
+// @Tags         	Reconciliations
+// @accept 			mpfd
+// @Product       	json
+// @Param        	provider_id formData string true "Identifiant du provider"
+// @Param        	file formData file true "Relevé csv"
+// @response      	200 {object} ResReconciliationAPIImportSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/ [post]
+func (s *ReconciliationApiRessource) Import() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		mapping, err := getMapping(db, c.FormValue("provider_id"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return resp.SendError(c, "fichier relevé obligatoire", models.TransformErr(err))
+		}
+
+		if fileHeader.Size > statementMaxSize {
+			err := fmt.Errorf("fichier relevé trop volumineux")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+		defer file.Close()
+
+		// Seuls les fichiers texte sont acceptés
+		contentType, err := utils.GetFileContentType(file)
+		if err != nil && err != io.EOF {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if !strings.HasPrefix(contentType, "text/plain") {
+			err := fmt.Errorf("format de relevé invalide %v, csv attendu", contentType)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		reconciliation := models.ReconciliationModel{
+			ProviderId:  mapping.ProviderId,
+			FileName:    fileHeader.Filename,
+			State:       models.RECONCILIATION_OPEN,
+			CreatedById: loginUser.ID,
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return importStatement(tx, *mapping, file, &reconciliation)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Reconciliation models.ReconciliationModel `json:"reconciliation"`
+		}
+
+		resp.SetData(resData{
+			Reconciliation: reconciliation,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// statementLine ligne lue dans le relevé
+type statementLine struct {
+	Number    int
+	Reference string
+	Amount    float64
+	Currency  string
+	Date      time.Time
+}
+
+// importStatement lecture du relevé, rapprochement ligne par ligne puis recherche des transactions absentes
+func importStatement(tx *gorm.DB, mapping models.ReconciliationMappingModel, file io.Reader, reconciliation *models.ReconciliationModel) error {
+	if result := tx.Create(reconciliation); result.Error != nil {
+		return result.Error
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		reader.Comma = rune(mapping.Delimiter[0])
+	}
+
+	columns, err := newStatementColumns(reader, mapping)
+	if err != nil {
+		return err
+	}
+
+	matchedIds := map[string]bool{}
+	batch := []models.ReconciliationLineModel{}
+	lineNumber := columns.firstLine
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("ligne %v: %v", lineNumber, err)
+		}
+
+		lineNumber++
+
+		line, err := columns.parse(record, lineNumber, mapping.DateLayout)
+		if err != nil {
+			return err
+		}
+
+		reconciliationLine, err := matchStatementLine(tx, mapping.ProviderId, line, matchedIds)
+		if err != nil {
+			return err
+		}
+
+		reconciliationLine.ReconciliationId = reconciliation.ID
+		batch = append(batch, *reconciliationLine)
+		countLine(reconciliation, reconciliationLine)
+
+		if reconciliation.PeriodStart.IsZero() || line.Date.Before(reconciliation.PeriodStart) {
+			reconciliation.PeriodStart = line.Date
+		}
+
+		if line.Date.After(reconciliation.PeriodEnd) {
+			reconciliation.PeriodEnd = line.Date
+		}
+
+		if len(batch) >= statementBatchSize {
+			if result := tx.Create(&batch); result.Error != nil {
+				return result.Error
+			}
+
+			batch = []models.ReconciliationLineModel{}
+		}
+	}
+
+	if len(batch) > 0 {
+		if result := tx.Create(&batch); result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if reconciliation.LineCount == 0 {
+		return fmt.Errorf("relevé vide")
+	}
+
+	// Transactions réussies de la période absentes du relevé
+	err = findMissingTransactions(tx, reconciliation, matchedIds)
+	if err != nil {
+		return err
+	}
+
+	if reconciliation.UnresolvedCount == 0 {
+		reconciliation.State = models.RECONCILIATION_RESOLVED
+	}
+
+	return tx.Save(reconciliation).Error
+}
+
+// matchStatementLine recherche de la transaction par réference opérateur puis par montant et date
+func matchStatementLine(tx *gorm.DB, providerId string, line statementLine, matchedIds map[string]bool) (*models.ReconciliationLineModel, error) {
+	reconciliationLine := models.ReconciliationLineModel{
+		LineNumber:        line.Number,
+		ProviderReference: line.Reference,
+		Amount:            line.Amount,
+		Currency:          line.Currency,
+		OperationDate:     line.Date,
+		Status:            models.RECONCILIATION_EXTRA,
+	}
+
+	candidates := []models.TransactionModel{}
+
+	if line.Reference != "" {
+		result := tx.Model(&models.TransactionModel{}).
+			Where("provider_id = ? AND mode_live = ? AND provider_reference = ?", providerId, true, line.Reference).
+			Limit(2).
+			Find(&candidates)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+	}
+
+	if len(candidates) == 0 {
+		day := time.Date(line.Date.Year(), line.Date.Month(), line.Date.Day(), 0, 0, 0, 0, line.Date.Location())
+		result := tx.Model(&models.TransactionModel{}).
+			Where("provider_id = ? AND mode_live = ? AND amount = ? AND created_at >= ? AND created_at < ?", providerId, true, line.Amount, day, day.AddDate(0, 0, 1)).
+			Order("created_at asc").
+			Limit(20).
+			Find(&candidates)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+	}
+
+	for _, transaction := range candidates {
+		if matchedIds[transaction.ID] {
+			continue
+		}
+
+		matchedIds[transaction.ID] = true
+		applyTransactionMatch(&reconciliationLine, transaction)
+		break
+	}
+
+	return &reconciliationLine, nil
+}
+
+// applyTransactionMatch statut d'une ligne rattachée à une transaction
+func applyTransactionMatch(line *models.ReconciliationLineModel, transaction models.TransactionModel) {
+	line.TransactionId = transaction.ID
+	line.TransactionAmount = transaction.Amount
+
+	switch {
+	case transaction.OperationState != models.TRANSACTION_SUCCESS:
+		line.Status = models.RECONCILIATION_STATE_MISMATCH
+	case math.Abs(transaction.Amount-line.Amount) >= 0.005:
+		line.Status = models.RECONCILIATION_AMOUNT_MISMATCH
+	default:
+		line.Status = models.RECONCILIATION_MATCHED
+	}
+}
+
+func findMissingTransactions(tx *gorm.DB, reconciliation *models.ReconciliationModel, matchedIds map[string]bool) error {
+	start := time.Date(reconciliation.PeriodStart.Year(), reconciliation.PeriodStart.Month(), reconciliation.PeriodStart.Day(), 0, 0, 0, 0, reconciliation.PeriodStart.Location())
+	end := time.Date(reconciliation.PeriodEnd.Year(), reconciliation.PeriodEnd.Month(), reconciliation.PeriodEnd.Day(), 0, 0, 0, 0, reconciliation.PeriodEnd.Location()).AddDate(0, 0, 1)
+
+	rows, err := tx.Model(&models.TransactionModel{}).
+		Where("provider_id = ? AND mode_live = ? AND operation_state = ? AND created_at >= ? AND created_at < ?", reconciliation.ProviderId, true, models.TRANSACTION_SUCCESS, start, end).
+		Rows()
+	if err != nil {
+		return err
+	}
+
+	missing := []models.ReconciliationLineModel{}
+	for rows.Next() {
+		transaction := models.TransactionModel{}
+		if err := tx.ScanRows(rows, &transaction); err != nil {
+			rows.Close()
+			return err
+		}
+
+		if matchedIds[transaction.ID] {
+			continue
+		}
+
+		line := models.ReconciliationLineModel{
+			ReconciliationId:  reconciliation.ID,
+			TransactionId:     transaction.ID,
+			ProviderReference: transaction.ProviderReference,
+			TransactionAmount: transaction.Amount,
+			Currency:          transaction.Currency,
+			OperationDate:     transaction.CreatedAt,
+			Status:            models.RECONCILIATION_MISSING,
+		}
+
+		missing = append(missing, line)
+		countLine(reconciliation, &line)
+	}
+
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return tx.CreateInBatches(&missing, statementBatchSize).Error
+}
+
+func countLine(reconciliation *models.ReconciliationModel, line *models.ReconciliationLineModel) {
+	if line.LineNumber > 0 {
+		reconciliation.LineCount++
+	}
+
+	switch line.Status {
+	case models.RECONCILIATION_MATCHED:
+		reconciliation.MatchedCount++
+	case models.RECONCILIATION_MISSING:
+		reconciliation.MissingCount++
+	case models.RECONCILIATION_EXTRA:
+		reconciliation.ExtraCount++
+	default:
+		reconciliation.MismatchCount++
+	}
+
+	if !line.IsResolved() {
+		reconciliation.UnresolvedCount++
+	}
+}
+
+// statementColumns position des colonnes du relevé
+type statementColumns struct {
+	reference int
+	amount    int
+	date      int
+	currency  int
+	firstLine int
+}
+
+func newStatementColumns(reader *csv.Reader, mapping models.ReconciliationMappingModel) (*statementColumns, error) {
+	columns := statementColumns{currency: -1}
+	header := []string{}
+
+	if mapping.HasHeader {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("entête du relevé illisible: %v", err)
+		}
+
+		header = record
+		columns.firstLine = 1
+	}
+
+	position := func(column string) (int, error) {
+		if mapping.HasHeader {
+			for i, name := range header {
+				if strings.EqualFold(strings.Trim(name, " \ufeff"), column) {
+					return i, nil
+				}
+			}
+
+			return -1, fmt.Errorf("colonne %v absente du relevé", column)
+		}
+
+		index, err := strconv.Atoi(column)
+		if err != nil || index < 0 {
+			return -1, fmt.Errorf("index de colonne invalide %v", column)
+		}
+
+		return index, nil
+	}
+
+	var err error
+	if columns.reference, err = position(mapping.ReferenceColumn); err != nil {
+		return nil, err
+	}
+
+	if columns.amount, err = position(mapping.AmountColumn); err != nil {
+		return nil, err
+	}
+
+	if columns.date, err = position(mapping.DateColumn); err != nil {
+		return nil, err
+	}
+
+	if mapping.CurrencyColumn != "" {
+		if columns.currency, err = position(mapping.CurrencyColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	return &columns, nil
+}
+
+func (columns *statementColumns) parse(record []string, lineNumber int, dateLayout string) (statementLine, error) {
+	line := statementLine{Number: lineNumber}
+
+	value := func(index int) (string, error) {
+		if index >= len(record) {
+			return "", fmt.Errorf("ligne %v: colonne %v absente", lineNumber, index)
+		}
+
+		return strings.Trim(record[index], " "), nil
+	}
+
+	var err error
+	if line.Reference, err = value(columns.reference); err != nil {
+		return line, err
+	}
+
+	amount, err := value(columns.amount)
+	if err != nil {
+		return line, err
+	}
+
+	// Les relevés utilisent parfois la virgule décimale et des espaces de milliers
+	amount = strings.ReplaceAll(strings.ReplaceAll(amount, " ", ""), ",", ".")
+	if line.Amount, err = strconv.ParseFloat(amount, 64); err != nil {
+		return line, fmt.Errorf("ligne %v: montant invalide %v", lineNumber, amount)
+	}
+
+	date, err := value(columns.date)
+	if err != nil {
+		return line, err
+	}
+
+	layouts := append([]string{time.RFC3339, "2006-01-02 15:04:05"}, utils.LAYOUTS_TIME...)
+	if dateLayout != "" {
+		layouts = []string{dateLayout}
+	}
+
+	var ok bool
+	if ok, line.Date = utils.StringToDate(date, layouts); !ok {
+		return line, fmt.Errorf("ligne %v: date invalide %v", lineNumber, date)
+	}
+
+	if columns.currency >= 0 {
+		if line.Currency, err = value(columns.currency); err != nil {
+			return line, err
+		}
+	}
+
+	return line, nil
+}
@@ -0,0 +1,157 @@
+package reconciliations
+
+import (
+	"fmt"
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResReconciliationLineAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Lines      []models.ReconciliationLineModel `json:"lines"`
+		Pagination models.PaginationModel           `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchLines
+// @Summary      	Fetch reconciliation lines
+// @Description  	Récuperation des lignes du rapprochement paginer
+// @Tags         	Reconciliations
+// @Product       	json
+// @Param        	filter-status query string false "Statuts séparés par virgule (MATCHED,MISSING,EXTRA,AMOUNT_MISMATCH,STATE_MISMATCH)"
+// @Param        	filter-unresolved query bool false "Uniquement les écarts non traités"
+// @response      	200 {object} ResReconciliationLineAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/:id/lines [get]
+func (s *ReconciliationApiRessource) FetchLines() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		reconciliation, ok := c.Get("RECONCILIATION").(*models.ReconciliationModel)
+		if !ok {
+			err := fmt.Errorf("rapprochement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		lines := []models.ReconciliationLineModel{}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		query, _ := c.Get("QUERY").(string)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.ReconciliationLineModel{}).Where("reconciliation_id = ?", reconciliation.ID)
+
+		if status := c.QueryParam("filter-status"); status != "" {
+			reqDb = reqDb.Where("status IN (?)", strings.Split(strings.ToUpper(status), ","))
+		}
+
+		if c.QueryParam("filter-unresolved") == "true" {
+			reqDb = reqDb.Where("status <> ? AND (resolution = '' OR resolution IS NULL)", models.RECONCILIATION_MATCHED)
+		}
+
+		if len(query) > 0 {
+			reqDb = reqDb.Where("lower(provider_reference) LIKE ?", strings.ToLower("%"+query+"%"))
+		}
+
+		var count int64
+		err = fetchLinesExec(reqDb, &lines, fetchParams{
+			Orders:    orders,
+			Limit:     limit,
+			Offset:    offset,
+			Cursor:    cursor,
+			WithCount: withCount,
+		}, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Lines      []models.ReconciliationLineModel `json:"lines"`
+			Pagination models.PaginationModel           `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			lines, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(lines, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Lines:      lines,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func fetchLinesExec(
+	reqDb *gorm.DB,
+	lines *[]models.ReconciliationLineModel,
+	params fetchParams,
+	count *int64,
+) error {
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		if len(params.Orders) == 0 {
+			params.Orders = []string{"line_number asc"}
+		}
+
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.Find(lines)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
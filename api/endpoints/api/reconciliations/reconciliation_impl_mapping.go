@@ -0,0 +1,155 @@
+package reconciliations
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResMappingAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Mapping models.ReconciliationMappingModel `json:"mapping"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type MappingFormData struct {
+	Delimiter       string `json:"delimiter" form:"delimiter" xml:"delimiter" validate:"omitempty,len=1"`               // Séparateur (défaut: ,)
+	HasHeader       bool   `json:"has_header" form:"has_header" xml:"has_header" validate:"-"`                          // Le fichier contient une ligne d'entête
+	ReferenceColumn string `json:"reference_column" form:"reference_column" xml:"reference_column" validate:"required"` // Nom ou index de la colonne réference opérateur
+	AmountColumn    string `json:"amount_column" form:"amount_column" xml:"amount_column" validate:"required"`          // Nom ou index de la colonne montant
+	DateColumn      string `json:"date_column" form:"date_column" xml:"date_column" validate:"required"`                // Nom ou index de la colonne date
+	DateLayout      string `json:"date_layout" form:"date_layout" xml:"date_layout" validate:"omitempty"`               // Format go de la date (ex: 2006-01-02 15:04:05)
+	CurrencyColumn  string `json:"currency_column" form:"currency_column" xml:"currency_column" validate:"omitempty"`   // Nom ou index de la colonne devise
+}
+
+// GetMapping
+// @Summary      	Get provider statement mapping
+// @Description  	Récuperation de la correspondance des colonnes du relevé du provider
+// @Tags         	Reconciliations
+// @Product       	json
+// @response      	200 {object} ResMappingAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/mappings/:provider_id [get]
+func (s *ReconciliationApiRessource) GetMapping() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		mapping, err := getMapping(db, c.Param("provider_id"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Mapping models.ReconciliationMappingModel `json:"mapping"`
+		}
+
+		resp.SetData(resData{
+			Mapping: *mapping,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// SaveMapping
+// @Summary      	Save provider statement mapping
+// @Description  	Enregistrement de la correspondance des colonnes du relevé du provider
+// @Tags         	Reconciliations
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData MappingFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResMappingAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/mappings/:provider_id [put]
+func (s *ReconciliationApiRessource) SaveMapping() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Le provider doit exister
+		provider := models.ProviderModel{}
+		result := db.Model(&provider).Where("id = ?", c.Param("provider_id")).First(&provider)
+		if result.Error != nil {
+			err := fmt.Errorf("provider inexistant")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		data := new(MappingFormData)
+		mapping := models.ReconciliationMappingModel{}
+		err = utils.BindValidate(c, data, &mapping)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		mapping.ProviderId = provider.ID
+
+		// Mise à jour de la correspondance existante
+		existing := models.ReconciliationMappingModel{}
+		result = db.Model(&existing).Where("provider_id = ?", provider.ID).First(&existing)
+		if result.Error == nil {
+			mapping.ID = existing.ID
+			mapping.CreatedAt = existing.CreatedAt
+			result = db.Save(&mapping)
+		} else {
+			result = db.Create(&mapping)
+		}
+
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Mapping models.ReconciliationMappingModel `json:"mapping"`
+		}
+
+		resp.SetData(resData{
+			Mapping: mapping,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func getMapping(db *gorm.DB, providerId string) (*models.ReconciliationMappingModel, error) {
+	mapping := models.ReconciliationMappingModel{}
+
+	result := db.Model(&mapping).Where("provider_id = ?", providerId).First(&mapping)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("aucune correspondance de colonnes définie pour ce provider")
+		}
+
+		return nil, result.Error
+	}
+
+	return &mapping, nil
+}
@@ -0,0 +1,245 @@
+package reconciliations
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ResReconciliationAPIResolveSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Line           models.ReconciliationLineModel `json:"line"`
+		Reconciliation models.ReconciliationModel     `json:"reconciliation"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResolveFormData struct {
+	Action        string `json:"action" form:"action" xml:"action" validate:"required,oneof=ACKNOWLEDGE LINK APPLY_STATEMENT"` // ACKNOWLEDGE, LINK, APPLY_STATEMENT
+	Note          string `json:"note" form:"note" xml:"note" validate:"required"`                                              // Justification de la résolution
+	TransactionId string `json:"transaction_id" form:"transaction_id" xml:"transaction_id" validate:"omitempty,uuid"`          // Transaction à rattacher (LINK)
+}
+
+// Resolve
+// @Summary      	Resolve reconciliation line
+// @Description  	Traitement d'un écart de rapprochement
+// @Tags         	Reconciliations
+// @Product       	json
+// @Param        	data body ResolveFormData true "Action de résolution"
+// @response      	200 {object} ResReconciliationAPIResolveSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/reconciliations/:id/lines/:line_id/resolve [post]
+func (s *ReconciliationApiRessource) Resolve() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		reconciliation, ok := c.Get("RECONCILIATION").(*models.ReconciliationModel)
+		if !ok {
+			err := fmt.Errorf("rapprochement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		data := new(ResolveFormData)
+		resolve := ResolveFormData{}
+		err := utils.BindValidate(c, data, &resolve)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		resolve.Action = strings.ToUpper(resolve.Action)
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		line := models.ReconciliationLineModel{}
+		result := db.
+			Where("id = ? AND reconciliation_id = ?", c.Param("line_id"), reconciliation.ID).
+			First(&line)
+		if result.Error != nil {
+			err := fmt.Errorf("ligne de rapprochement inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if line.IsResolved() {
+			err := fmt.Errorf("ligne de rapprochement déjà traitée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Relecture verrouillée, une ligne n'est résolue qu'une fois
+			result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", line.ID).First(&line)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if line.IsResolved() {
+				return fmt.Errorf("ligne de rapprochement déjà traitée")
+			}
+
+			return resolveLine(tx, reconciliation, &line, resolve, loginUser)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Line           models.ReconciliationLineModel `json:"line"`
+			Reconciliation models.ReconciliationModel     `json:"reconciliation"`
+		}
+
+		resp.SetData(resData{
+			Line:           line,
+			Reconciliation: *reconciliation,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// resolveLine application de l'action puis mise à jour du rapport
+func resolveLine(tx *gorm.DB, reconciliation *models.ReconciliationModel, line *models.ReconciliationLineModel, resolve ResolveFormData, loginUser *models.UserModel) error {
+	switch resolve.Action {
+	case models.RESOLUTION_ACKNOWLEDGE:
+		// Aucune modification des transactions
+
+	case models.RESOLUTION_LINK:
+		if err := linkTransaction(tx, reconciliation, line, resolve.TransactionId); err != nil {
+			return err
+		}
+
+	case models.RESOLUTION_APPLY_STATEMENT:
+		if err := applyStatement(tx, line); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("action de résolution invalide")
+	}
+
+	now := time.Now()
+	line.Resolution = resolve.Action
+	line.ResolutionNote = resolve.Note
+	line.ResolvedById = loginUser.ID
+	line.ResolvedAt = &now
+
+	result := tx.Save(line)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Compteur décrémenté en base, les résolutions concurrentes ne se perdent pas
+	result = tx.Model(&models.ReconciliationModel{}).
+		Where("id = ?", reconciliation.ID).
+		Update("unresolved_count", gorm.Expr("GREATEST(unresolved_count - 1, 0)"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = tx.Model(&models.ReconciliationModel{}).
+		Where("id = ? AND unresolved_count = ?", reconciliation.ID, 0).
+		Update("state", models.RECONCILIATION_RESOLVED)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return tx.Select("unresolved_count", "state").Where("id = ?", reconciliation.ID).First(reconciliation).Error
+}
+
+// linkTransaction rattachement d'une ligne EXTRA à une transaction du même provider
+func linkTransaction(tx *gorm.DB, reconciliation *models.ReconciliationModel, line *models.ReconciliationLineModel, transactionId string) error {
+	if line.Status != models.RECONCILIATION_EXTRA {
+		return fmt.Errorf("seule une ligne sans transaction peut être rattachée")
+	}
+
+	if transactionId == "" {
+		return fmt.Errorf("transaction obligatoire")
+	}
+
+	transaction := models.TransactionModel{}
+	result := tx.
+		Where("id = ? AND provider_id = ?", transactionId, reconciliation.ProviderId).
+		First(&transaction)
+	if result.Error != nil {
+		return fmt.Errorf("transaction inexistante")
+	}
+
+	// La transaction ne doit pas déjà figurer dans ce rapprochement
+	var count int64
+	result = tx.Model(&models.ReconciliationLineModel{}).
+		Where("reconciliation_id = ? AND transaction_id = ?", reconciliation.ID, transaction.ID).
+		Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if count > 0 {
+		return fmt.Errorf("transaction déjà rapprochée")
+	}
+
+	transaction.ProviderReference = line.ProviderReference
+
+	// Le relevé fait foi sur l'état de la transaction rattachée
+	state := transaction.OperationState
+	msg := transaction.OperationMsg
+	if state != models.TRANSACTION_SUCCESS {
+		state = models.TRANSACTION_SUCCESS
+		msg = "rapprochement manuel"
+	}
+
+	if err := transaction.SetState(tx, state, msg); err != nil {
+		return err
+	}
+
+	applyTransactionMatch(line, transaction)
+
+	return nil
+}
+
+// applyStatement alignement de l'état de la transaction sur le relevé
+func applyStatement(tx *gorm.DB, line *models.ReconciliationLineModel) error {
+	var state string
+	switch line.Status {
+	case models.RECONCILIATION_STATE_MISMATCH:
+		// Présente dans le relevé, donc réussie chez l'opérateur
+		state = models.TRANSACTION_SUCCESS
+	case models.RECONCILIATION_MISSING:
+		// Absente du relevé, donc non aboutie chez l'opérateur
+		state = models.TRANSACTION_FAIL
+	default:
+		return fmt.Errorf("action non applicable au statut %v", line.Status)
+	}
+
+	transaction := models.TransactionModel{}
+	result := tx.Where("id = ?", line.TransactionId).First(&transaction)
+	if result.Error != nil {
+		return fmt.Errorf("transaction inexistante")
+	}
+
+	return transaction.SetState(tx, state, "alignement sur le relevé opérateur")
+}
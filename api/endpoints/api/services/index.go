@@ -11,6 +11,14 @@ type ServiceApiRessource struct {
 	*models.ServiceModel
 }
 
+// Colonnes autorisées pour le tri
+var serviceSorts = []string{"name", "country", "current_amount", "onboarding_state", "submitted_at", "created_at", "updated_at"}
+var permissionSorts = []string{"role", "created_at", "updated_at"}
+var eventSorts = []string{"created_at", "type", "delivered"}
+var transferSorts = []string{"amount", "created_at", "updated_at"}
+var invitationSorts = []string{"email", "state", "expires_at", "created_at", "updated_at"}
+var roleSorts = []string{"name", "created_at", "updated_at"}
+
 func AttachAPI(server *echo.Group) {
 	serviceApi := &ServiceApiRessource{&models.ServiceModel{}}
 
@@ -18,7 +26,7 @@ func AttachAPI(server *echo.Group) {
 	serviceApiService := server.Group("/services")
 	{
 		// Fetch
-		serviceApiService.GET("/", serviceApi.Fetch(), middlewares.GrantMid(), models.PaginationMid())
+		serviceApiService.GET("/", serviceApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(serviceSorts...))
 
 		// Add Service
 		serviceApiService.POST("/", serviceApi.Add(), middlewares.GrantMid())
@@ -26,28 +34,74 @@ func AttachAPI(server *echo.Group) {
 		serviceOneApiService := serviceApiService.Group("/:id", middlewares.GrantMid(), serviceApi.GetOnMid())
 		{
 			// Get Service Info
-			serviceOneApiService.GET("/", serviceApi.GetInfo())
+			serviceOneApiService.GET("/", serviceApi.GetInfo(), middlewares.PermMid(models.PERM_SERVICE_READ))
 
 			// Get Client Service Info
-			serviceOneApiService.GET("/show-client", serviceApi.GetClient())
+			serviceOneApiService.GET("/show-client", serviceApi.GetClient(), middlewares.PermMid(models.PERM_SERVICE_KEYS_READ))
 
 			// Regenerate Client Service Info
-			serviceOneApiService.POST("/regenerate-client", serviceApi.GenClient())
+			serviceOneApiService.POST("/regenerate-client", serviceApi.GenClient(), middlewares.PermMid(models.PERM_SERVICE_KEYS_ROTATE))
 
 			// Update Service Info
-			serviceOneApiService.PUT("/", serviceApi.UpdateInfo())
+			serviceOneApiService.PUT("/", serviceApi.UpdateInfo(), middlewares.PermMid(models.PERM_SERVICE_UPDATE))
 
 			// Delete Service Info
-			serviceOneApiService.DELETE("/", serviceApi.Delete())
+			serviceOneApiService.DELETE("/", serviceApi.Delete(), middlewares.PermMid(models.PERM_SERVICE_DELETE))
+
+			// Export Service Statement
+			serviceOneApiService.GET("/statement/export", serviceApi.ExportStatement(), middlewares.PermMid(models.PERM_SERVICE_STATEMENT_EXPORT))
+
+			// Settlement Config
+			serviceOneApiService.GET("/settlement-config", serviceApi.GetSettlementConfig(), middlewares.PermMid(models.PERM_SERVICE_SETTLEMENT_READ))
+			serviceOneApiService.PUT("/settlement-config", serviceApi.UpdateSettlementConfig(), middlewares.PermMid(models.PERM_SERVICE_SETTLEMENT_UPDATE))
+
+			// Logo
+			serviceOneApiService.POST("/logo", serviceApi.UploadLogo(), middlewares.PermMid(models.PERM_SERVICE_UPDATE))
+			serviceOneApiService.DELETE("/logo", serviceApi.DeleteLogo(), middlewares.PermMid(models.PERM_SERVICE_UPDATE))
+
+			// Onboarding KYC
+			serviceOneApiService.GET("/documents", serviceApi.FetchDocuments(), middlewares.PermMid(models.PERM_SERVICE_ONBOARDING))
+			serviceOneApiService.POST("/documents", serviceApi.AddDocument(), middlewares.PermMid(models.PERM_SERVICE_ONBOARDING))
+			serviceOneApiService.GET("/documents/:document_id", serviceApi.GetDocument(), middlewares.PermMid(models.PERM_SERVICE_ONBOARDING))
+			serviceOneApiService.GET("/documents/:document_id/url", serviceApi.GetDocumentUrl(), middlewares.PermMid(models.PERM_SERVICE_ONBOARDING))
+			serviceOneApiService.POST("/submit", serviceApi.SubmitOnboarding(), middlewares.PermMid(models.PERM_SERVICE_ONBOARDING))
+			serviceOneApiService.POST("/review", serviceApi.ReviewOnboarding(), middlewares.PermMid(models.PERM_PLATFORM_ONBOARDING_REVIEW))
+
+			// Internal Transfers
+			serviceOneApiService.GET("/transfers", serviceApi.FetchTransfers(), models.PaginationMid(transferSorts...), middlewares.PermMid(models.PERM_TRANSFERS_READ))
+			serviceOneApiService.POST("/transfers", serviceApi.AddTransfer(), middlewares.PermMid(models.PERM_TRANSFERS_CREATE))
+
+			// Fetch Service Events
+			serviceOneApiService.GET("/events", serviceApi.FetchEvents(), models.PaginationMid(eventSorts...), middlewares.PermMid(models.PERM_SERVICE_EVENTS_READ))
 
 			servicePermissionsApiService := serviceOneApiService.Group("/permissions")
 			{
 				// Fetch service permissions
-				servicePermissionsApiService.GET("/", serviceApi.FetchPermission(), models.PaginationMid())
+				servicePermissionsApiService.GET("/", serviceApi.FetchPermission(), models.PaginationMid(permissionSorts...), middlewares.PermMid(models.PERM_MEMBERS_READ))
 
 				// Add user to service
-				servicePermissionsApiService.POST("/add", serviceApi.AddUserToService())
+				servicePermissionsApiService.POST("/add", serviceApi.AddUserToService(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+
+				// Leave service
+				servicePermissionsApiService.POST("/leave", serviceApi.LeaveService())
+
+				// Update user role
+				servicePermissionsApiService.PUT("/:permission_id", serviceApi.UpdatePermission(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+
+				// Remove user from service
+				servicePermissionsApiService.DELETE("/:permission_id", serviceApi.RemovePermission(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
 			}
+
+			// Invitations
+			serviceOneApiService.GET("/invitations", serviceApi.FetchInvitations(), models.PaginationMid(invitationSorts...), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+			serviceOneApiService.POST("/invitations", serviceApi.AddInvitation(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+			serviceOneApiService.DELETE("/invitations/:invitation_id", serviceApi.RevokeInvitation(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+
+			// Custom Roles
+			serviceOneApiService.GET("/roles", serviceApi.FetchRoles(), middlewares.PermMid(models.PERM_MEMBERS_READ), models.PaginationMid(roleSorts...))
+			serviceOneApiService.POST("/roles", serviceApi.AddRole(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+			serviceOneApiService.PUT("/roles/:role_id", serviceApi.UpdateRole(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
+			serviceOneApiService.DELETE("/roles/:role_id", serviceApi.DeleteRole(), middlewares.PermMid(models.PERM_MEMBERS_MANAGE))
 		}
 	}
 }
@@ -44,6 +44,7 @@ func (s *ServiceApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.Handle
 			}
 
 			c.Set("SERVICE", service)
+			c.Set("SERVICE_ID", service.ID)
 
 			if err := next(c); err != nil {
 				err := fmt.Errorf("une erreur c'est produite")
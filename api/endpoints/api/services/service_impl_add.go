@@ -32,11 +32,14 @@ type AddFormData struct {
 	SiteWeb     string `json:"site_web" form:"site_web" xml:"site_web" validate:"required"`          // Site web (ex: https://www.maboutique.ci)
 	Country     string `json:"country" form:"country" xml:"country" validate:"omitempty"`            // Pays (ex: civ)
 	AuthId      string `json:"auth_id" form:"auth_id" xml:"auth_id" validate:"omitempty"`            // Id d'authentification de l'admin de la boutique
+
+	SettlementCurrency string `json:"settlement_currency" form:"settlement_currency" xml:"settlement_currency" validate:"omitempty,currency"` // Devise du solde de la boutique (ex: XOF)
 }
 
 // Add
 // @Summary      	Add new service
 // @Description  	Création d'un nouvel utilisateur
+// @Description  	La boutique est créée en DRAFT, seules les transactions sandbox sont acceptées avant approbation
 // @Tags         	Services
 // @accept 			json,xml,x-www-form-urlencoded,mpfd
 // @Product       	json
@@ -107,7 +110,7 @@ func createServiceDb(newService *models.ServiceModel, authId string, claims jwt.
 	loginUser.AuthId = claims["sub"].(string)
 
 	result := db.
-		Preload("ServicePermissions").
+		Scopes(models.PreloadGrants).
 		Where(&loginUser).First(&loginUser)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msgf("")
@@ -121,7 +124,7 @@ func createServiceDb(newService *models.ServiceModel, authId string, claims jwt.
 	userAdmin := models.UserModel{}
 
 	// // Si authId precisé
-	if loginUser.IsGrant(models.USER_MANAGER) && authId != "" {
+	if loginUser.Can(models.PERM_PLATFORM_ACT_AS, "") && authId != "" {
 		result := db.Model(&userAdmin).Where("auth_id = ?", authId).First(&userAdmin)
 		if result.Error != nil {
 			return nil, result.Error
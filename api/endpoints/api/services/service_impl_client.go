@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"spay/models"
+	"spay/utils"
 	"time"
 
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type ResServiceAPIGetClientSuccess struct {
@@ -39,42 +40,12 @@ func (s *ServiceApiRessource) GetClient() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
-		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		service, ok := c.Get("SERVICE").(*models.ServiceModel)
 		if !ok {
 			err := fmt.Errorf("boutique non valide")
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		// Controle de permission
-		// Récuperation de l'utilisateur
-		loginUser := models.UserModel{}
-		loginUser.AuthId = claims["sub"].(string)
-
-		// Connexion à la base de donnée
-		db, err := models.GetDB()
-		if err != nil {
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
-		result := db.
-			Preload("ServicePermissions").
-			Where(&loginUser).First(&loginUser)
-		if result.Error != nil {
-			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
-		}
-
-		if !loginUser.IsGrant(models.USER_MANAGER) && !loginUser.IsServiceGrant(service.ID, models.SERVICE_MANAGER) {
-			err := fmt.Errorf("permission non accordé")
-			return err
-		}
-
 		type resData struct {
 			ClientId  string `json:"client_id"`
 			ClientKey string `json:"client_key"`
@@ -95,8 +66,9 @@ type ResServiceAPIGenClientSuccess struct {
 	IsError bool   `json:"is_error"`
 
 	Data struct {
-		ClientId  string `json:"client_id"`
-		ClientKey string `json:"client_key"`
+		ClientId  string                       `json:"client_id,omitempty"`
+		ClientKey string                       `json:"client_key,omitempty"`
+		Approval  *models.ApprovalRequestModel `json:"approval,omitempty"`
 	} `json:"data"`
 
 	RequestDate time.Time `json:"request_date"`
@@ -105,10 +77,12 @@ type ResServiceAPIGenClientSuccess struct {
 
 // GenClient
 // @Summary      	Regeneration Client service data
-// @Description  	Régéneration du client de la boutique
+// @Description  	Régéneration du client de la boutique, mise en attente d'approbation (202)
+// @Description  	selon la politique service.regenerate_client
 // @Tags         	Services
 // @Product       	json
 // @response      	200 {object} ResServiceAPIGenClientSuccess
+// @response      	202 {object} ResServiceAPIGenClientSuccess
 // @response      	400 {object} models.ResFailure
 // @Router       	/api/services/:id/regenerate-client [post]
 func (s *ServiceApiRessource) GenClient() echo.HandlerFunc {
@@ -118,51 +92,71 @@ func (s *ServiceApiRessource) GenClient() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
 		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
+			err := fmt.Errorf("boutique non valide")
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
 		if !ok {
-			err := fmt.Errorf("boutique non valide")
+			err := fmt.Errorf("utilisateur non reconnu")
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		// Controle de permission
-		// Récuperation de l'utilisateur
-		loginUser := models.UserModel{}
-		loginUser.AuthId = claims["sub"].(string)
-
 		// Connexion à la base de donnée
 		db, err := models.GetDB()
 		if err != nil {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		result := db.
-			Preload("ServicePermissions").
-			Where(&loginUser).First(&loginUser)
-		if result.Error != nil {
-			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		type resData struct {
+			ClientId  string                       `json:"client_id,omitempty"`
+			ClientKey string                       `json:"client_key,omitempty"`
+			Approval  *models.ApprovalRequestModel `json:"approval,omitempty"`
 		}
 
-		if !loginUser.IsGrant(models.USER_MANAGER) && !loginUser.IsServiceGrant(service.ID, models.SERVICE_MANAGER) {
-			err := fmt.Errorf("permission non accordé")
+		var approval *models.ApprovalRequestModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			approval, err = models.RequestApproval(tx, utils.AuditActor(c), models.ApprovalRequestModel{
+				Operation:   models.APPROVAL_SERVICE_CLIENT,
+				ServiceId:   service.ID,
+				TargetId:    service.ID,
+				RequestedBy: loginUser.ID,
+			}, nil)
+			return err
+		})
+		if err != nil {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		service.GenerateClient()
-		result = db.Save(service)
-		if result.Error != nil {
-			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		// Régénération après approbation
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+			resp.SetData(resData{
+				Approval: approval,
+			})
+
+			return resp.Send(c)
 		}
 
-		type resData struct {
-			ClientId  string `json:"client_id"`
-			ClientKey string `json:"client_key"`
+		before := *service
+
+		service.GenerateClient()
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Seules les clés sont écrites, le solde est tenu par le relevé
+			result := tx.Model(&models.ServiceModel{}).Where("id = ?", service.ID).Updates(map[string]interface{}{
+				"client_id":  service.ClientId,
+				"client_key": service.ClientKey,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_REGENERATE, "service", service.ID, before, service)
+		})
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
 		resp.SetData(resData{
@@ -2,15 +2,11 @@ package services
 
 import (
 	"fmt"
-	"net/http"
 	"spay/models"
-	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
-	"gorm.io/gorm"
 )
 
 type ResServiceAPIDeleteSuccess struct {
@@ -42,13 +38,6 @@ func (s *ServiceApiRessource) Delete() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
-		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		service, ok := c.Get("SERVICE").(*models.ServiceModel)
 		if !ok {
 			err := fmt.Errorf("boutique non valide")
@@ -61,11 +50,6 @@ func (s *ServiceApiRessource) Delete() echo.HandlerFunc {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		err = checkPermission(db, service, claims)
-		if err != nil {
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		result := db.Delete(service)
 		if result.Error != nil {
 			log.Error().Err(result.Error).Msgf("")
@@ -86,27 +70,3 @@ func (s *ServiceApiRessource) Delete() echo.HandlerFunc {
 		return resp.Send(c)
 	}
 }
-
-func checkPermission(db *gorm.DB, service *models.ServiceModel, claims jwt.MapClaims) error {
-	// Récuperation de l'utilisateur
-	loginUser := models.UserModel{}
-	loginUser.AuthId = claims["sub"].(string)
-
-	result := db.
-		Preload("ServicePermissions").
-		Where(&loginUser).First(&loginUser)
-	if result.Error != nil {
-		log.Error().Err(result.Error).Msgf("")
-		if strings.Contains(result.Error.Error(), "record not found") {
-			return fmt.Errorf("utilisateur non reconnu")
-		}
-
-		return result.Error
-	}
-
-	if !loginUser.IsGrant(models.USER_MANAGER) && (loginUser.IsGrant(models.USER_MERCHANT) && !loginUser.IsServiceGrant(service.ID, models.SERVICE_MANAGER)) {
-		return fmt.Errorf("permission non accordé")
-	}
-
-	return nil
-}
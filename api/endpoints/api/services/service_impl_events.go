@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResServiceEventAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Events     []models.EventModel    `json:"events"`
+		Pagination models.PaginationModel `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchEvents
+// @Summary      	Fetch service events paginate
+// @Description  	Récuperation des événements de la boutique paginer
+// @Tags         	Services
+// @Product       	json
+// @Param        	filter-type query string false "Types séparés par virgule (ex: subscription.payment_failed)"
+// @Param        	filter-delivered query bool false "Evénements livrés ou non au webhook"
+// @response      	200 {object} ResServiceEventAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/events [get]
+func (s *ServiceApiRessource) FetchEvents() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.EventModel{}).Where("service_id = ?", service.ID)
+
+		if filter := c.QueryParam("filter-type"); filter != "" {
+			reqDb = reqDb.Where("type IN (?)", strings.Split(strings.ToLower(filter), ","))
+		}
+
+		if filter := c.QueryParam("filter-delivered"); filter != "" {
+			reqDb = reqDb.Where("delivered = ?", filter == "true")
+		}
+
+		var count int64
+		if withCount {
+			result := reqDb.Count(&count)
+			if result.Error != nil {
+				log.Error().Err(result.Error).Msgf("")
+				return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			}
+		}
+
+		if cursor != nil {
+			// Pagination par curseur
+			reqDb = models.CursorQuery(reqDb, cursor, limit)
+		} else {
+			if len(orders) == 0 {
+				orders = []string{"created_at desc"}
+			}
+
+			for _, order := range orders {
+				reqDb = reqDb.Order(order)
+			}
+
+			reqDb = reqDb.Limit(limit).Offset(offset)
+		}
+
+		events := []models.EventModel{}
+		result := reqDb.Find(&events)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type dataResponse struct {
+			Events     []models.EventModel    `json:"events"`
+			Pagination models.PaginationModel `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			events, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(events, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Events:     events,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
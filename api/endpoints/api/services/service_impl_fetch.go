@@ -62,7 +62,7 @@ func (s *ServiceApiRessource) Fetch() echo.HandlerFunc {
 		loginUser.AuthId = claims["sub"].(string)
 
 		result := db.
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where(&loginUser).First(&loginUser)
 		if result.Error != nil {
 			log.Error().Err(result.Error).Msgf("")
@@ -85,9 +85,16 @@ func (s *ServiceApiRessource) Fetch() echo.HandlerFunc {
 		offset, _ := c.Get("OFFSET").(int)
 		query, _ := c.Get("QUERY").(string)
 		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
 
 		reqDb := db.Model(&models.ServiceModel{})
 
+		// Filtre sur l'état de l'inscription (file de revue KYC)
+		if filter := c.QueryParam("filter-onboarding"); filter != "" {
+			reqDb = reqDb.Where("onboarding_state IN (?)", strings.Split(filter, ","))
+		}
+
 		var count int64
 		err = fetchExec(
 			reqDb,
@@ -100,6 +107,8 @@ func (s *ServiceApiRessource) Fetch() echo.HandlerFunc {
 				Query:         query,
 				Limit:         limit,
 				Offset:        offset,
+				Cursor:        cursor,
+				WithCount:     withCount,
 			},
 			&count,
 		)
@@ -108,19 +117,37 @@ func (s *ServiceApiRessource) Fetch() echo.HandlerFunc {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
+		for i := range services {
+			if err := utils.SignServiceLogo(&services[i]); err != nil {
+				log.Error().Err(err).Msgf("")
+				break
+			}
+		}
+
 		type dataResponse struct {
 			Services   []models.ServiceModel  `json:"services"`
 			Pagination models.PaginationModel `json:"pagination"`
 		}
 
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			services, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(services, cursor, limit)
+			pagination.Offset = 0
+		}
+
 		resp.SetData(dataResponse{
-			Services: services,
-			Pagination: models.PaginationModel{
-				Count:  count,
-				Limit:  limit,
-				Offset: offset,
-				Query:  query,
-			},
+			Services:   services,
+			Pagination: pagination,
 		})
 
 		return resp.Send(c)
@@ -128,15 +155,7 @@ func (s *ServiceApiRessource) Fetch() echo.HandlerFunc {
 }
 
 func fetchRestricted(reqDb *gorm.DB, db *gorm.DB, loginUser models.UserModel) (*gorm.DB, error) {
-	if loginUser.Role == models.USER_MERCHANT {
-		serviceIds := []string{}
-
-		for _, perm := range loginUser.ServicePermissions {
-			if loginUser.IsServiceGrant(perm.ServiceId, models.SERVICE_DEV) {
-				serviceIds = append(serviceIds, perm.ServiceId)
-			}
-		}
-
+	if serviceIds, all := loginUser.ServicesWith(models.PERM_SERVICE_READ); !all {
 		reqDb = reqDb.Where("id IN (?)", serviceIds)
 	}
 
@@ -170,7 +189,7 @@ func fetchFilterUser(reqDb *gorm.DB, db *gorm.DB, filter string) (*gorm.DB, erro
 
 		resultUser := db.
 			Model(&models.UserModel{}).
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where("id = ?", user.ID).
 			First(&user)
 
@@ -225,19 +244,28 @@ func fetchExec(
 		return err
 	}
 
-	result := reqDb.Count(count)
-	if result.Error != nil {
-		return result.Error
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
 	}
 
-	// Orders
-	reqDb = fetchOrder(reqDb, params.Orders)
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
 
-	result = reqDb.
+	result := reqDb.
 		Preload("Permissions").
 		Preload("Permissions.User").
-		Limit(int(params.Limit)).
-		Offset(int(params.Offset)).
 		Find(services)
 	if result.Error != nil {
 		return result.Error
@@ -252,4 +280,6 @@ type fetchParams struct {
 	Query         string
 	Limit         int
 	Offset        int
+	Cursor        *models.Cursor
+	WithCount     bool
 }
@@ -3,9 +3,11 @@ package services
 import (
 	"fmt"
 	"spay/models"
+	"spay/utils"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 )
 
 type ResServiceAPIGetSuccess struct {
@@ -42,6 +44,11 @@ func (s *ServiceApiRessource) GetInfo() echo.HandlerFunc {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
+		// Adresses signées du logo, un échec n'empêche pas la lecture
+		if err := utils.SignServiceLogo(service); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
 		type resData struct {
 			Service models.ServiceModel `json:"service"`
 		}
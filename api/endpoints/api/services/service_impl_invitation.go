@@ -0,0 +1,295 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResServiceInvitationAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Invitation models.ServiceInvitationModel `json:"invitation"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResServiceInvitationAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Invitations []models.ServiceInvitationModel `json:"invitations"`
+		Pagination  models.PaginationModel          `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type InvitationFormData struct {
+	Email string `json:"email" form:"email" xml:"email" validate:"required,email"` // Adresse e-mail invitée
+	Role  int    `json:"role" form:"role" xml:"role" validate:"min=0,max=2"`       // Role: 0: DEV, 1: MANAGER, 2: ADMIN
+}
+
+// AddInvitation
+// @Summary      	Invite user to service
+// @Description  	Invitation d'une adresse e-mail à rejoindre la boutique avec un rôle,
+// @Description  	le jeton est envoyé par e-mail et expire après INVITATION_TTL
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData InvitationFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceInvitationAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/invitations [post]
+func (s *ServiceApiRessource) AddInvitation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(InvitationFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		invitation := models.ServiceInvitationModel{
+			ServiceId:   service.ID,
+			Email:       data.Email,
+			Role:        models.ServiceRole(data.Role),
+			InvitedById: loginUser.ID,
+		}
+
+		var token string
+		err = db.Transaction(func(tx *gorm.DB) error {
+			token, err = models.CreateInvitation(tx, &invitation, utils.InvitationTtl(), time.Now())
+			if err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_INVITE, "invitation", invitation.ID, nil, invitation)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Le jeton n'est transmis qu'à l'adresse invitée
+		utils.SendInvitation(invitation, service.Name, token)
+
+		type resData struct {
+			Invitation models.ServiceInvitationModel `json:"invitation"`
+		}
+
+		resp.SetData(resData{
+			Invitation: invitation,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// FetchInvitations
+// @Summary      	Fetch service invitations paginate
+// @Description  	Récuperation des invitations de la boutique paginer, en attente par défaut
+// @Description  	(filter-state=PENDING,ACCEPTED,REVOKED ou ALL), les invitations expirées sont exclues des invitations en attente
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceInvitationAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/invitations [get]
+func (s *ServiceApiRessource) FetchInvitations() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.ServiceInvitationModel{}).Where("service_id = ?", service.ID)
+
+		state := c.QueryParam("filter-state")
+		switch state {
+		case "", models.INVITATION_PENDING:
+			reqDb = reqDb.Where("state = ? AND expires_at > ?", models.INVITATION_PENDING, time.Now())
+
+		case "ALL":
+
+		default:
+			reqDb = reqDb.Where("state IN (?)", strings.Split(state, ","))
+		}
+
+		var count int64
+		if withCount {
+			result := reqDb.Count(&count)
+			if result.Error != nil {
+				log.Error().Err(result.Error).Msgf("")
+				return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			}
+		}
+
+		if cursor != nil {
+			// Pagination par curseur
+			reqDb = models.CursorQuery(reqDb, cursor, limit)
+		} else {
+			if len(orders) == 0 {
+				orders = []string{"created_at desc"}
+			}
+
+			for _, order := range orders {
+				reqDb = reqDb.Order(order)
+			}
+
+			reqDb = reqDb.Limit(limit).Offset(offset)
+		}
+
+		invitations := []models.ServiceInvitationModel{}
+		result := reqDb.Find(&invitations)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type dataResponse struct {
+			Invitations []models.ServiceInvitationModel `json:"invitations"`
+			Pagination  models.PaginationModel          `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			invitations, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(invitations, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Invitations: invitations,
+			Pagination:  pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// RevokeInvitation
+// @Summary      	Revoke service invitation
+// @Description  	Révocation d'une invitation en attente, le jeton envoyé n'est plus acceptable
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceInvitationAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/invitations/:invitation_id [delete]
+func (s *ServiceApiRessource) RevokeInvitation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		invitation := models.ServiceInvitationModel{}
+		result := db.Where("id = ? AND service_id = ?", c.Param("invitation_id"), service.ID).Limit(1).Find(&invitation)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("invitation inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := invitation
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := invitation.Revoke(tx, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_INVITE_REVOKE, "invitation", invitation.ID, before, invitation)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Invitation models.ServiceInvitationModel `json:"invitation"`
+		}
+
+		resp.SetData(resData{
+			Invitation: invitation,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResServiceFileUrlAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Url       string    `json:"url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// UploadLogo
+// @Summary      	Upload service logo
+// @Description  	Envoi du logo de la boutique (jpeg, png, 2 Mo maximum), une miniature png est générée,
+// @Description  	le logo et la miniature sont servis par adresses signées (logo_url, logo_thumbnail_url)
+// @Tags         	Services
+// @accept 			mpfd
+// @Product       	json
+// @Param        	file formData file true "Logo"
+// @response      	200 {object} ResServiceAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/logo [post]
+func (s *ServiceApiRessource) UploadLogo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			err := fmt.Errorf("fichier obligatoire")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		name, err := uuid.NewV4()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		logo, thumbnail, err := utils.SaveLogo(file, service.ID, name.String())
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		oldLogo, oldThumbnail := service.LogoPath, service.LogoThumbnailPath
+
+		result := db.Model(service).Updates(map[string]interface{}{
+			"logo_path":           logo.Path,
+			"logo_thumbnail_path": thumbnail.Path,
+		})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+
+			// Les fichiers sans enregistrement sont supprimés
+			utils.RemoveUpload(logo.Path)
+			utils.RemoveUpload(thumbnail.Path)
+
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		removeLogo(oldLogo, oldThumbnail)
+
+		service.LogoPath, service.LogoThumbnailPath = logo.Path, thumbnail.Path
+		if err := utils.SignServiceLogo(service); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		type resData struct {
+			Service models.ServiceModel `json:"service"`
+		}
+
+		resp.SetData(resData{
+			Service: *service,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// DeleteLogo
+// @Summary      	Delete service logo
+// @Description  	Suppression du logo envoyé et de sa miniature
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/logo [delete]
+func (s *ServiceApiRessource) DeleteLogo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if service.LogoPath == "" {
+			err := fmt.Errorf("aucun logo envoyé")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Model(service).Updates(map[string]interface{}{
+			"logo_path":           "",
+			"logo_thumbnail_path": "",
+		})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		removeLogo(service.LogoPath, service.LogoThumbnailPath)
+		service.LogoPath, service.LogoThumbnailPath = "", ""
+
+		type resData struct {
+			Service models.ServiceModel `json:"service"`
+		}
+
+		resp.SetData(resData{
+			Service: *service,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// GetDocumentUrl
+// @Summary      	Get service KYC document url
+// @Description  	Adresse signée de téléchargement d'une pièce KYC, valable STORAGE_URL_TTL
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceFileUrlAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/documents/:document_id/url [get]
+func (s *ServiceApiRessource) GetDocumentUrl() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		document := models.ServiceDocumentModel{}
+		result := db.Where("id = ? AND service_id = ?", c.Param("document_id"), service.ID).Limit(1).Find(&document)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("pièce KYC inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		url, expiresAt, err := utils.SignedUrl(document.Path, document.FileName)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Url       string    `json:"url"`
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+
+		resp.SetData(resData{
+			Url:       url,
+			ExpiresAt: expiresAt,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// removeLogo suppression des fichiers d'un logo remplacé, un échec est seulement journalisé
+func removeLogo(paths ...string) {
+	for _, path := range paths {
+		if err := utils.RemoveUpload(path); err != nil {
+			log.Error().Err(err).Str("path", path).Msgf("")
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResServiceOnboardingAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Service models.ServiceModel `json:"service"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResServiceDocumentAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Document models.ServiceDocumentModel `json:"document"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResServiceDocumentAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Documents []models.ServiceDocumentModel `json:"documents"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ReviewOnboardingFormData struct {
+	State  string `json:"state" form:"state" xml:"state" validate:"required,oneof=APPROVED DRAFT SUSPENDED"` // DRAFT pour rejeter l'inscription
+	Reason string `json:"reason" form:"reason" xml:"reason" validate:"omitempty"`                            // Obligatoire pour un rejet ou une suspension
+}
+
+// FetchDocuments
+// @Summary      	Fetch service KYC documents
+// @Description  	Liste des pièces KYC déposées pour l'inscription de la boutique
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceDocumentAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/documents [get]
+func (s *ServiceApiRessource) FetchDocuments() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		documents := []models.ServiceDocumentModel{}
+		result := db.Where("service_id = ?", service.ID).Order("created_at").Find(&documents)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Documents []models.ServiceDocumentModel `json:"documents"`
+		}
+
+		resp.SetData(resData{
+			Documents: documents,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// AddDocument
+// @Summary      	Upload service KYC document
+// @Description  	Dépôt d'une pièce KYC (pdf, jpeg, png) par un administrateur de la boutique,
+// @Description  	impossible une fois l'inscription soumise ou approuvée
+// @Tags         	Services
+// @accept 			mpfd
+// @Product       	json
+// @Param        	file formData file true "Pièce KYC"
+// @Param        	kind formData string true "ID_CARD, BUSINESS_REGISTRATION, TAX_CERTIFICATE, PROOF_OF_ADDRESS, BANK_DETAILS, OTHER"
+// @Param        	description formData string false "Description de la pièce"
+// @response      	200 {object} ResServiceDocumentAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/documents [post]
+func (s *ServiceApiRessource) AddDocument() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if service.OnboardingState != models.ONBOARDING_DRAFT && service.OnboardingState != models.ONBOARDING_SUSPENDED {
+			err := fmt.Errorf("inscription en cours de revue ou déjà approuvée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		document := models.ServiceDocumentModel{
+			Kind:         c.FormValue("kind"),
+			Description:  c.FormValue("description"),
+			ServiceId:    service.ID,
+			UploadedById: loginUser.ID,
+		}
+
+		if err := c.Validate(document); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			err := fmt.Errorf("fichier obligatoire")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		name, err := uuid.NewV4()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		uploaded, err := utils.SaveUpload(file, "services/"+service.ID+"/kyc", name.String(), utils.DocumentContentTypes)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		document.FileName = uploaded.FileName
+		document.ContentType = uploaded.ContentType
+		document.Size = uploaded.Size
+		document.Path = uploaded.Path
+
+		if err := db.Create(&document).Error; err != nil {
+			log.Error().Err(err).Msgf("")
+
+			// Le fichier sans enregistrement est supprimé
+			utils.RemoveUpload(uploaded.Path)
+
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Document models.ServiceDocumentModel `json:"document"`
+		}
+
+		resp.SetData(resData{
+			Document: document,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// GetDocument
+// @Summary      	Download service KYC document
+// @Description  	Téléchargement d'une pièce KYC de la boutique
+// @Tags         	Services
+// @Product       	octet-stream
+// @response      	200 {file} binary
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/documents/:document_id [get]
+func (s *ServiceApiRessource) GetDocument() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		document := models.ServiceDocumentModel{}
+		result := db.Where("id = ? AND service_id = ?", c.Param("document_id"), service.ID).Limit(1).Find(&document)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("pièce KYC inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := utils.SendUpload(c, document.Path, document.FileName, document.ContentType, false); err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		return nil
+	}
+}
+
+// SubmitOnboarding
+// @Summary      	Submit service onboarding
+// @Description  	Soumission de l'inscription de la boutique à la revue des gestionnaires,
+// @Description  	les pièces ID_CARD et BUSINESS_REGISTRATION sont obligatoires
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceOnboardingAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/submit [post]
+func (s *ServiceApiRessource) SubmitOnboarding() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := *service
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := service.SubmitOnboarding(tx, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_ONBOARDING, "service", service.ID, before, service)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Service models.ServiceModel `json:"service"`
+		}
+
+		resp.SetData(resData{
+			Service: *service,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// ReviewOnboarding
+// @Summary      	Review service onboarding
+// @Description  	Décision d'un gestionnaire sur l'inscription: approbation, rejet (retour en DRAFT)
+// @Description  	ou suspension d'une boutique approuvée, le motif est obligatoire sauf pour une approbation
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData ReviewOnboardingFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceOnboardingAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/review [post]
+func (s *ServiceApiRessource) ReviewOnboarding() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(ReviewOnboardingFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := *service
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := service.ReviewOnboarding(tx, data.State, data.Reason, loginUser.ID, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_ONBOARDING, "service", service.ID, before, service)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		utils.NotifyOnboarding(db, *service)
+
+		type resData struct {
+			Service models.ServiceModel `json:"service"`
+		}
+
+		resp.SetData(resData{
+			Service: *service,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResServiceSettlementConfigAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Config models.SettlementConfigModel `json:"config"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type SettlementConfigFormData struct {
+	Active *bool `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"` // Versements automatiques, true par défaut
+
+	Method         string `json:"method" form:"method" xml:"method" validate:"required,oneof=BANK WALLET"`                      // BANK, WALLET
+	AccountName    string `json:"account_name" form:"account_name" xml:"account_name" validate:"required"`                      // Titulaire du compte
+	BankName       string `json:"bank_name" form:"bank_name" xml:"bank_name" validate:"required_if=Method BANK"`                // Banque
+	AccountNumber  string `json:"account_number" form:"account_number" xml:"account_number" validate:"required_if=Method BANK"` // IBAN ou RIB
+	WalletPhone    string `json:"wallet_phone" form:"wallet_phone" xml:"wallet_phone" validate:"required_if=Method WALLET"`     // Numéro du compte mobile money
+	WalletOperator string `json:"wallet_operator" form:"wallet_operator" xml:"wallet_operator" validate:"omitempty"`            // Opérateur mobile money (ORANGE, MTN, MOOV)
+
+	Schedule    string  `json:"schedule" form:"schedule" xml:"schedule" validate:"required,oneof=DAILY WEEKLY MONTHLY"` // DAILY, WEEKLY, MONTHLY
+	ScheduleDay int     `json:"schedule_day" form:"schedule_day" xml:"schedule_day" validate:"gte=0,lte=28"`            // WEEKLY: 0 (dimanche) à 6, MONTHLY: 1 à 28
+	MinAmount   float64 `json:"min_amount" form:"min_amount" xml:"min_amount" validate:"gte=0"`                         // Versement minimum
+
+	// Fixés par les gestionnaires de la plateforme
+	ReservePercent float64 `json:"reserve_percent" form:"reserve_percent" xml:"reserve_percent" validate:"gte=0,lte=100"` // Part du solde conservée en réserve
+	FeeFixed       float64 `json:"fee_fixed" form:"fee_fixed" xml:"fee_fixed" validate:"gte=0"`                           // Frais fixes par versement
+	FeePercent     float64 `json:"fee_percent" form:"fee_percent" xml:"fee_percent" validate:"gte=0,lte=100"`             // Frais proportionnels au versement
+}
+
+// GetSettlementConfig
+// @Summary      	Get service settlement config
+// @Description  	Récuperation de la configuration des versements de la boutique
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceSettlementConfigAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/settlement-config [get]
+func (s *ServiceApiRessource) GetSettlementConfig() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		config := models.SettlementConfigModel{}
+		result := db.Where("service_id = ?", service.ID).Limit(1).Find(&config)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("versements non configurés pour cette boutique")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Config models.SettlementConfigModel `json:"config"`
+		}
+
+		resp.SetData(resData{
+			Config: config,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// UpdateSettlementConfig
+// @Summary      	Set service settlement config
+// @Description  	Configuration du compte, du calendrier et du minimum des versements de la boutique,
+// @Description  	la réserve et les frais ne sont modifiables que par les gestionnaires de la plateforme
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData SettlementConfigFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceSettlementConfigAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/settlement-config [put]
+func (s *ServiceApiRessource) UpdateSettlementConfig() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if service.SettlementCurrency == "" {
+			err := fmt.Errorf("devise de règlement de la boutique non définie")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		config := models.SettlementConfigModel{}
+		result := db.Where("service_id = ?", service.ID).Limit(1).Find(&config)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		previous := config
+
+		// Récuperation des données du formulaire
+		data := new(SettlementConfigFormData)
+		err = utils.BindValidate(c, data, &config)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Réserve et frais conservés pour les utilisateurs de la boutique
+		if !loginUser.Can(models.PERM_PLATFORM_SETTLEMENTS, "") {
+			config.ReservePercent = previous.ReservePercent
+			config.FeeFixed = previous.FeeFixed
+			config.FeePercent = previous.FeePercent
+		}
+
+		if config.Method == models.SETTLEMENT_METHOD_WALLET {
+			config.WalletPhone, err = utils.NormalizePhone(config.WalletPhone)
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+		}
+
+		if config.Schedule == models.SETTLEMENT_WEEKLY && config.ScheduleDay > 6 {
+			err := fmt.Errorf("jour de la semaine invalide, 0 (dimanche) à 6 attendu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		config.Active = data.Active == nil || *data.Active
+
+		// Prochain versement recalculé au changement de calendrier
+		if config.NextRunAt == nil || config.Schedule != previous.Schedule || config.ScheduleDay != previous.ScheduleDay {
+			nextRunAt := config.NextRun(time.Now())
+			config.NextRunAt = &nextRunAt
+		}
+
+		config.ServiceId = service.ID
+
+		result = db.Save(&config)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Config models.SettlementConfigModel `json:"config"`
+		}
+
+		resp.SetData(resData{
+			Config: config,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// ExportStatement
+// @Summary      	Export service statement
+// @Description  	Export en flux du relevé de la boutique au format csv ou xlsx
+// @Tags         	Services
+// @Produce       	text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        	format query string false "csv (par défaut) ou xlsx"
+// @Param        	filter-date-from query string false "Date minimum (ex: 2023-01-31)"
+// @Param        	filter-date-to query string false "Date maximum incluse (ex: 2023-01-31)"
+// @response      	200 {file} file
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/statement/export [get]
+func (s *ServiceApiRessource) ExportStatement() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		format := strings.ToLower(c.QueryParam("format"))
+		if format == "" {
+			format = utils.EXPORT_CSV
+		}
+
+		if format != utils.EXPORT_CSV && format != utils.EXPORT_XLSX {
+			err := fmt.Errorf("format d'export inconnu %v, csv ou xlsx attendu", format)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		reqDb := db.Model(&models.LedgerEntryModel{}).Where("service_id = ?", service.ID)
+
+		if from := c.QueryParam("filter-date-from"); from != "" {
+			ok, date := utils.StringToDate(from, utils.LAYOUTS_TIME)
+			if !ok {
+				err := fmt.Errorf("filtre date de début invalide %v", from)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			reqDb = reqDb.Where("created_at >= ?", date)
+		}
+
+		if to := c.QueryParam("filter-date-to"); to != "" {
+			ok, date := utils.StringToDate(to, utils.LAYOUTS_TIME)
+			if !ok {
+				err := fmt.Errorf("filtre date de fin invalide %v", to)
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			reqDb = reqDb.Where("created_at < ?", date.AddDate(0, 0, 1))
+		}
+
+		reqDb = reqDb.Order("created_at asc").Order("id asc")
+
+		// Envoi en flux, aucune réponse json n'est possible après les entêtes
+		fileName := fmt.Sprintf("releve-%v-%v.%v", service.NameSlug, time.Now().Format("20060102-150405"), format)
+		c.Response().Header().Set(echo.HeaderContentType, utils.ExportContentType(format))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+		c.Response().WriteHeader(http.StatusOK)
+
+		writer, err := utils.NewExportWriter(format, c.Response(), "releve")
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return nil
+		}
+
+		count, err := utils.ExportLedger(db, reqDb, writer)
+		if err != nil {
+			log.Error().Err(err).Int64("rows", count).Msgf("export relevé interrompu")
+		}
+
+		if err := writer.Close(); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		return nil
+	}
+}
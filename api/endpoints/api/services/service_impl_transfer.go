@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResServiceTransferAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Transfer models.TransferModel         `json:"transfer"`
+		Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResServiceTransferAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Transfers  []models.TransferModel `json:"transfers"`
+		Pagination models.PaginationModel `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type TransferFormData struct {
+	DestinationServiceId string  `json:"destination_service_id" form:"destination_service_id" xml:"destination_service_id" validate:"required"` // Boutique créditée
+	Amount               float64 `json:"amount" form:"amount" xml:"amount" validate:"required,gt=0"`                                            // Montant dans la devise de règlement de la boutique
+	Label                string  `json:"label" form:"label" xml:"label" validate:"omitempty"`                                                   // Libellé repris dans les relevés
+}
+
+// AddTransfer
+// @Summary      	Add service transfer
+// @Description  	Virement de fonds de la boutique vers une autre boutique, exécuté immédiatement
+// @Description  	ou mis en attente d'approbation (202) selon la politique transfer.create
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData TransferFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceTransferAPISuccess
+// @response      	202 {object} ResServiceTransferAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/transfers [post]
+func (s *ServiceApiRessource) AddTransfer() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("utilisateur non reconnu")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		transfer := models.TransferModel{}
+		data := new(TransferFormData)
+		err = utils.BindValidate(c, data, &transfer)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// La destination doit être accessible à l'utilisateur
+		if !loginUser.Can(models.PERM_TRANSFERS_READ, transfer.DestinationServiceId) {
+			err := fmt.Errorf("permission non accordé sur la boutique de destination")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		transfer.SourceServiceId = service.ID
+		transfer.RequestedBy = loginUser.ID
+
+		var approval *models.ApprovalRequestModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+
+			if err := models.CheckTransfer(tx, &transfer, now); err != nil {
+				return err
+			}
+
+			// Mise en attente selon la politique d'approbation
+			approval, err = models.RequestApproval(tx, utils.AuditActor(c), models.ApprovalRequestModel{
+				Operation:   models.APPROVAL_TRANSFER,
+				ServiceId:   service.ID,
+				Amount:      transfer.Amount,
+				Currency:    transfer.Currency,
+				RequestedBy: loginUser.ID,
+			}, transfer)
+			if err != nil || approval != nil {
+				return err
+			}
+
+			if err := models.CreateTransfer(tx, &transfer, now); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_TRANSFER, "transfer", transfer.ID, nil, transfer)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+		}
+
+		type resData struct {
+			Transfer models.TransferModel         `json:"transfer"`
+			Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
+		}
+
+		resp.SetData(resData{
+			Transfer: transfer,
+			Approval: approval,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// FetchTransfers
+// @Summary      	Fetch service transfers paginate
+// @Description  	Récuperation des virements émis et reçus par la boutique paginer
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceTransferAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/transfers [get]
+func (s *ServiceApiRessource) FetchTransfers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.TransferModel{}).
+			Where("source_service_id = ? OR destination_service_id = ?", service.ID, service.ID)
+
+		var count int64
+		if withCount {
+			result := reqDb.Count(&count)
+			if result.Error != nil {
+				log.Error().Err(result.Error).Msgf("")
+				return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			}
+		}
+
+		if cursor != nil {
+			// Pagination par curseur
+			reqDb = models.CursorQuery(reqDb, cursor, limit)
+		} else {
+			if len(orders) == 0 {
+				orders = []string{"created_at desc"}
+			}
+
+			for _, order := range orders {
+				reqDb = reqDb.Order(order)
+			}
+
+			reqDb = reqDb.Limit(limit).Offset(offset)
+		}
+
+		transfers := []models.TransferModel{}
+		result := reqDb.Find(&transfers)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type dataResponse struct {
+			Transfers  []models.TransferModel `json:"transfers"`
+			Pagination models.PaginationModel `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			transfers, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(transfers, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Transfers:  transfers,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -3,14 +3,12 @@ package services
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"spay/models"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gobeam/stringy"
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 )
@@ -29,10 +27,13 @@ type ResServiceAPIUpdateSuccess struct {
 }
 
 type UpdateFormData struct {
-	Name        string `json:"name,omitempty" form:"name" xml:"name" validate:"omitempty"`                      // Nom de la boutique
-	Description string `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"` // Une courte description de la boutiuque
-	SiteWeb     string `json:"site_web,omitempty" form:"site_web" xml:"site_web" validate:"omitempty"`          // Site web (ex: https://www.maboutique.ci)
-	Country     string `json:"country,omitempty" form:"country" xml:"country" validate:"omitempty"`             // Pays (ex: civ)
+	Name        string `json:"name,omitempty" form:"name" xml:"name" validate:"omitempty"`                          // Nom de la boutique
+	Description string `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"`     // Une courte description de la boutiuque
+	SiteWeb     string `json:"site_web,omitempty" form:"site_web" xml:"site_web" validate:"omitempty"`              // Site web (ex: https://www.maboutique.ci)
+	Country     string `json:"country,omitempty" form:"country" xml:"country" validate:"omitempty"`                 // Pays (ex: civ)
+	WebhookUrl  string `json:"webhook_url,omitempty" form:"webhook_url" xml:"webhook_url" validate:"omitempty,url"` // Adresse de réception des événements
+
+	SettlementCurrency string `json:"settlement_currency,omitempty" form:"settlement_currency" xml:"settlement_currency" validate:"omitempty,currency"` // Devise du solde, modifiable tant que le solde est nul
 }
 
 // UpdateInfo
@@ -52,13 +53,6 @@ func (s *ServiceApiRessource) UpdateInfo() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
-		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		// Récuperation de l'utilisateur à modifier
 		updateService, ok := c.Get("SERVICE").(*models.ServiceModel)
 		if !ok {
@@ -93,7 +87,7 @@ func (s *ServiceApiRessource) UpdateInfo() echo.HandlerFunc {
 			return resp.SendError(c, "Formulaire invalide", models.TransformErr(errorsApi))
 		}
 
-		err := checkForUpdateService(claims, updateService, data, c)
+		err := checkForUpdateService(updateService, data, c)
 		if err != nil {
 			log.Error().Err(err).Msgf("")
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
@@ -111,27 +105,17 @@ func (s *ServiceApiRessource) UpdateInfo() echo.HandlerFunc {
 	}
 }
 
-func checkForUpdateService(claims jwt.MapClaims, updateService *models.ServiceModel, formData *UpdateFormData, c echo.Context) error {
-	// Récuperation de l'utilisateur
-	loginUser := models.UserModel{}
-	loginUser.AuthId = claims["sub"].(string)
-
+func checkForUpdateService(updateService *models.ServiceModel, formData *UpdateFormData, c echo.Context) error {
 	// Connexion à la base de donnée
 	db, err := models.GetDB()
 	if err != nil {
 		return err
 	}
 
-	result := db.
-		Preload("ServicePermissions").
-		Where(&loginUser).First(&loginUser)
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if !loginUser.IsGrant(models.USER_MANAGER) && !loginUser.IsServiceGrant(updateService.ID, models.SERVICE_MANAGER) {
-		err := fmt.Errorf("permission non accordé")
-		return err
+	// Le solde est tenu dans une seule devise
+	currencyChanged := formData.SettlementCurrency != "" && !strings.EqualFold(formData.SettlementCurrency, updateService.SettlementCurrency)
+	if currencyChanged && updateService.CurrentAmount != 0 {
+		return fmt.Errorf("devise de règlement non modifiable, le solde de la boutique n'est pas nul")
 	}
 
 	// Convert to json
@@ -165,14 +149,27 @@ func checkForUpdateService(claims jwt.MapClaims, updateService *models.ServiceMo
 		return errorsApi
 	}
 
-	// Mise à jour des informations utilisateurs
-	result = db.Save(updateService)
+	// Mise à jour des seuls champs du formulaire, le solde et l'état d'inscription
+	// sont tenus par le relevé et la revue et ne sont jamais écrits ici
+	reqDb := db.Model(updateService).
+		Select("name", "name_slug", "description", "site_web", "country", "webhook_url", "settlement_currency", "updated_at")
+
+	// Changement de devise uniquement sur un solde toujours nul
+	if currencyChanged {
+		reqDb = reqDb.Where("current_amount = ?", 0)
+	}
+
+	result := reqDb.Updates(updateService)
 	if result.Error != nil {
 		if strings.Contains(result.Error.Error(), "duplicate key value violates") {
 			return fmt.Errorf("impossible d'effectuer la modification, donnée dupliquée detecter")
 		}
 
-		return err
+		return result.Error
+	}
+
+	if currencyChanged && result.RowsAffected == 0 {
+		return fmt.Errorf("devise de règlement non modifiable, le solde de la boutique n'est pas nul")
 	}
 
 	return nil
@@ -2,14 +2,13 @@ package services
 
 import (
 	"fmt"
-	"net/http"
 	"spay/models"
+	"spay/utils"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gobeam/stringy"
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
@@ -43,13 +42,6 @@ func (s *ServiceApiRessource) FetchPermission() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
-		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		service, ok := c.Get("SERVICE").(*models.ServiceModel)
 		if !ok {
 			err := fmt.Errorf("boutique non valide")
@@ -62,17 +54,14 @@ func (s *ServiceApiRessource) FetchPermission() echo.HandlerFunc {
 		}
 
 		// Récuperation de l'utilisateur
-		err = checkUserPerm(claims, db, *service, models.SERVICE_DEV)
-		if err != nil {
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		permissions := []models.ServicePermissionModel{}
 
 		limit, _ := c.Get("LIMIT").(int)
 		offset, _ := c.Get("OFFSET").(int)
 		query, _ := c.Get("QUERY").(string)
 		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
 
 		reqDb := db.Model(&models.ServicePermissionModel{})
 
@@ -82,10 +71,12 @@ func (s *ServiceApiRessource) FetchPermission() echo.HandlerFunc {
 			*service,
 			&permissions,
 			fetchParams{
-				Orders: orders,
-				Query:  query,
-				Limit:  limit,
-				Offset: offset,
+				Orders:    orders,
+				Query:     query,
+				Limit:     limit,
+				Offset:    offset,
+				Cursor:    cursor,
+				WithCount: withCount,
 			},
 			&count,
 		)
@@ -99,41 +90,29 @@ func (s *ServiceApiRessource) FetchPermission() echo.HandlerFunc {
 			Pagination  models.PaginationModel          `json:"pagination"`
 		}
 
-		resp.SetData(dataResponse{
-			Permissions: permissions,
-			Pagination: models.PaginationModel{
-				Count:  count,
-				Limit:  limit,
-				Offset: offset,
-				Query:  query,
-			},
-		})
-
-		return resp.Send(c)
-	}
-}
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
 
-func checkUserPerm(claims jwt.MapClaims, db *gorm.DB, service models.ServiceModel, role models.ServiceRole) error {
-	loginUser := models.UserModel{}
-	loginUser.AuthId = claims["sub"].(string)
+		if withCount {
+			pagination.Count = &count
+		}
 
-	result := db.
-		Preload("ServicePermissions").
-		Where(&loginUser).First(&loginUser)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "record not found") {
-			return fmt.Errorf("utilisateur inexistant")
+		// Pagination par curseur
+		if cursor != nil {
+			permissions, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(permissions, cursor, limit)
+			pagination.Offset = 0
 		}
 
-		return result.Error
-	}
+		resp.SetData(dataResponse{
+			Permissions: permissions,
+			Pagination:  pagination,
+		})
 
-	if !loginUser.IsGrant(models.USER_MANAGER) && !(loginUser.IsGrant(models.USER_MERCHANT) && loginUser.IsServiceGrant(service.ID, role)) {
-		err := fmt.Errorf("permission non accordé perms")
-		return err
+		return resp.Send(c)
 	}
-
-	return nil
 }
 
 func fetchPermOrder(reqDb *gorm.DB, orders []string) *gorm.DB {
@@ -153,19 +132,29 @@ func fetchPermExec(
 	params fetchParams,
 	count *int64,
 ) error {
-	result := reqDb.Count(count)
-	if result.Error != nil {
-		return result.Error
+	reqDb = reqDb.Where("service_id = ?", service.ID)
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
 	}
 
-	// Orders
-	reqDb = fetchPermOrder(reqDb, params.Orders)
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchPermOrder(reqDb, params.Orders)
 
-	result = reqDb.
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.
 		Preload("Service").
-		Where("service_id = ?", service.ID).
-		Limit(int(params.Limit)).
-		Offset(int(params.Offset)).
 		Find(permissions)
 	if result.Error != nil {
 		return result.Error
@@ -209,13 +198,6 @@ func (s *ServiceApiRessource) AddUserToService() echo.HandlerFunc {
 			resp = models.NewResponseAPI[interface{}]()
 		}
 
-		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
-		if !ok {
-			err := fmt.Errorf("authentification obligatoire")
-			resp.SetStatus(http.StatusUnauthorized)
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		// Récuperation des données du formulaire
 		data := new(AddPermissionFormData)
 		if err := c.Bind(data); err != nil {
@@ -254,18 +236,7 @@ func (s *ServiceApiRessource) AddUserToService() echo.HandlerFunc {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
-		permRole := models.SERVICE_MANAGER
-
-		if models.ServiceRole(data.Role) == models.SERVICE_ADMIN {
-			permRole = models.SERVICE_ADMIN
-		}
-
 		// Récuperation de l'utilisateur
-		err = checkUserPerm(claims, db, *service, permRole)
-		if err != nil {
-			return resp.SendError(c, err.Error(), models.TransformErr(err))
-		}
-
 		// Get User to add
 		userToAdd := models.UserModel{}
 		result := db.Model(&userToAdd).Where("id = ? OR auth_id = ?", data.UserId, data.UserId).First(&userToAdd)
@@ -285,14 +256,20 @@ func (s *ServiceApiRessource) AddUserToService() echo.HandlerFunc {
 			Role:      models.ServiceRole(data.Role),
 		}
 
-		result = db.Create(&permission)
-		if result.Error != nil {
-			if strings.Contains(result.Error.Error(), "duplicated key not allowed") {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&permission).Error; err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_PERMISSION_ADD, "service_permission", permission.ID, nil, permission)
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicated key not allowed") {
 				err := fmt.Errorf("utilisateur déjà ajouté a cette boutique")
 				return resp.SendError(c, err.Error(), models.TransformErr(err))
 			}
 
-			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
 		type resData struct {
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResServicePermissionAPIUpdateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		ServicePermission models.ServicePermissionModel `json:"service_permission"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type UpdatePermissionFormData struct {
+	Role         *int   `json:"role" form:"role" xml:"role" validate:"required,min=0,max=2"`                    // Role: 0: DEV, 1: MANAGER, 2: ADMIN
+	CustomRoleId string `json:"custom_role_id" form:"custom_role_id" xml:"custom_role_id" validate:"omitempty"` // Rôle personnalisé de la boutique, remplace les permissions du rôle
+}
+
+// UpdatePermission
+// @Summary      	Update service permission role
+// @Description  	Changement du rôle d'un membre de la boutique par un administrateur,
+// @Description  	avec un rôle personnalisé optionnel, la boutique garde au moins un SERVICE_ADMIN
+// @Tags         	Service Permissions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData UpdatePermissionFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServicePermissionAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/permissions/:permission_id [put]
+func (s *ServiceApiRessource) UpdatePermission() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(UpdatePermissionFormData)
+		updateForm := UpdatePermissionFormData{}
+		err := utils.BindValidate(c, data, &updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		permission := findPermission(service, func(p models.ServicePermissionModel) bool {
+			return p.ID == c.Param("permission_id")
+		})
+		if permission == nil {
+			err := fmt.Errorf("permission inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Le rôle personnalisé doit appartenir à la boutique
+		var customRoleId *string
+		if updateForm.CustomRoleId != "" {
+			customRole := models.ServiceRoleModel{}
+			result := db.Where("id = ? AND service_id = ?", updateForm.CustomRoleId, service.ID).Limit(1).Find(&customRole)
+			if result.Error != nil {
+				return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			}
+
+			if result.RowsAffected == 0 {
+				err := fmt.Errorf("rôle personnalisé inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			customRoleId = &customRole.ID
+		}
+
+		before := *permission
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := permission.ChangeRole(tx, models.ServiceRole(*updateForm.Role), customRoleId); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_PERMISSION_SET, "service_permission", permission.ID, before, permission)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			ServicePermission models.ServicePermissionModel `json:"service_permission"`
+		}
+
+		resp.SetData(resData{
+			ServicePermission: *permission,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// RemovePermission
+// @Summary      	Remove user from service
+// @Description  	Retrait d'un membre de la boutique par un administrateur,
+// @Description  	la boutique garde au moins un SERVICE_ADMIN
+// @Tags         	Service Permissions
+// @Product       	json
+// @response      	200 {object} ResServicePermissionAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/permissions/:permission_id [delete]
+func (s *ServiceApiRessource) RemovePermission() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		permission := findPermission(service, func(p models.ServicePermissionModel) bool {
+			return p.ID == c.Param("permission_id")
+		})
+		if permission == nil {
+			err := fmt.Errorf("permission inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := permission.Remove(tx); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_PERMISSION_DEL, "service_permission", permission.ID, permission, nil)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			ServicePermission models.ServicePermissionModel `json:"service_permission"`
+		}
+
+		resp.SetData(resData{
+			ServicePermission: *permission,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// LeaveService
+// @Summary      	Leave service
+// @Description  	Retrait de l'utilisateur connecté de la boutique, impossible pour le dernier SERVICE_ADMIN
+// @Tags         	Service Permissions
+// @Product       	json
+// @response      	200 {object} ResServicePermissionAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/permissions/leave [post]
+func (s *ServiceApiRessource) LeaveService() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		authId, _ := claims["sub"].(string)
+
+		permission := findPermission(service, func(p models.ServicePermissionModel) bool {
+			return p.User != nil && p.User.AuthId == authId
+		})
+		if permission == nil {
+			err := fmt.Errorf("utilisateur non membre de la boutique")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := permission.Remove(tx); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_LEAVE, "service_permission", permission.ID, permission, nil)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			ServicePermission models.ServicePermissionModel `json:"service_permission"`
+		}
+
+		resp.SetData(resData{
+			ServicePermission: *permission,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// findPermission permission de la boutique chargée par GetOnMid
+func findPermission(service *models.ServiceModel, match func(models.ServicePermissionModel) bool) *models.ServicePermissionModel {
+	for i := range service.Permissions {
+		if match(service.Permissions[i]) {
+			return &service.Permissions[i]
+		}
+	}
+
+	return nil
+}
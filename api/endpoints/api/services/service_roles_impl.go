@@ -0,0 +1,339 @@
+package services
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResServiceRoleAPISuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Role models.ServiceRoleModel `json:"role"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type ResServiceRoleAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Roles      []models.ServiceRoleModel `json:"roles"`
+		Pagination models.PaginationModel    `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type RoleFormData struct {
+	Name        string   `json:"name" form:"name" xml:"name" validate:"required"`                                          // Nom du rôle, unique dans la boutique
+	Description string   `json:"description" form:"description" xml:"description" validate:"omitempty"`                    // Description du rôle
+	Permissions []string `json:"permissions" form:"permissions" xml:"permissions" validate:"required,min=1,dive,required"` // Permissions de boutique (transactions:read, service:keys:rotate, ...)
+}
+
+// FetchRoles
+// @Summary      	Fetch service custom roles paginate
+// @Description  	Récuperation des rôles personnalisés de la boutique paginer
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceRoleAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/roles [get]
+func (s *ServiceApiRessource) FetchRoles() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		limit, _ := c.Get("LIMIT").(int)
+		offset, _ := c.Get("OFFSET").(int)
+		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
+
+		reqDb := db.Model(&models.ServiceRoleModel{}).Where("service_id = ?", service.ID)
+
+		var count int64
+		if withCount {
+			result := reqDb.Count(&count)
+			if result.Error != nil {
+				log.Error().Err(result.Error).Msgf("")
+				return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+			}
+		}
+
+		if cursor != nil {
+			// Pagination par curseur
+			reqDb = models.CursorQuery(reqDb, cursor, limit)
+		} else {
+			if len(orders) == 0 {
+				orders = []string{"name asc"}
+			}
+
+			for _, order := range orders {
+				reqDb = reqDb.Order(order)
+			}
+
+			reqDb = reqDb.Limit(limit).Offset(offset)
+		}
+
+		roles := []models.ServiceRoleModel{}
+		result := reqDb.Find(&roles)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type dataResponse struct {
+			Roles      []models.ServiceRoleModel `json:"roles"`
+			Pagination models.PaginationModel    `json:"pagination"`
+		}
+
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			roles, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(roles, cursor, limit)
+			pagination.Offset = 0
+		}
+
+		resp.SetData(dataResponse{
+			Roles:      roles,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// AddRole
+// @Summary      	Add service custom role
+// @Description  	Création d'un rôle personnalisé de la boutique à partir des permissions de boutique,
+// @Description  	attribué aux membres par custom_role_id
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData RoleFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceRoleAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/roles [post]
+func (s *ServiceApiRessource) AddRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		role := models.ServiceRoleModel{}
+		data := new(RoleFormData)
+		err := utils.BindValidate(c, data, &role)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		role.ServiceId = service.ID
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_ROLE_ADD, "service_role", role.ID, nil, role)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Role models.ServiceRoleModel `json:"role"`
+		}
+
+		resp.SetData(resData{
+			Role: role,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// UpdateRole
+// @Summary      	Update service custom role
+// @Description  	Modification d'un rôle personnalisé, appliquée immédiatement aux membres qui l'ont
+// @Tags         	Services
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData RoleFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResServiceRoleAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/roles/:role_id [put]
+func (s *ServiceApiRessource) UpdateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		role, err := findRole(db, service, c.Param("role_id"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		before := *role
+
+		// Récuperation des données du formulaire
+		data := new(RoleFormData)
+		err = utils.BindValidate(c, data, role)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		role.ID = before.ID
+		role.ServiceId = service.ID
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(role).Error; err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_ROLE_SET, "service_role", role.ID, before, role)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Role models.ServiceRoleModel `json:"role"`
+		}
+
+		resp.SetData(resData{
+			Role: *role,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// DeleteRole
+// @Summary      	Delete service custom role
+// @Description  	Suppression d'un rôle personnalisé, les membres qui l'avaient reprennent les permissions de leur rôle
+// @Tags         	Services
+// @Product       	json
+// @response      	200 {object} ResServiceRoleAPISuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/services/:id/roles/:role_id [delete]
+func (s *ServiceApiRessource) DeleteRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		service, ok := c.Get("SERVICE").(*models.ServiceModel)
+		if !ok {
+			err := fmt.Errorf("boutique non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		role, err := findRole(db, service, c.Param("role_id"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := role.Remove(tx); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SERVICE_ROLE_DEL, "service_role", role.ID, role, nil)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Role models.ServiceRoleModel `json:"role"`
+		}
+
+		resp.SetData(resData{
+			Role: *role,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// findRole rôle personnalisé de la boutique
+func findRole(db *gorm.DB, service *models.ServiceModel, roleId string) (*models.ServiceRoleModel, error) {
+	role := models.ServiceRoleModel{}
+	result := db.Where("id = ? AND service_id = ?", roleId, service.ID).Limit(1).Find(&role)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("rôle personnalisé inexistant")
+	}
+
+	return &role, nil
+}
@@ -0,0 +1,41 @@
+package settlements
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SettlementApiRessource struct {
+	*models.SettlementModel
+}
+
+// Colonnes autorisées pour le tri
+var settlementSorts = []string{"created_at", "updated_at", "net_amount", "state", "paid_at"}
+
+func AttachAPI(server *echo.Group) {
+	settlementApi := &SettlementApiRessource{&models.SettlementModel{}}
+
+	// Settlement
+	settlementApiService := server.Group("/settlements")
+	{
+		// Fetch
+		settlementApiService.GET("/", settlementApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(settlementSorts...))
+
+		// Run Settlement
+		settlementApiService.POST("/", settlementApi.Add(), middlewares.GrantMid())
+
+		settlementOneApiService := settlementApiService.Group("/:id", middlewares.GrantMid(), settlementApi.GetOnMid())
+		{
+			// Get Settlement Info
+			settlementOneApiService.GET("/", settlementApi.GetInfo(), middlewares.PermMid(models.PERM_SETTLEMENTS_READ))
+
+			// Download Settlement Statement
+			settlementOneApiService.GET("/statement", settlementApi.Statement(), middlewares.PermMid(models.PERM_SETTLEMENTS_READ))
+
+			// Update Settlement State
+			settlementOneApiService.POST("/state", settlementApi.SetState(), middlewares.PermMid(models.PERM_PLATFORM_SETTLEMENTS))
+		}
+	}
+}
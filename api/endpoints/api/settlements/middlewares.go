@@ -0,0 +1,84 @@
+package settlements
+
+import (
+	"fmt"
+	"spay/models"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (s *SettlementApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			db, err := models.GetDB()
+			if err != nil {
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			settlement, err := getSettlement(db, c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if settlement == nil {
+				err := fmt.Errorf("règlement inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("SETTLEMENT", settlement)
+			c.Set("SERVICE_ID", settlement.ServiceId)
+
+			return next(c)
+		}
+	}
+}
+
+func getSettlement(db *gorm.DB, settlementId string) (*models.SettlementModel, error) {
+	id, err := uuid.FromString(settlementId)
+	if err != nil {
+		return nil, fmt.Errorf("identifiant règlement invalide")
+	}
+
+	settlement := models.SettlementModel{}
+	result := db.Where("id = ?", id.String()).Limit(1).Find(&settlement)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &settlement, nil
+}
+
+// getLoginUser utilisateur connecté et ses permissions
+func getLoginUser(db *gorm.DB, claims jwt.MapClaims) (*models.UserModel, error) {
+	loginUser := models.UserModel{}
+	loginUser.AuthId = claims["sub"].(string)
+
+	result := db.
+		Scopes(models.PreloadGrants).
+		Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msgf("")
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	return &loginUser, nil
+}
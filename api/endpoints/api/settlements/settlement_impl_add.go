@@ -0,0 +1,120 @@
+package settlements
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResSettlementAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Settlement models.SettlementModel `json:"settlement"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	ServiceId string `json:"service_id" form:"service_id" xml:"service_id" validate:"required"` // Boutique à régler
+}
+
+// Add
+// @Summary      	Run settlement
+// @Description  	Calcul immédiat du versement du solde d'une boutique, hors calendrier
+// @Tags         	Settlements
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResSettlementAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/settlements/ [post]
+func (s *SettlementApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := getLoginUser(db, claims)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := loginUser.Authorize(models.PERM_PLATFORM_SETTLEMENTS, ""); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		config := models.SettlementConfigModel{}
+		result := db.Where("service_id = ?", data.ServiceId).Limit(1).Find(&config)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		if result.RowsAffected == 0 {
+			err := fmt.Errorf("versements non configurés pour cette boutique")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		var settlement *models.SettlementModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			settlement, err = models.CreateSettlement(tx, config, time.Now())
+			return err
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if settlement == nil {
+			err := fmt.Errorf("montant à verser inférieur au minimum de %.2f", config.MinAmount)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		utils.NotifySettlement(db, *settlement)
+
+		type resData struct {
+			Settlement models.SettlementModel `json:"settlement"`
+		}
+
+		resp.SetData(resData{
+			Settlement: *settlement,
+		})
+
+		return resp.Send(c)
+	}
+}
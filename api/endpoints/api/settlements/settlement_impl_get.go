@@ -0,0 +1,66 @@
+package settlements
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResSettlementAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Settlement models.SettlementModel `json:"settlement"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get settlement data
+// @Description  	Récuperation du règlement et des transactions incluses
+// @Tags         	Settlements
+// @Product       	json
+// @response      	200 {object} ResSettlementAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/settlements/:id/ [get]
+func (s *SettlementApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		settlement, ok := c.Get("SETTLEMENT").(*models.SettlementModel)
+		if !ok {
+			err := fmt.Errorf("règlement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Where("settlement_id = ?", settlement.ID).Order("created_at asc").Find(&settlement.Items)
+		if result.Error != nil {
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Settlement models.SettlementModel `json:"settlement"`
+		}
+
+		resp.SetData(resData{
+			Settlement: *settlement,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,119 @@
+package settlements
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type StateFormData struct {
+	State     string `json:"state" form:"state" xml:"state" validate:"required,oneof=PROCESSING PAID FAILED processing paid failed"` // PROCESSING, PAID, FAILED
+	Reference string `json:"reference" form:"reference" xml:"reference" validate:"omitempty"`                                        // Référence du virement
+	Reason    string `json:"reason" form:"reason" xml:"reason" validate:"omitempty"`                                                 // Motif du rejet, obligatoire pour FAILED
+}
+
+// SetState
+// @Summary      	Update settlement state
+// @Description  	Suivi du versement: émis (PROCESSING), confirmé (PAID) ou rejeté (FAILED, le montant est rendu au solde),
+// @Description  	l'émission d'un versement en attente peut nécessiter une approbation (202) selon la politique settlement.payout
+// @Tags         	Settlements
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData StateFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResSettlementAPIGetSuccess
+// @response      	202 {object} ResSettlementAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/settlements/:id/state [post]
+func (s *SettlementApiRessource) SetState() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		settlement, ok := c.Get("SETTLEMENT").(*models.SettlementModel)
+		if !ok {
+			err := fmt.Errorf("règlement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(StateFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		state := strings.ToUpper(data.State)
+
+		var approval *models.ApprovalRequestModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Emission du versement soumise à la politique d'approbation
+			if settlement.State == models.SETTLEMENT_PENDING && state != models.SETTLEMENT_FAILED {
+				approval, err = models.RequestApproval(tx, utils.AuditActor(c), models.ApprovalRequestModel{
+					Operation:   models.APPROVAL_SETTLEMENT_PAYOUT,
+					ServiceId:   settlement.ServiceId,
+					TargetId:    settlement.ID,
+					Amount:      settlement.NetAmount,
+					Currency:    settlement.Currency,
+					RequestedBy: loginUser.ID,
+				}, models.SettlementPayout{State: state, Reference: data.Reference})
+				if err != nil || approval != nil {
+					return err
+				}
+			}
+
+			before := *settlement
+			if err := settlement.SetState(tx, state, data.Reference, data.Reason, time.Now()); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_SETTLEMENT_STATE, "settlement", settlement.ID, before, settlement)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+		} else {
+			utils.NotifySettlement(db, *settlement)
+		}
+
+		type resData struct {
+			Settlement models.SettlementModel       `json:"settlement"`
+			Approval   *models.ApprovalRequestModel `json:"approval,omitempty"`
+		}
+
+		resp.SetData(resData{
+			Settlement: *settlement,
+			Approval:   approval,
+		})
+
+		return resp.Send(c)
+	}
+}
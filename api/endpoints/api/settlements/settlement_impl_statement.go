@@ -0,0 +1,74 @@
+package settlements
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// Statement
+// @Summary      	Download settlement statement
+// @Description  	Relevé du règlement au format csv ou xlsx: récapitulatif des montants puis transactions incluses
+// @Tags         	Settlements
+// @Produce       	text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        	format query string false "csv (par défaut) ou xlsx"
+// @response      	200 {file} file
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/settlements/:id/statement [get]
+func (s *SettlementApiRessource) Statement() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		settlement, ok := c.Get("SETTLEMENT").(*models.SettlementModel)
+		if !ok {
+			err := fmt.Errorf("règlement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		format := strings.ToLower(c.QueryParam("format"))
+		if format == "" {
+			format = utils.EXPORT_CSV
+		}
+
+		if format != utils.EXPORT_CSV && format != utils.EXPORT_XLSX {
+			err := fmt.Errorf("format d'export inconnu %v, csv ou xlsx attendu", format)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Envoi en flux, aucune réponse json n'est possible après les entêtes
+		fileName := fmt.Sprintf("reglement-%v-%v.%v", settlement.PeriodEnd.Format("20060102"), settlement.ID, format)
+		c.Response().Header().Set(echo.HeaderContentType, utils.ExportContentType(format))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+		c.Response().WriteHeader(http.StatusOK)
+
+		writer, err := utils.NewExportWriter(format, c.Response(), "reglement")
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return nil
+		}
+
+		count, err := utils.ExportSettlement(db, *settlement, writer)
+		if err != nil {
+			log.Error().Err(err).Int64("rows", count).Msgf("export règlement interrompu")
+		}
+
+		if err := writer.Close(); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		return nil
+	}
+}
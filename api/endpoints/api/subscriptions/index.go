@@ -0,0 +1,63 @@
+package subscriptions
+
+import (
+	"spay/endpoints/api/middlewares"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SubscriptionApiRessource struct {
+	*models.SubscriptionModel
+}
+
+// Colonnes autorisées pour le tri
+var subscriptionSorts = []string{"created_at", "updated_at", "state", "next_billing_at", "retry_count"}
+var planSorts = []string{"created_at", "updated_at", "name", "amount", "interval"}
+
+func AttachAPI(server *echo.Group) {
+	subscriptionApi := &SubscriptionApiRessource{&models.SubscriptionModel{}}
+
+	// Subscription
+	subscriptionApiService := server.Group("/subscriptions")
+	{
+		// Fetch
+		subscriptionApiService.GET("/", subscriptionApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(subscriptionSorts...))
+
+		// Add Subscription
+		subscriptionApiService.POST("/", subscriptionApi.Add(), middlewares.GrantMid())
+
+		planApiService := subscriptionApiService.Group("/plans")
+		{
+			// Fetch Plans
+			planApiService.GET("/", subscriptionApi.FetchPlans(), middlewares.GrantMid(), models.PaginationMid(planSorts...))
+
+			// Add Plan
+			planApiService.POST("/", subscriptionApi.AddPlan(), middlewares.GrantMid())
+
+			planOneApiService := planApiService.Group("/:plan_id", middlewares.GrantMid(), subscriptionApi.GetPlanOnMid())
+			{
+				// Get Plan Info
+				planOneApiService.GET("/", subscriptionApi.GetPlan(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_READ))
+
+				// Update Plan
+				planOneApiService.PUT("/", subscriptionApi.UpdatePlan(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_MANAGE))
+			}
+		}
+
+		subscriptionOneApiService := subscriptionApiService.Group("/:id", middlewares.GrantMid(), subscriptionApi.GetOnMid())
+		{
+			// Get Subscription Info
+			subscriptionOneApiService.GET("/", subscriptionApi.GetInfo(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_READ))
+
+			// Pause Subscription
+			subscriptionOneApiService.POST("/pause", subscriptionApi.Pause(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_MANAGE))
+
+			// Resume Subscription
+			subscriptionOneApiService.POST("/resume", subscriptionApi.Resume(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_MANAGE))
+
+			// Cancel Subscription
+			subscriptionOneApiService.POST("/cancel", subscriptionApi.Cancel(), middlewares.PermMid(models.PERM_SUBSCRIPTIONS_MANAGE))
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package subscriptions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (s *SubscriptionApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			_, ok = c.Get("JWT_CLAIMS").(jwt.MapClaims)
+			if !ok {
+				err := fmt.Errorf("authentification obligatoire")
+				resp.SetStatus(http.StatusUnauthorized)
+				return resp.SendError(c, err.Error(), models.ResErrorAPI{
+					models.ErrorAPI{
+						Code:    "400",
+						Message: err.Error(),
+						Data:    err,
+					},
+				})
+			}
+
+			subscription, err := getSubscription(c.Param("id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if subscription == nil {
+				err := fmt.Errorf("abonnement inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("SUBSCRIPTION", subscription)
+			c.Set("SERVICE_ID", subscription.ServiceId)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func (s *SubscriptionApiRessource) GetPlanOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+			if !ok {
+				resp = models.NewResponseAPI[interface{}]()
+			}
+
+			plan, err := getPlan(c.Param("plan_id"))
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			if plan == nil {
+				err := fmt.Errorf("plan inexistant")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			c.Set("PLAN", plan)
+			c.Set("SERVICE_ID", plan.ServiceId)
+
+			if err := next(c); err != nil {
+				err := fmt.Errorf("une erreur c'est produite")
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			return nil
+		}
+	}
+}
+
+func getSubscription(subscriptionId string) (*models.SubscriptionModel, error) {
+	subscription := models.SubscriptionModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := uuid.FromString(subscriptionId); err == nil {
+		db = db.Or("id = ?", id.String())
+	} else {
+		err := fmt.Errorf("identifiant abonnement invalide")
+		return nil, err
+	}
+
+	result := db.
+		Preload("Plan").
+		Preload("Provider").
+		Where(&subscription).
+		First(&subscription)
+	if result.Error != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func getPlan(planId string) (*models.PlanModel, error) {
+	plan := models.PlanModel{}
+
+	// Connexion à la base de donnée
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := uuid.FromString(planId); err == nil {
+		db = db.Or("id = ?", id.String())
+	} else {
+		err := fmt.Errorf("identifiant plan invalide")
+		return nil, err
+	}
+
+	result := db.
+		Where(&plan).
+		First(&plan)
+	if result.Error != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// checkServicePerm contrôle des droits de l'utilisateur sur la boutique
+func checkServicePerm(db *gorm.DB, serviceId string, claims jwt.MapClaims, perm models.Permission) (*models.UserModel, error) {
+	loginUser := models.UserModel{}
+	loginUser.AuthId = claims["sub"].(string)
+
+	result := db.
+		Scopes(models.PreloadGrants).
+		Where(&loginUser).First(&loginUser)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msgf("")
+		if strings.Contains(result.Error.Error(), "record not found") {
+			return nil, fmt.Errorf("utilisateur non reconnu")
+		}
+
+		return nil, result.Error
+	}
+
+	if err := loginUser.Authorize(perm, serviceId); err != nil {
+		return nil, err
+	}
+
+	return &loginUser, nil
+}
@@ -0,0 +1,116 @@
+package subscriptions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPlanAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Plan models.PlanModel `json:"plan"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddPlanFormData struct {
+	ServiceId          string  `json:"service_id" form:"service_id" xml:"service_id" validate:"required"`                            // Identifiant de la boutique
+	Name               string  `json:"name" form:"name" xml:"name" validate:"required"`                                              // Nom du plan
+	Amount             float64 `json:"amount" form:"amount" xml:"amount" validate:"required,gt=0"`                                   // Montant de chaque échéance
+	Currency           string  `json:"currency" form:"currency" xml:"currency" validate:"required,currency"`                         // Devise (ex: XOF)
+	Interval           string  `json:"interval" form:"interval" xml:"interval" validate:"required,oneof=DAY WEEK MONTH YEAR"`        // DAY, WEEK, MONTH, YEAR
+	IntervalCount      int     `json:"interval_count" form:"interval_count" xml:"interval_count" validate:"omitempty,gte=1"`         // Nombre d'intervalles entre deux échéances (défaut 1)
+	ModeLive           bool    `json:"mode_live" form:"mode_live" xml:"mode_live" validate:"-"`                                      // Prélèvements réels ou sandbox
+	MaxRetries         int     `json:"max_retries" form:"max_retries" xml:"max_retries" validate:"gte=0"`                            // Nombre de relances après un échec
+	RetryIntervalHours int     `json:"retry_interval_hours" form:"retry_interval_hours" xml:"retry_interval_hours" validate:"gte=0"` // Délai entre deux relances (défaut 24h)
+}
+
+// AddPlan
+// @Summary      	Add new subscription plan
+// @Description  	Création d'un plan d'abonnement pour une boutique
+// @Tags         	Subscriptions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddPlanFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResPlanAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/plans/ [post]
+func (s *SubscriptionApiRessource) AddPlan() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddPlanFormData)
+
+		newPlan := models.PlanModel{}
+		err := utils.BindValidate(c, data, &newPlan)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		_, err = checkServicePerm(db, newPlan.ServiceId, claims, models.PERM_SUBSCRIPTIONS_MANAGE)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// La boutique doit exister
+		service := models.ServiceModel{}
+		result := db.Model(&service).Where("id = ?", newPlan.ServiceId).First(&service)
+		if result.Error != nil {
+			err := fmt.Errorf("boutique inexistante")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if newPlan.IntervalCount < 1 {
+			newPlan.IntervalCount = 1
+		}
+
+		newPlan.Currency = strings.ToUpper(newPlan.Currency)
+		newPlan.Active = true
+
+		result = db.Create(&newPlan)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Plan models.PlanModel `json:"plan"`
+		}
+
+		resp.SetData(resData{
+			Plan: newPlan,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,108 @@
+package subscriptions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPlanAPIFetchSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Plans      []models.PlanModel     `json:"plans"`
+		Pagination models.PaginationModel `json:"pagination"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// FetchPlans
+// @Summary      	Fetch all subscription plans paginate
+// @Description  	Récuperation des plans d'abonnement paginer
+// @Tags         	Subscriptions
+// @Product       	json
+// @Param        	filter-service query string false "Identifiant de la boutique"
+// @Param        	filter-active query bool false "Plans actifs ou désactivés"
+// @response      	200 {object} ResPlanAPIFetchSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/plans/ [get]
+func (s *SubscriptionApiRessource) FetchPlans() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := getLoginUser(db, claims)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		params := fetchParamsFromContext(c)
+
+		reqDb := fetchRestricted(db.Model(&models.PlanModel{}), *loginUser)
+
+		if len(params.Query) > 0 {
+			reqDb = reqDb.Where("lower(name) LIKE ?", strings.ToLower("%"+params.Query+"%"))
+		}
+
+		if filter := c.QueryParam("filter-service"); filter != "" {
+			reqDb = reqDb.Where("service_id = ?", filter)
+		}
+
+		if filter := c.QueryParam("filter-active"); filter != "" {
+			reqDb = reqDb.Where("active = ?", filter == "true")
+		}
+
+		plans := []models.PlanModel{}
+
+		var count int64
+		err = fetchExec(reqDb, &plans, params, &count)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type dataResponse struct {
+			Plans      []models.PlanModel     `json:"plans"`
+			Pagination models.PaginationModel `json:"pagination"`
+		}
+
+		pagination := params.pagination(&count)
+
+		// Pagination par curseur
+		if params.Cursor != nil {
+			plans, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(plans, params.Cursor, params.Limit)
+		}
+
+		resp.SetData(dataResponse{
+			Plans:      plans,
+			Pagination: pagination,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,55 @@
+package subscriptions
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResPlanAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Plan models.PlanModel `json:"plan"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetPlan
+// @Summary      	Get subscription plan info
+// @Description  	Récuperation d'un plan d'abonnement
+// @Tags         	Subscriptions
+// @Product       	json
+// @response      	200 {object} ResPlanAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/plans/:plan_id/ [get]
+func (s *SubscriptionApiRessource) GetPlan() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		plan, ok := c.Get("PLAN").(*models.PlanModel)
+		if !ok {
+			err := fmt.Errorf("plan non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Plan models.PlanModel `json:"plan"`
+		}
+
+		resp.SetData(resData{
+			Plan: *plan,
+		})
+
+		return resp.Send(c)
+	}
+}
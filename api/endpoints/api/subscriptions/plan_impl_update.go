@@ -0,0 +1,106 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResPlanAPIUpdateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Plan models.PlanModel `json:"plan"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Le montant et la périodicité d'un plan ne sont pas modifiables, un nouveau plan doit être créé
+type UpdatePlanFormData struct {
+	Name               *string `json:"name,omitempty" form:"name" xml:"name" validate:"omitempty"`                                                       // Nom du plan
+	Active             *bool   `json:"active,omitempty" form:"active" xml:"active" validate:"omitempty"`                                                 // Un plan désactivé n'accepte plus d'abonnement
+	MaxRetries         *int    `json:"max_retries,omitempty" form:"max_retries" xml:"max_retries" validate:"omitempty,gte=0"`                            // Nombre de relances après un échec
+	RetryIntervalHours *int    `json:"retry_interval_hours,omitempty" form:"retry_interval_hours" xml:"retry_interval_hours" validate:"omitempty,gte=0"` // Délai entre deux relances
+}
+
+// UpdatePlan
+// @Summary      	Update subscription plan
+// @Description  	Mise à jour d'un plan d'abonnement
+// @Tags         	Subscriptions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData UpdatePlanFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResPlanAPIUpdateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/plans/:plan_id/ [put]
+func (s *SubscriptionApiRessource) UpdatePlan() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		plan, ok := c.Get("PLAN").(*models.PlanModel)
+		if !ok {
+			err := fmt.Errorf("plan non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(UpdatePlanFormData)
+		updateForm := UpdatePlanFormData{}
+		err := utils.BindValidate(c, data, &updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Seuls les champs transmis sont modifiés
+		planFormJson, err := json.Marshal(updateForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = json.Unmarshal(planFormJson, plan)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		result := db.Model(&models.PlanModel{}).
+			Where("id = ?", plan.ID).
+			Updates(map[string]interface{}{
+				"name":                 plan.Name,
+				"active":               plan.Active,
+				"max_retries":          plan.MaxRetries,
+				"retry_interval_hours": plan.RetryIntervalHours,
+			})
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Plan models.PlanModel `json:"plan"`
+		}
+
+		resp.SetData(resData{
+			Plan: *plan,
+		})
+
+		return resp.Send(c)
+	}
+}
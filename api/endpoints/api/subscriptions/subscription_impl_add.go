@@ -0,0 +1,159 @@
+package subscriptions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResSubscriptionAPICreateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Subscription models.SubscriptionModel `json:"subscription"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AddFormData struct {
+	PlanId      string     `json:"plan_id" form:"plan_id" xml:"plan_id" validate:"required"`                 // Identifiant du plan
+	ProviderId  string     `json:"provider_id" form:"provider_id" xml:"provider_id" validate:"required"`     // Provider des prélèvements
+	PayerPhone  string     `json:"payer_phone" form:"payer_phone" xml:"payer_phone" validate:"required"`     // Numéro de téléphone du payeur
+	ReferenceId string     `json:"reference_id" form:"reference_id" xml:"reference_id" validate:"omitempty"` // Réference du contrat (ex: numéro de police)
+	StartAt     *time.Time `json:"start_at,omitempty" form:"start_at" xml:"start_at" validate:"omitempty"`   // Première échéance (défaut: immédiate)
+}
+
+// Add
+// @Summary      	Add new subscription
+// @Description  	Abonnement d'un payeur à un plan, la première échéance est prélevée à la date de début
+// @Tags         	Subscriptions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AddFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResSubscriptionAPICreateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/ [post]
+func (s *SubscriptionApiRessource) Add() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AddFormData)
+
+		newSubscription := models.SubscriptionModel{}
+		err := utils.BindValidate(c, data, &newSubscription)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		plan := models.PlanModel{}
+		result := db.Model(&plan).Where("id = ?", newSubscription.PlanId).First(&plan)
+		if result.Error != nil {
+			err := fmt.Errorf("plan inexistant")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, err := checkServicePerm(db, plan.ServiceId, claims, models.PERM_SUBSCRIPTIONS_CREATE)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if !plan.Active {
+			err := fmt.Errorf("plan désactivé")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		provider := models.ProviderModel{}
+		result = db.Model(&provider).Where("id = ?", newSubscription.ProviderId).First(&provider)
+		if result.Error != nil {
+			err := fmt.Errorf("provider inexistant")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		service := models.ServiceModel{}
+		result = db.Model(&service).Where("id = ?", plan.ServiceId).First(&service)
+		if result.Error != nil {
+			err := fmt.Errorf("boutique introuvable")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Validation selon le plan de numérotation du pays de la boutique
+		number, err := utils.ParsePayerPhone(service.Country, newSubscription.PayerPhone)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := utils.CheckProviderOperator(provider, *number); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newSubscription.PayerPhone = number.E164
+
+		newSubscription.ServiceId = plan.ServiceId
+		newSubscription.State = models.SUBSCRIPTION_ACTIVE
+		newSubscription.CreatedById = loginUser.ID
+		newSubscription.NextBillingAt = time.Now()
+		if data.StartAt != nil && data.StartAt.After(newSubscription.NextBillingAt) {
+			newSubscription.NextBillingAt = *data.StartAt
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Payeur
+			customer, err := utils.SaveCustomer(tx, service, models.CustomerModel{Phone: newSubscription.PayerPhone})
+			if err != nil {
+				return err
+			}
+
+			newSubscription.CustomerId = customer.ID
+
+			if err := tx.Create(&newSubscription).Error; err != nil {
+				return err
+			}
+
+			return models.PublishEvent(tx, newSubscription.ServiceId, models.EVENT_SUBSCRIPTION_CREATED, "subscription", newSubscription.ID, newSubscription)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newSubscription.Plan = &plan
+
+		type resData struct {
+			Subscription models.SubscriptionModel `json:"subscription"`
+		}
+
+		resp.SetData(resData{
+			Subscription: newSubscription,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,77 @@
+package subscriptions
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResSubscriptionAPIGetSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Subscription models.SubscriptionModel  `json:"subscription"`
+		Transactions []models.TransactionModel `json:"transactions"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// GetInfo
+// @Summary      	Get subscription info
+// @Description  	Récuperation d'un abonnement et de ses derniers prélèvements
+// @Tags         	Subscriptions
+// @Product       	json
+// @response      	200 {object} ResSubscriptionAPIGetSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/:id/ [get]
+func (s *SubscriptionApiRessource) GetInfo() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		subscription, ok := c.Get("SUBSCRIPTION").(*models.SubscriptionModel)
+		if !ok {
+			err := fmt.Errorf("abonnement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Derniers prélèvements
+		transactions := []models.TransactionModel{}
+		result := db.
+			Where("subscription_id = ?", subscription.ID).
+			Order("created_at desc").
+			Limit(12).
+			Find(&transactions)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		type resData struct {
+			Subscription models.SubscriptionModel  `json:"subscription"`
+			Transactions []models.TransactionModel `json:"transactions"`
+		}
+
+		resp.SetData(resData{
+			Subscription: *subscription,
+			Transactions: transactions,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -0,0 +1,132 @@
+package subscriptions
+
+import (
+	"fmt"
+	"spay/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResSubscriptionAPIStateSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Subscription models.SubscriptionModel `json:"subscription"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Pause
+// @Summary      	Pause subscription
+// @Description  	Suspension des prélèvements de l'abonnement
+// @Tags         	Subscriptions
+// @Product       	json
+// @response      	200 {object} ResSubscriptionAPIStateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/:id/pause [post]
+func (s *SubscriptionApiRessource) Pause() echo.HandlerFunc {
+	return changeState(models.SUBSCRIPTION_PAUSED, models.EVENT_SUBSCRIPTION_PAUSED)
+}
+
+// Resume
+// @Summary      	Resume subscription
+// @Description  	Reprise des prélèvements, une échéance dépassée est prélevée immédiatement
+// @Tags         	Subscriptions
+// @Product       	json
+// @response      	200 {object} ResSubscriptionAPIStateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/:id/resume [post]
+func (s *SubscriptionApiRessource) Resume() echo.HandlerFunc {
+	return changeState(models.SUBSCRIPTION_ACTIVE, models.EVENT_SUBSCRIPTION_RESUMED)
+}
+
+// Cancel
+// @Summary      	Cancel subscription
+// @Description  	Résiliation définitive de l'abonnement
+// @Tags         	Subscriptions
+// @Product       	json
+// @response      	200 {object} ResSubscriptionAPIStateSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/subscriptions/:id/cancel [post]
+func (s *SubscriptionApiRessource) Cancel() echo.HandlerFunc {
+	return changeState(models.SUBSCRIPTION_CANCELED, models.EVENT_SUBSCRIPTION_CANCELED)
+}
+
+func changeState(state string, eventType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		subscription, ok := c.Get("SUBSCRIPTION").(*models.SubscriptionModel)
+		if !ok {
+			err := fmt.Errorf("abonnement non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := subscription.CheckTransition(state); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		now := time.Now()
+		switch state {
+		case models.SUBSCRIPTION_PAUSED:
+			subscription.PausedAt = &now
+
+		case models.SUBSCRIPTION_ACTIVE:
+			subscription.PausedAt = nil
+			subscription.RetryCount = 0
+			subscription.NextRetryAt = nil
+
+		case models.SUBSCRIPTION_CANCELED:
+			subscription.CanceledAt = &now
+		}
+
+		subscription.State = state
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.SubscriptionModel{}).
+				Where("id = ?", subscription.ID).
+				Updates(map[string]interface{}{
+					"state":         subscription.State,
+					"paused_at":     subscription.PausedAt,
+					"canceled_at":   subscription.CanceledAt,
+					"retry_count":   subscription.RetryCount,
+					"next_retry_at": subscription.NextRetryAt,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			return models.PublishEvent(tx, subscription.ServiceId, eventType, "subscription", subscription.ID, subscription)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Subscription models.SubscriptionModel `json:"subscription"`
+		}
+
+		resp.SetData(resData{
+			Subscription: *subscription,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -11,6 +11,9 @@ type TransactionApiRessource struct {
 	*models.TransactionModel
 }
 
+// Colonnes autorisées pour le tri
+var transactionSorts = []string{"created_at", "updated_at", "amount", "amount_with_fee", "operation_state", "operation_mode", "currency", "reference_id", "mode_live"}
+
 func AttachAPI(server *echo.Group) {
 	transactionApi := &TransactionApiRessource{&models.TransactionModel{}}
 
@@ -18,18 +21,34 @@ func AttachAPI(server *echo.Group) {
 	serviceApiService := server.Group("/transactions")
 	{
 		// Fetch
-		serviceApiService.GET("/", transactionApi.Fetch(), middlewares.GrantMid(), models.PaginationMid())
+		serviceApiService.GET("/", transactionApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(transactionSorts...))
 
 		// Add Transaction
 		serviceApiService.POST("/", transactionApi.Add(), middlewares.GrantMid())
 
-		// serviceOneApiService := serviceApiService.Group("/:id", middlewares.GrantMid(), transactionApi.GetOnMid())
-		// {
-		// 	// Get Transaction Info
-		// 	serviceOneApiService.GET("/", transactionApi.GetInfo())
+		// Export Transactions
+		serviceApiService.GET("/export", transactionApi.Export(), middlewares.GrantMid())
+
+		transactionOneApiService := serviceApiService.Group("/:id", middlewares.GrantMid(), transactionApi.GetOnMid())
+		{
+			// Get Transaction Info
+			transactionOneApiService.GET("/", transactionApi.GetInfo(), middlewares.PermMid(models.PERM_TRANSACTIONS_READ))
+
+			// Cancel Transaction
+			transactionOneApiService.POST("/cancel", transactionApi.Cancel(), middlewares.PermMid(models.PERM_TRANSACTIONS_CANCEL))
+
+			// Capture / Void Authorized Transaction
+			transactionOneApiService.POST("/capture", transactionApi.Capture(), middlewares.PermMid(models.PERM_TRANSACTIONS_CAPTURE))
+			transactionOneApiService.POST("/void", transactionApi.Void(), middlewares.PermMid(models.PERM_TRANSACTIONS_CAPTURE))
+
+			// Refund Transaction
+			transactionOneApiService.POST("/refund", transactionApi.Refund(), middlewares.PermMid(models.PERM_TRANSACTIONS_REFUND))
+
+			// Check Transaction Status
+			transactionOneApiService.GET("/status", transactionApi.CheckStatus(), middlewares.PermMid(models.PERM_TRANSACTIONS_READ))
 
-		// 	// Delete Transaction Info
-		// 	serviceOneApiService.DELETE("/", transactionApi.Delete())
-		// }
+			// Transaction QR Code
+			transactionOneApiService.GET("/qr", transactionApi.QrCode(), middlewares.PermMid(models.PERM_TRANSACTIONS_READ))
+		}
 	}
 }
@@ -44,6 +44,7 @@ func (s *TransactionApiRessource) GetOnMid() func(next echo.HandlerFunc) echo.Ha
 			}
 
 			c.Set("TRANSACTION", transaction)
+			c.Set("SERVICE_ID", transaction.ServiceId)
 
 			if err := next(c); err != nil {
 				err := fmt.Errorf("une erreur c'est produite")
@@ -68,13 +69,14 @@ func getTransaction(transactionId string, claims jwt.MapClaims) (*models.Transac
 	if id, err := uuid.FromString(transactionId); err == nil {
 		db = db.Or("id = ?", id.String())
 	} else {
-		err := fmt.Errorf("identifiant boutique invalide")
+		err := fmt.Errorf("identifiant transaction invalide")
 		return nil, err
 	}
 
 	result := db.
 		Preload("Service").
 		Preload("Provider").
+		Preload("Splits").
 		Where(&transaction).
 		First(&transaction)
 	if result.Error != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type ResTransactionAPICreateSuccess struct {
@@ -32,6 +33,27 @@ type AddFormData struct {
 	ReferenceId   string  `json:"reference_id" form:"reference_id" xml:"reference_id" validate:"required"`       // Reference de la transaction unique
 	ServiceId     string  `json:"service_id" form:"service_id" xml:"service_id" validate:"required"`             // Reference de la transaction unique
 	AuthId        string  `json:"auth_id" form:"auth_id" xml:"auth_id" validate:"omitempty"`                     // Executer en tant que
+
+	Currency string            `json:"currency,omitempty" form:"currency" xml:"currency" validate:"omitempty,currency"` // Code ISO-4217, devise de règlement de la boutique par défaut
+	Capture  *bool             `json:"capture,omitempty" form:"capture" xml:"capture" validate:"omitempty"`             // false: réservation des fonds, capture ultérieure
+	PayerIp  string            `json:"payer_ip,omitempty" form:"payer_ip" xml:"payer_ip" validate:"omitempty,ip"`       // Adresse IP du payeur, contrôle anti-fraude
+	Customer *CustomerFormData `json:"customer,omitempty" form:"-" xml:"customer" validate:"omitempty"`                 // Payeur de la transaction
+
+	Splits []SplitFormData `json:"splits,omitempty" form:"-" xml:"splits" validate:"omitempty,dive"` // Parts reversées à d'autres boutiques (commissions)
+}
+
+type SplitFormData struct {
+	ServiceId   string  `json:"service_id" form:"service_id" xml:"service_id" validate:"required"`               // Boutique bénéficiaire
+	Kind        string  `json:"kind" form:"kind" xml:"kind" validate:"required,oneof=FIXED PERCENT"`             // FIXED: montant, PERCENT: pourcentage du montant
+	Value       float64 `json:"value" form:"value" xml:"value" validate:"required,gt=0"`                         // Montant ou pourcentage de la part
+	Description string  `json:"description,omitempty" form:"description" xml:"description" validate:"omitempty"` // Libellé de la part
+}
+
+type CustomerFormData struct {
+	Name    string `json:"name" form:"name" xml:"name" validate:"omitempty"`          // Nom du payeur
+	Email   string `json:"email" form:"email" xml:"email" validate:"omitempty,email"` // Email du payeur
+	Country string `json:"country" form:"country" xml:"country" validate:"omitempty"` // Pays du numéro, celui de la boutique par défaut
+	Phone   string `json:"phone" form:"phone" xml:"phone" validate:"required"`        // Numéro de téléphone national ou international
 }
 
 // Add
@@ -69,6 +91,8 @@ func (s *TransactionApiRessource) Add() echo.HandlerFunc {
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
+		newTransaction.AuthorizeOnly = data.Capture != nil && !*data.Capture
+
 		// Création de la boutique
 		_, err = createTransactionDb(&newTransaction, data.AuthId, claims)
 		if err != nil {
@@ -105,7 +129,7 @@ func createTransactionDb(newTransaction *models.TransactionModel, authId string,
 	loginUser.AuthId = claims["sub"].(string)
 
 	result := db.
-		Preload("ServicePermissions").
+		Scopes(models.PreloadGrants).
 		Where(&loginUser).First(&loginUser)
 	if result.Error != nil {
 		log.Error().Err(result.Error).Msgf("")
@@ -119,7 +143,7 @@ func createTransactionDb(newTransaction *models.TransactionModel, authId string,
 	userAdmin := models.UserModel{}
 
 	// // Si authId precisé
-	if loginUser.IsGrant(models.USER_MANAGER) && authId != "" {
+	if loginUser.Can(models.PERM_PLATFORM_ACT_AS, "") && authId != "" {
 		result := db.Model(&userAdmin).Where("auth_id = ?", authId).First(&userAdmin)
 		if result.Error != nil {
 			return nil, result.Error
@@ -128,19 +152,65 @@ func createTransactionDb(newTransaction *models.TransactionModel, authId string,
 		userAdmin = loginUser
 	}
 
+	if err := loginUser.Authorize(models.PERM_TRANSACTIONS_CREATE, newTransaction.ServiceId); err != nil {
+		return nil, err
+	}
+
 	// Provicer
 	provider := models.ProviderModel{}
-	db.Model(&provider).First(&provider)
+	result = db.Where("id = ?", newTransaction.ProviderId).Limit(1).Find(&provider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
 
-	// Service
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("provider introuvable")
+	}
 
-	result = db.Create(&newTransaction)
+	// Service
+	service := models.ServiceModel{}
+	result = db.Where("id = ?", newTransaction.ServiceId).Limit(1).Find(&service)
 	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "duplicate key value violates") {
-			return nil, fmt.Errorf("boutique existe déjà")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("boutique introuvable")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Payeur
+		if newTransaction.Customer != nil {
+			customer, err := utils.SaveCustomer(tx, service, *newTransaction.Customer)
+			if err != nil {
+				return err
+			}
+
+			newTransaction.Customer = customer
+			newTransaction.CustomerId = customer.ID
+			newTransaction.PayerPhone = customer.Phone
 		}
 
-		return nil, result.Error
+		result := tx.Omit("Customer").Create(&newTransaction)
+		if result.Error != nil {
+			if strings.Contains(result.Error.Error(), "duplicate key value violates") {
+				return fmt.Errorf("boutique existe déjà")
+			}
+
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Réservation des fonds, un refus du provider est visible dans l'état de la transaction
+	if newTransaction.AuthorizeOnly {
+		if err := utils.ProviderStartTransaction(db, provider, newTransaction); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
 	}
 
 	return &userAdmin, nil
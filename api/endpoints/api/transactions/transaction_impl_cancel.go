@@ -0,0 +1,97 @@
+package transactions
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResTransactionAPICancelSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Transaction models.TransactionModel `json:"transaction"`
+		Canceled    bool                    `json:"canceled"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// Cancel
+// @Summary      	Cancel pending transaction
+// @Description  	Annulation d'une transaction en attente
+// @Tags         	Transactions
+// @Product       	json
+// @response      	200 {object} ResTransactionAPICancelSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/cancel [post]
+func (s *TransactionApiRessource) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = cancelTransaction(db, transaction)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Transaction models.TransactionModel `json:"transaction"`
+			Canceled    bool                    `json:"canceled"`
+		}
+
+		resp.SetData(resData{
+			Transaction: *transaction,
+			Canceled:    transaction.OperationState == models.TRANSACTION_CANCEL,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+func cancelTransaction(db *gorm.DB, transaction *models.TransactionModel) error {
+	if transaction.OperationState != models.TRANSACTION_PENDING {
+		return fmt.Errorf("seule une transaction en attente peut être annulée")
+	}
+
+	if !transaction.Provider.SupportCancel() {
+		return fmt.Errorf(utils.PROVIDER_CANCEL_UNSUPPORTED)
+	}
+
+	providerRes, err := utils.ProviderCancelTransaction(transaction.Provider, *transaction)
+	if err != nil {
+		return err
+	}
+
+	if providerRes.Reference != "" {
+		transaction.ProviderReference = providerRes.Reference
+	}
+
+	// Le provider peut refuser l'annulation (transaction déjà aboutie par exemple)
+	return db.Transaction(func(tx *gorm.DB) error {
+		return transaction.SetState(tx, providerRes.State, providerRes.Message)
+	})
+}
@@ -0,0 +1,133 @@
+package transactions
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResTransactionAPICaptureSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Transaction models.TransactionModel `json:"transaction"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type CaptureFormData struct {
+	Amount *float64 `json:"amount,omitempty" form:"amount" xml:"amount" validate:"omitempty,gt=0"` // Montant capturé, totalité de l'autorisation par défaut
+}
+
+// Capture
+// @Summary      	Capture authorized transaction
+// @Description  	Capture totale ou partielle d'une transaction autorisée
+// @Tags         	Transactions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData CaptureFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResTransactionAPICaptureSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/capture [post]
+func (s *TransactionApiRessource) Capture() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(CaptureFormData)
+		captureForm := CaptureFormData{}
+		err := utils.BindValidate(c, data, &captureForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		amount := transaction.AuthorizedAmount
+		if captureForm.Amount != nil {
+			amount = *captureForm.Amount
+		}
+
+		err = utils.ProviderCaptureTransaction(db, transaction.Provider, transaction, amount)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Transaction models.TransactionModel `json:"transaction"`
+		}
+
+		resp.SetData(resData{
+			Transaction: *transaction,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// Void
+// @Summary      	Void authorized transaction
+// @Description  	Libération des fonds réservés d'une transaction autorisée
+// @Tags         	Transactions
+// @Product       	json
+// @response      	200 {object} ResTransactionAPICaptureSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/void [post]
+func (s *TransactionApiRessource) Void() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		err = utils.ProviderVoidTransaction(db, transaction.Provider, transaction, "autorisation libérée")
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Transaction models.TransactionModel `json:"transaction"`
+		}
+
+		resp.SetData(resData{
+			Transaction: *transaction,
+		})
+
+		return resp.Send(c)
+	}
+}
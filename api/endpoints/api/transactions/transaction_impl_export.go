@@ -0,0 +1,123 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// Export
+// @Summary      	Export transactions
+// @Description  	Export en flux des transactions au format csv ou xlsx, accepte les mêmes filtres que la liste
+// @Tags         	Transactions
+// @Produce       	text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        	format query string false "csv (par défaut) ou xlsx"
+// @Param        	currency query string false "transaction (par défaut): devise du paiement, settlement: devise de règlement de la boutique"
+// @Param        	query query string false "Recherche"
+// @response      	200 {file} file
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/export [get]
+func (s *TransactionApiRessource) Export() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		format := strings.ToLower(c.QueryParam("format"))
+		if format == "" {
+			format = utils.EXPORT_CSV
+		}
+
+		if format != utils.EXPORT_CSV && format != utils.EXPORT_XLSX {
+			err := fmt.Errorf("format d'export inconnu %v, csv ou xlsx attendu", format)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		export, err := utils.TransactionExporter(c.QueryParam("currency"))
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation de l'utilisateur
+		loginUser := models.UserModel{}
+		loginUser.AuthId = claims["sub"].(string)
+
+		result := db.
+			Scopes(models.PreloadGrants).
+			Where(&loginUser).First(&loginUser)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msgf("")
+			if strings.Contains(result.Error.Error(), "record not found") {
+				return resp.SendError(c, "Utilisateur non reconnu", models.TransformErr(result.Error))
+			}
+
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		filters, err := parseFetchFilters(c)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		reqDb, err := fetchSelect(
+			db.Model(&models.TransactionModel{}),
+			db,
+			loginUser,
+			fetchParams{
+				FilterTransaction: c.QueryParam("filter-transaction"),
+				Filters:           filters,
+				Query:             c.QueryParam("query"),
+			},
+		)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		reqDb = reqDb.Order("created_at asc").Order("id asc")
+
+		// Envoi en flux, aucune réponse json n'est possible après les entêtes
+		fileName := fmt.Sprintf("transactions-%v.%v", time.Now().Format("20060102-150405"), format)
+		c.Response().Header().Set(echo.HeaderContentType, utils.ExportContentType(format))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+		c.Response().WriteHeader(http.StatusOK)
+
+		writer, err := utils.NewExportWriter(format, c.Response(), "transactions")
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return nil
+		}
+
+		count, err := export(db, reqDb, writer)
+		if err != nil {
+			log.Error().Err(err).Int64("rows", count).Msgf("export transactions interrompu")
+		}
+
+		if err := writer.Close(); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		return nil
+	}
+}
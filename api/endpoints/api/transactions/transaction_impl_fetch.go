@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"spay/models"
 	"spay/utils"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +35,16 @@ type ResTransactionAPIFetchSuccess struct {
 // @Description  	Récuperation des transactions paginer
 // @Tags         	Transactions
 // @Product       	json
+// @Param        	filter-state query string false "Etats séparés par virgule (PENDING,SUCCESS,CANCEL,FAIL)"
+// @Param        	filter-mode query string false "Modes séparés par virgule (CREDIT,DEBIT)"
+// @Param        	filter-provider query string false "Identifiants provider séparés par virgule"
+// @Param        	filter-service query string false "Identifiants boutique séparés par virgule"
+// @Param        	filter-currency query string false "Devises séparées par virgule (ex: XOF)"
+// @Param        	filter-live query bool false "true: live, false: sandbox"
+// @Param        	filter-amount-min query number false "Montant minimum"
+// @Param        	filter-amount-max query number false "Montant maximum"
+// @Param        	filter-date-from query string false "Date de création minimum (ex: 2023-01-31)"
+// @Param        	filter-date-to query string false "Date de création maximum incluse (ex: 2023-01-31)"
 // @response      	200 {object} ResTransactionAPIFetchSuccess
 // @response      	400 {object} models.ResFailure
 // @Router       	/api/transactions/ [get]
@@ -62,7 +73,7 @@ func (s *TransactionApiRessource) Fetch() echo.HandlerFunc {
 		loginUser.AuthId = claims["sub"].(string)
 
 		result := db.
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where(&loginUser).First(&loginUser)
 		if result.Error != nil {
 			log.Error().Err(result.Error).Msgf("")
@@ -79,12 +90,19 @@ func (s *TransactionApiRessource) Fetch() echo.HandlerFunc {
 		// 	return resp.SendError(c, err.Error(), models.TransformErr(err))
 		// }
 
+		filters, err := parseFetchFilters(c)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
 		transactions := []models.TransactionModel{}
 
 		limit, _ := c.Get("LIMIT").(int)
 		offset, _ := c.Get("OFFSET").(int)
 		query, _ := c.Get("QUERY").(string)
 		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
 
 		reqDb := db.Model(&models.TransactionModel{})
 
@@ -96,10 +114,13 @@ func (s *TransactionApiRessource) Fetch() echo.HandlerFunc {
 			&transactions,
 			fetchParams{
 				FilterTransaction: c.QueryParam("filter-transaction"),
+				Filters:           filters,
 				Orders:            orders,
 				Query:             query,
 				Limit:             limit,
 				Offset:            offset,
+				Cursor:            cursor,
+				WithCount:         withCount,
 			},
 			&count,
 		)
@@ -113,14 +134,25 @@ func (s *TransactionApiRessource) Fetch() echo.HandlerFunc {
 			Pagination   models.PaginationModel    `json:"pagination"`
 		}
 
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			transactions, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(transactions, cursor, limit)
+			pagination.Offset = 0
+		}
+
 		resp.SetData(dataResponse{
 			Transactions: transactions,
-			Pagination: models.PaginationModel{
-				Count:  count,
-				Limit:  limit,
-				Offset: offset,
-				Query:  query,
-			},
+			Pagination:   pagination,
 		})
 
 		return resp.Send(c)
@@ -128,15 +160,7 @@ func (s *TransactionApiRessource) Fetch() echo.HandlerFunc {
 }
 
 func fetchRestricted(reqDb *gorm.DB, db *gorm.DB, loginUser models.UserModel) (*gorm.DB, error) {
-	if loginUser.Role == models.USER_MERCHANT {
-		serviceIds := []string{}
-
-		for _, perm := range loginUser.ServicePermissions {
-			if loginUser.IsServiceGrant(perm.ServiceId, models.SERVICE_DEV) {
-				serviceIds = append(serviceIds, perm.ServiceId)
-			}
-		}
-
+	if serviceIds, all := loginUser.ServicesWith(models.PERM_TRANSACTIONS_READ); !all {
 		reqDb = reqDb.Where("service_id IN (?)", serviceIds)
 	}
 
@@ -146,7 +170,7 @@ func fetchRestricted(reqDb *gorm.DB, db *gorm.DB, loginUser models.UserModel) (*
 func fetchQuery(reqDb *gorm.DB, query string) *gorm.DB {
 	if len(query) > 0 {
 		reqDb = reqDb.
-			Where("lower(reference_id) LIKE ?", strings.ToLower("%"+query+"%"), strings.ToLower("%"+query+"%"))
+			Where("lower(reference_id) LIKE ? OR lower(provider_reference) LIKE ? OR lower(operation_msg) LIKE ?", strings.ToLower("%"+query+"%"), strings.ToLower("%"+query+"%"), strings.ToLower("%"+query+"%"))
 	}
 
 	return reqDb
@@ -170,7 +194,7 @@ func fetchFilterTransaction(reqDb *gorm.DB, db *gorm.DB, filter string) (*gorm.D
 
 		resultUser := db.
 			Model(&models.UserModel{}).
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where("id = ?", user.ID).
 			First(&user)
 
@@ -210,10 +234,52 @@ func fetchExec(
 	params fetchParams,
 	count *int64,
 ) error {
+	reqDb, err := fetchSelect(reqDb, db, loginUser, params)
+	if err != nil {
+		return err
+	}
+
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
+
+	result := reqDb.
+		Preload("Provider").
+		Preload("Service").
+		Find(transactions)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// fetchSelect sélection des transactions visibles par l'utilisateur, partagée avec l'export
+func fetchSelect(
+	reqDb *gorm.DB,
+	db *gorm.DB,
+	loginUser models.UserModel,
+	params fetchParams,
+) (*gorm.DB, error) {
 	// Restreindre
 	reqDb, err := fetchRestricted(reqDb, db, loginUser)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Query
@@ -222,34 +288,182 @@ func fetchExec(
 	// Filter by UserId
 	reqDb, err = fetchFilterTransaction(reqDb, db, params.FilterTransaction)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	result := reqDb.Count(count)
-	if result.Error != nil {
-		return result.Error
+	// Filtres
+	reqDb = fetchFilters(reqDb, params.Filters)
+
+	return reqDb, nil
+}
+
+// fetchFilters application des filtres combinables
+func fetchFilters(reqDb *gorm.DB, filters fetchFilterParams) *gorm.DB {
+	if len(filters.States) > 0 {
+		reqDb = reqDb.Where("operation_state IN (?)", filters.States)
 	}
 
-	// Orders
-	reqDb = fetchOrder(reqDb, params.Orders)
+	if len(filters.Modes) > 0 {
+		reqDb = reqDb.Where("operation_mode IN (?)", filters.Modes)
+	}
 
-	result = reqDb.
-		Preload("Provider").
-		Preload("Service").
-		Limit(int(params.Limit)).
-		Offset(int(params.Offset)).
-		Find(transactions)
-	if result.Error != nil {
-		return result.Error
+	if len(filters.ProviderIds) > 0 {
+		reqDb = reqDb.Where("provider_id IN (?)", filters.ProviderIds)
 	}
 
-	return nil
+	if len(filters.ServiceIds) > 0 {
+		reqDb = reqDb.Where("service_id IN (?)", filters.ServiceIds)
+	}
+
+	if len(filters.Currencies) > 0 {
+		reqDb = reqDb.Where("currency IN (?)", filters.Currencies)
+	}
+
+	if filters.ModeLive != nil {
+		reqDb = reqDb.Where("mode_live = ?", *filters.ModeLive)
+	}
+
+	if filters.AmountMin != nil {
+		reqDb = reqDb.Where("amount >= ?", *filters.AmountMin)
+	}
+
+	if filters.AmountMax != nil {
+		reqDb = reqDb.Where("amount <= ?", *filters.AmountMax)
+	}
+
+	if filters.DateFrom != nil {
+		reqDb = reqDb.Where("created_at >= ?", *filters.DateFrom)
+	}
+
+	if filters.DateTo != nil {
+		// Date de fin incluse
+		reqDb = reqDb.Where("created_at < ?", filters.DateTo.AddDate(0, 0, 1))
+	}
+
+	return reqDb
+}
+
+// parseFetchFilters lecture des filtres depuis les paramètres de la requête
+func parseFetchFilters(c echo.Context) (fetchFilterParams, error) {
+	filters := fetchFilterParams{
+		States:      queryList(c.QueryParam("filter-state"), true),
+		Modes:       queryList(c.QueryParam("filter-mode"), true),
+		ProviderIds: queryList(c.QueryParam("filter-provider"), false),
+		ServiceIds:  queryList(c.QueryParam("filter-service"), false),
+		Currencies:  queryList(c.QueryParam("filter-currency"), true),
+	}
+
+	for _, state := range filters.States {
+		switch state {
+		case models.TRANSACTION_PENDING, models.TRANSACTION_SUCCESS, models.TRANSACTION_CANCEL, models.TRANSACTION_FAIL,
+			models.TRANSACTION_AUTHORIZED, models.TRANSACTION_VOID:
+		default:
+			return filters, fmt.Errorf("filtre état invalide %v", state)
+		}
+	}
+
+	for _, mode := range filters.Modes {
+		if mode != models.OPERATION_CREDIT && mode != models.OPERATION_DEBIT {
+			return filters, fmt.Errorf("filtre mode invalide %v", mode)
+		}
+	}
+
+	if live := c.QueryParam("filter-live"); live != "" {
+		modeLive, err := strconv.ParseBool(live)
+		if err != nil {
+			return filters, fmt.Errorf("filtre live invalide %v", live)
+		}
+
+		filters.ModeLive = &modeLive
+	}
+
+	if min := c.QueryParam("filter-amount-min"); min != "" {
+		amount, err := strconv.ParseFloat(min, 64)
+		if err != nil {
+			return filters, fmt.Errorf("filtre montant minimum invalide %v", min)
+		}
+
+		filters.AmountMin = &amount
+	}
+
+	if max := c.QueryParam("filter-amount-max"); max != "" {
+		amount, err := strconv.ParseFloat(max, 64)
+		if err != nil {
+			return filters, fmt.Errorf("filtre montant maximum invalide %v", max)
+		}
+
+		filters.AmountMax = &amount
+	}
+
+	if filters.AmountMin != nil && filters.AmountMax != nil && *filters.AmountMin > *filters.AmountMax {
+		return filters, fmt.Errorf("filtre montant minimum supérieur au maximum")
+	}
+
+	if from := c.QueryParam("filter-date-from"); from != "" {
+		ok, date := utils.StringToDate(from, utils.LAYOUTS_TIME)
+		if !ok {
+			return filters, fmt.Errorf("filtre date de début invalide %v", from)
+		}
+
+		filters.DateFrom = &date
+	}
+
+	if to := c.QueryParam("filter-date-to"); to != "" {
+		ok, date := utils.StringToDate(to, utils.LAYOUTS_TIME)
+		if !ok {
+			return filters, fmt.Errorf("filtre date de fin invalide %v", to)
+		}
+
+		filters.DateTo = &date
+	}
+
+	if filters.DateFrom != nil && filters.DateTo != nil && filters.DateFrom.After(*filters.DateTo) {
+		return filters, fmt.Errorf("filtre date de début postérieure à la date de fin")
+	}
+
+	return filters, nil
+}
+
+// queryList découpage d'un paramètre à valeurs multiples séparées par virgule
+func queryList(param string, upper bool) []string {
+	list := []string{}
+
+	for _, value := range strings.Split(param, ",") {
+		value = strings.Trim(value, " ")
+		if value == "" {
+			continue
+		}
+
+		if upper {
+			value = strings.ToUpper(value)
+		}
+
+		list = append(list, value)
+	}
+
+	return utils.ArrayUnique(list)
+}
+
+type fetchFilterParams struct {
+	States      []string
+	Modes       []string
+	ProviderIds []string
+	ServiceIds  []string
+	Currencies  []string
+	ModeLive    *bool
+	AmountMin   *float64
+	AmountMax   *float64
+	DateFrom    *time.Time
+	DateTo      *time.Time
 }
 
 type fetchParams struct {
 	FilterTransaction string
+	Filters           fetchFilterParams
 	Orders            []string
 	Query             string
 	Limit             int
 	Offset            int
+	Cursor            *models.Cursor
+	WithCount         bool
 }
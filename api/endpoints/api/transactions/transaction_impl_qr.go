@@ -0,0 +1,82 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// QrCode
+// @Summary      	Transaction QR code
+// @Description  	QR code de la transaction (page de suivi du lien de paiement ou QR marchand du provider)
+// @Tags         	Transactions
+// @Product       	png,svg
+// @Param        	format query string false "png (défaut) ou svg"
+// @Param        	size query int false "Taille en pixel (64 à 1024, défaut 256)"
+// @Param        	level query string false "Correction d'erreur L, M (défaut), Q, H"
+// @Param        	payload query string false "checkout (défaut) ou provider"
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/qr [get]
+func (s *TransactionApiRessource) QrCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		options, err := utils.QrOptionsFromQuery(c)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		var content string
+		switch options.Payload {
+		case utils.QR_PAYLOAD_PROVIDER:
+			if transaction.IsFinal() {
+				err := fmt.Errorf("transaction déjà finalisée")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			content, err = utils.ProviderQrPayload(transaction.Provider, *transaction)
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+		default:
+			// Seules les transactions d'un lien de paiement ont une page de suivi
+			paymentLink := models.PaymentLinkModel{}
+			result := db.Model(&paymentLink).Where("id = ?", transaction.PaymentLinkId).First(&paymentLink)
+			if transaction.PaymentLinkId == "" || result.Error != nil {
+				err := fmt.Errorf("transaction sans page de paiement")
+				return resp.SendError(c, err.Error(), models.TransformErr(err))
+			}
+
+			content = utils.CheckoutUrl(paymentLink.Code) + "/" + transaction.ID
+		}
+
+		image, contentType, err := utils.QrCode(content, options)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		return c.Blob(http.StatusOK, contentType, image)
+	}
+}
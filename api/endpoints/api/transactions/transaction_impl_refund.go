@@ -0,0 +1,143 @@
+package transactions
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResTransactionAPIRefundSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Refund   *models.RefundModel          `json:"refund,omitempty"`
+		Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type RefundFormData struct {
+	Amount *float64 `json:"amount,omitempty" form:"amount" xml:"amount" validate:"omitempty,gt=0"` // Montant remboursé, reste de la transaction par défaut
+	Reason string   `json:"reason" form:"reason" xml:"reason" validate:"required"`                 // Motif du remboursement
+}
+
+// Refund
+// @Summary      	Refund transaction
+// @Description  	Remboursement total ou partiel d'un paiement réussi, les parts des bénéficiaires sont reprises proportionnellement,
+// @Description  	le remboursement peut nécessiter une approbation (202) selon la politique transaction.refund
+// @Tags         	Transactions
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData RefundFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResTransactionAPIRefundSuccess
+// @response      	202 {object} ResTransactionAPIRefundSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/refund [post]
+func (s *TransactionApiRessource) Refund() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser, ok := c.Get("LOGIN_USER").(*models.UserModel)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if transaction.OperationState != models.TRANSACTION_SUCCESS || transaction.OperationMode != models.OPERATION_CREDIT {
+			err := fmt.Errorf("seul un paiement réussi peut être remboursé")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(RefundFormData)
+		refundForm := RefundFormData{}
+		err := utils.BindValidate(c, data, &refundForm)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		refund := models.RefundModel{
+			TransactionId: transaction.ID,
+			Reason:        refundForm.Reason,
+			CreatedById:   loginUser.ID,
+		}
+
+		// Sans montant la politique est évaluée sur le reste non remboursé
+		amount := models.RoundAmount(transaction.Amount - transaction.RefundedAmount)
+		if refundForm.Amount != nil {
+			refund.Amount = *refundForm.Amount
+			amount = refund.Amount
+		}
+
+		var approval *models.ApprovalRequestModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			approval, err = models.RequestApproval(tx, utils.AuditActor(c), models.ApprovalRequestModel{
+				Operation:   models.APPROVAL_TRANSACTION_REFUND,
+				ServiceId:   transaction.ServiceId,
+				TargetId:    transaction.ID,
+				Amount:      amount,
+				Currency:    transaction.Currency,
+				RequestedBy: loginUser.ID,
+			}, refund)
+			if err != nil || approval != nil {
+				return err
+			}
+
+			if err := models.RefundTransaction(tx, &refund); err != nil {
+				return err
+			}
+
+			return models.Audit(tx, utils.AuditActor(c), models.AUDIT_TRANSACTION_REFUND, "refund", refund.ID, nil, refund)
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Refund   *models.RefundModel          `json:"refund,omitempty"`
+			Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
+		}
+
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+			resp.SetData(resData{
+				Approval: approval,
+			})
+
+			return resp.Send(c)
+		}
+
+		resp.SetData(resData{
+			Refund: &refund,
+		})
+
+		return resp.Send(c)
+	}
+}
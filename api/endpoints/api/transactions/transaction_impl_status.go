@@ -0,0 +1,77 @@
+package transactions
+
+import (
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type ResTransactionAPIStatusSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		Transaction   models.TransactionModel `json:"transaction"`
+		PreviousState string                  `json:"previous_state"`
+		Changed       bool                    `json:"changed"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+// CheckStatus
+// @Summary      	Check transaction status
+// @Description  	Vérification de l'état de la transaction auprès du provider
+// @Tags         	Transactions
+// @Product       	json
+// @response      	200 {object} ResTransactionAPIStatusSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/transactions/:id/status [get]
+func (s *TransactionApiRessource) CheckStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		transaction, ok := c.Get("TRANSACTION").(*models.TransactionModel)
+		if !ok {
+			err := fmt.Errorf("transaction non valide")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		previousState := transaction.OperationState
+
+		err = utils.ProviderSyncTransaction(db, transaction)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			Transaction   models.TransactionModel `json:"transaction"`
+			PreviousState string                  `json:"previous_state"`
+			Changed       bool                    `json:"changed"`
+		}
+
+		resp.SetData(resData{
+			Transaction:   *transaction,
+			PreviousState: previousState,
+			Changed:       previousState != transaction.OperationState,
+		})
+
+		return resp.Send(c)
+	}
+}
@@ -11,6 +11,9 @@ type UserApiRessource struct {
 	*models.UserModel
 }
 
+// Colonnes autorisées pour le tri
+var userSorts = []string{"first_name", "last_name", "email", "country", "role", "created_at", "updated_at"}
+
 func AttachAPI(server *echo.Group) {
 	userApi := &UserApiRessource{&models.UserModel{}}
 
@@ -18,13 +21,17 @@ func AttachAPI(server *echo.Group) {
 	userApiService := server.Group("/users")
 	{
 		// Fetch
-		userApiService.GET("/", userApi.Fetch(), middlewares.GrantMid(), models.PaginationMid())
+		userApiService.GET("/", userApi.Fetch(), middlewares.GrantMid(), models.PaginationMid(userSorts...))
 
 		// Add
 		userApiService.POST("/", userApi.Add())
 
 		// Change Role
-		userApiService.POST("/change-role", userApi.ChangeRole(), middlewares.GrantMid())
+		userApiService.POST("/change-role", userApi.ChangeRole(), middlewares.GrantMid(), middlewares.PermMid(models.PERM_PLATFORM_USERS_ROLE))
+
+		// Invitations
+		userApiService.POST("/invitations/accept", userApi.AcceptInvitation(), middlewares.GrantMid())
+		userApiService.POST("/invitations/register", userApi.RegisterInvitation())
 
 		// Login
 		userApiService.POST("/login", userApi.Login())
@@ -138,7 +138,7 @@ func createUserDb(newUser *models.UserModel, password string) (*gocloak.JWT, err
 	result := db.Create(&newUser)
 	if result.Error != nil {
 		// Suppression de l'utilisateur créer dans keycloak
-		delKeycloakUser()
+		delKeycloakUser(newUser.AuthId)
 
 		log.Error().Err(result.Error).Msgf("")
 
@@ -191,6 +191,17 @@ func registerKeycloakUser(newUser *models.UserModel, password string) (*gocloak.
 	return &newKcUser, userToken, nil
 }
 
-func delKeycloakUser() error {
-	return nil
+func delKeycloakUser(authId string) error {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Connexion en tant qu'admin
+	_, kcClientToken, err := utils.KeycloakLoginClient(config)
+	if err != nil {
+		return err
+	}
+
+	return utils.KeycloakDeleteUser(config, kcClientToken.AccessToken, authId)
 }
@@ -62,7 +62,7 @@ func (u *UserApiRessource) Fetch() echo.HandlerFunc {
 		loginUser.AuthId = claims["sub"].(string)
 
 		result := db.
-			Preload("ServicePermissions").
+			Scopes(models.PreloadGrants).
 			Where(&loginUser).First(&loginUser)
 		if result.Error != nil {
 			log.Error().Err(result.Error).Msgf("")
@@ -85,6 +85,8 @@ func (u *UserApiRessource) Fetch() echo.HandlerFunc {
 		offset, _ := c.Get("OFFSET").(int)
 		query, _ := c.Get("QUERY").(string)
 		orders, _ := c.Get("ORDERS").([]string)
+		cursor, _ := c.Get("CURSOR").(*models.Cursor)
+		withCount, _ := c.Get("WITH_COUNT").(bool)
 
 		reqDb := db.Model(&models.UserModel{})
 
@@ -100,6 +102,8 @@ func (u *UserApiRessource) Fetch() echo.HandlerFunc {
 				Query:         query,
 				Limit:         limit,
 				Offset:        offset,
+				Cursor:        cursor,
+				WithCount:     withCount,
 			},
 			&count,
 		)
@@ -113,14 +117,25 @@ func (u *UserApiRessource) Fetch() echo.HandlerFunc {
 			Pagination models.PaginationModel `json:"pagination"`
 		}
 
+		pagination := models.PaginationModel{
+			Limit:  limit,
+			Offset: offset,
+			Query:  query,
+		}
+
+		if withCount {
+			pagination.Count = &count
+		}
+
+		// Pagination par curseur
+		if cursor != nil {
+			users, pagination.NextCursor, pagination.PrevCursor = models.CursorResult(users, cursor, limit)
+			pagination.Offset = 0
+		}
+
 		resp.SetData(dataResponse{
-			Users: users,
-			Pagination: models.PaginationModel{
-				Count:  count,
-				Limit:  limit,
-				Offset: offset,
-				Query:  query,
-			},
+			Users:      users,
+			Pagination: pagination,
 		})
 
 		return resp.Send(c)
@@ -128,15 +143,7 @@ func (u *UserApiRessource) Fetch() echo.HandlerFunc {
 }
 
 func fetchRestricted(reqDb *gorm.DB, db *gorm.DB, loginUser models.UserModel) (*gorm.DB, error) {
-	if loginUser.Role == models.USER_MERCHANT {
-		ids := []string{}
-
-		for _, perm := range loginUser.ServicePermissions {
-			if loginUser.IsServiceGrant(perm.ServiceId, models.SERVICE_MANAGER) {
-				ids = append(ids, perm.ServiceId)
-			}
-		}
-
+	if ids, all := loginUser.ServicesWith(models.PERM_MEMBERS_READ); !all {
 		perms := []models.ServicePermissionModel{}
 
 		// Récuperation de toute les permission
@@ -239,19 +246,28 @@ func fetchExec(
 		return err
 	}
 
-	result := reqDb.Count(count)
-	if result.Error != nil {
-		return result.Error
+	if params.WithCount {
+		result := reqDb.Count(count)
+		if result.Error != nil {
+			return result.Error
+		}
 	}
 
-	// Orders
-	reqDb = fetchOrder(reqDb, params.Orders)
+	if params.Cursor != nil {
+		// Pagination par curseur
+		reqDb = models.CursorQuery(reqDb, params.Cursor, params.Limit)
+	} else {
+		// Orders
+		reqDb = fetchOrder(reqDb, params.Orders)
+
+		reqDb = reqDb.
+			Limit(int(params.Limit)).
+			Offset(int(params.Offset))
+	}
 
-	result = reqDb.
-		Preload("ServicePermissions").
+	result := reqDb.
+		Scopes(models.PreloadGrants).
 		Preload("ServicePermissions.Service").
-		Limit(int(params.Limit)).
-		Offset(int(params.Offset)).
 		Find(users)
 	if result.Error != nil {
 		return result.Error
@@ -266,4 +282,6 @@ type fetchParams struct {
 	Query         string
 	Limit         int
 	Offset        int
+	Cursor        *models.Cursor
+	WithCount     bool
 }
@@ -0,0 +1,264 @@
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/Nerzal/gocloak/v12"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ResUserInvitationAPIAcceptSuccess struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	IsError bool   `json:"is_error"`
+
+	Data struct {
+		User              models.UserModel              `json:"user"`
+		ServicePermission models.ServicePermissionModel `json:"service_permission"`
+		Token             string                        `json:"token,omitempty"`
+		RefreshToken      string                        `json:"refresh_token,omitempty"`
+	} `json:"data"`
+
+	RequestDate time.Time `json:"request_date"`
+	TimeElapsed string    `json:"time_elapsed"`
+}
+
+type AcceptInvitationFormData struct {
+	Token string `json:"token" form:"token" xml:"token" validate:"required"` // Jeton reçu par e-mail
+}
+
+type RegisterInvitationFormData struct {
+	Token string `json:"token" form:"token" xml:"token" validate:"required"` // Jeton reçu par e-mail
+
+	AddFormData
+}
+
+// AcceptInvitation
+// @Summary      	Accept service invitation
+// @Description  	Acceptation d'une invitation par l'utilisateur connecté, ajout de sa permission sur la boutique
+// @Tags         	Users
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData AcceptInvitationFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResUserInvitationAPIAcceptSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/users/invitations/accept [post]
+func (u *UserApiRessource) AcceptInvitation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims)
+		if !ok {
+			err := fmt.Errorf("authentification obligatoire")
+			resp.SetStatus(http.StatusUnauthorized)
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Récuperation des données du formulaire
+		data := new(AcceptInvitationFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		loginUser := models.UserModel{}
+		loginUser.AuthId = claims["sub"].(string)
+
+		result := db.Where(&loginUser).First(&loginUser)
+		if result.Error != nil {
+			if strings.Contains(result.Error.Error(), "record not found") {
+				return resp.SendError(c, "Utilisateur non reconnu", models.TransformErr(result.Error))
+			}
+
+			return resp.SendError(c, result.Error.Error(), models.TransformErr(result.Error))
+		}
+
+		permission, err := acceptInvitation(db, data.Token, &loginUser, utils.AuditActor(c))
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			User              models.UserModel              `json:"user"`
+			ServicePermission models.ServicePermissionModel `json:"service_permission"`
+		}
+
+		resp.SetData(resData{
+			User:              loginUser,
+			ServicePermission: *permission,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// RegisterInvitation
+// @Summary      	Register with service invitation
+// @Description  	Création du compte de l'invité avec l'adresse e-mail de l'invitation puis acceptation de l'invitation
+// @Tags         	Users
+// @accept 			json,xml,x-www-form-urlencoded,mpfd
+// @Product       	json
+// @Param        	data formData RegisterInvitationFormData  false  "Contenu de la requete" ""
+// @response      	200 {object} ResUserInvitationAPIAcceptSuccess
+// @response      	400 {object} models.ResFailure
+// @Router       	/api/users/invitations/register [post]
+func (u *UserApiRessource) RegisterInvitation() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		resp, ok := c.Get("RESP").(*models.ResponseAPI[interface{}])
+		if !ok {
+			resp = models.NewResponseAPI[interface{}]()
+		}
+
+		// Récuperation des données du formulaire
+		data := new(RegisterInvitationFormData)
+		if err := c.Bind(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if err := c.Validate(data); err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Connexion à la base de donnée
+		db, err := models.GetDB()
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Contrôle du jeton avant la création du compte, refait dans la transaction d'acceptation
+		invitation, err := models.FindInvitation(db, data.Token)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		if invitation.State != models.INVITATION_PENDING || invitation.IsExpired(time.Now()) {
+			err := fmt.Errorf("invitation expirée, déjà utilisée ou révoquée")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// Encodage des donnée en json pour faciliter le traitement
+		dataJson, err := json.Marshal(data.AddFormData)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newUser := models.UserModel{}
+		err = json.Unmarshal(dataJson, &newUser)
+		if err != nil {
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		// L'adresse invitée est celle du compte
+		newUser.Email = invitation.Email
+
+		kcUser, token, err := registerKeycloakUser(&newUser, data.Password)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		newUser.AuthId = gocloak.PString(kcUser.ID)
+
+		// Requête sans authentification, l'auteur est le compte créé
+		actor := utils.AuditActor(c)
+		actor.ActorId = newUser.AuthId
+
+		// Le compte et sa permission sont créés ensemble, l'échec de l'acceptation
+		// (invitation révoquée ou acceptée entre temps) ne laisse pas de compte orphelin
+		var permission *models.ServicePermissionModel
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&newUser).Error; err != nil {
+				if strings.Contains(err.Error(), "duplicate key value violates") {
+					return fmt.Errorf("utilisateur existe déjà")
+				}
+
+				return err
+			}
+
+			permission, err = acceptInvitationTx(tx, data.Token, &newUser, actor)
+			return err
+		})
+		if err != nil {
+			// Suppression de l'utilisateur créer dans keycloak
+			if err := delKeycloakUser(newUser.AuthId); err != nil {
+				log.Error().Err(err).Msgf("")
+			}
+
+			log.Error().Err(err).Msgf("")
+			return resp.SendError(c, err.Error(), models.TransformErr(err))
+		}
+
+		type resData struct {
+			User              models.UserModel              `json:"user"`
+			ServicePermission models.ServicePermissionModel `json:"service_permission"`
+			Token             string                        `json:"token"`
+			RefreshToken      string                        `json:"refresh_token"`
+		}
+
+		resp.SetData(resData{
+			User:              newUser,
+			ServicePermission: *permission,
+			Token:             token.AccessToken,
+			RefreshToken:      token.RefreshToken,
+		})
+
+		return resp.Send(c)
+	}
+}
+
+// acceptInvitation création de la permission de l'utilisateur et journalisation
+func acceptInvitation(db *gorm.DB, token string, user *models.UserModel, actor models.AuditActor) (*models.ServicePermissionModel, error) {
+	var permission *models.ServicePermissionModel
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		permission, err = acceptInvitationTx(tx, token, user, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+// acceptInvitationTx acceptation dans la transaction de l'appelant
+func acceptInvitationTx(tx *gorm.DB, token string, user *models.UserModel, actor models.AuditActor) (*models.ServicePermissionModel, error) {
+	invitation, err := models.FindInvitation(tx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := invitation.Accept(tx, user, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.Audit(tx, actor, models.AUDIT_SERVICE_PERMISSION_ADD, "service_permission", permission.ID, nil, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"spay/models"
+	"spay/utils"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -11,6 +12,7 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type ResUserAPIRoleSuccess struct {
@@ -19,8 +21,9 @@ type ResUserAPIRoleSuccess struct {
 	IsError bool   `json:"is_error"`
 
 	Data struct {
-		User    models.UserModel `json:"user"`
-		NewRole string           `json:"new_role"`
+		User     models.UserModel             `json:"user"`
+		NewRole  string                       `json:"new_role"`
+		Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
 	} `json:"data"`
 
 	RequestDate time.Time `json:"request_date"`
@@ -35,12 +38,14 @@ type ChangeRoleFormData struct {
 // ChangeRole
 // @Summary      	Change user role
 // @Security 		ApiKeyAuth
-// @Description  	Changement de la permission de l'utilisateur
+// @Description  	Changement de la permission de l'utilisateur, mise en attente d'approbation (202)
+// @Description  	selon la politique user.change_role
 // @Tags         	Users
 // @accept 			json,xml,x-www-form-urlencoded,mpfd
 // @Product       	json
 // @Param        	data formData ChangeRoleFormData  false  "Contenu de la requete" ""
 // @response      	200 {object} ResUserAPIRoleSuccess
+// @response      	202 {object} ResUserAPIRoleSuccess
 // @response      	400 {object} models.ResFailure
 // @Router       	/api/users/change-role [post]
 func (u *UserApiRessource) ChangeRole() echo.HandlerFunc {
@@ -86,64 +91,98 @@ func (u *UserApiRessource) ChangeRole() echo.HandlerFunc {
 		}
 
 		// Récuperation de l'utilisateur actuel
-		userForUpdate, err := updateUserRole(claims, *data)
+		userForUpdate, approval, err := updateUserRole(claims, *data, utils.AuditActor(c))
 		if err != nil {
 			log.Error().Err(err).Msgf("")
 			return resp.SendError(c, err.Error(), models.TransformErr(err))
 		}
 
+		if approval != nil {
+			resp.SetStatus(http.StatusAccepted)
+		}
+
 		type dataResp struct {
-			User    models.UserModel `json:"user"`
-			NewRole string           `json:"new_role"`
+			User     models.UserModel             `json:"user"`
+			NewRole  string                       `json:"new_role"`
+			Approval *models.ApprovalRequestModel `json:"approval,omitempty"`
 		}
 
 		resp.SetData(dataResp{
-			User:    *userForUpdate,
-			NewRole: models.UserRole(data.Role).String(),
+			User:     *userForUpdate,
+			NewRole:  models.UserRole(data.Role).String(),
+			Approval: approval,
 		})
 
 		return resp.Send(c)
 	}
 }
 
-func updateUserRole(claims jwt.MapClaims, data ChangeRoleFormData) (*models.UserModel, error) {
+// updateUserRole changement du rôle, ou demande d'approbation selon la politique user.change_role
+func updateUserRole(claims jwt.MapClaims, data ChangeRoleFormData, actor models.AuditActor) (*models.UserModel, *models.ApprovalRequestModel, error) {
 	logeduser := models.UserModel{}
 	userForUpdate := models.UserModel{}
 
 	db, err := models.GetDB()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	result := db.Model(&logeduser).Where("auth_id = ?", claims["sub"]).First(&logeduser)
+	result := db.Model(&logeduser).Where("auth_id = ?", claims["sub"]).Limit(1).Find(&logeduser)
 	if result.Error != nil {
-		return nil, err
+		return nil, nil, result.Error
 	}
 
-	// Verification du role
-	if !logeduser.IsGrant(models.USER_ADMIN) {
-		err := fmt.Errorf("permission insuffisante, vous devez être administrateur pour cette opération")
-		return nil, err
+	if result.RowsAffected == 0 {
+		return nil, nil, gorm.ErrRecordNotFound
 	}
 
 	// Récuperation de l'utilisateur à modifier
-	result = db.Model(&userForUpdate).Where("auth_id = ?", data.AuthId).First(&userForUpdate)
+	result = db.Model(&userForUpdate).Where("auth_id = ?", data.AuthId).Limit(1).Find(&userForUpdate)
 	if result.Error != nil {
-		return nil, err
+		return nil, nil, result.Error
 	}
 
-	role := models.UserRole(data.Role)
-	userForUpdate.Role = role
+	if result.RowsAffected == 0 {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
 
 	if userForUpdate.ID == logeduser.ID {
 		err := fmt.Errorf("interdition de modifier son propre rôle")
-		return nil, err
+		return nil, nil, err
 	}
 
-	result = db.Save(&userForUpdate)
-	if result.Error != nil {
-		return nil, err
+	var approval *models.ApprovalRequestModel
+	err = db.Transaction(func(tx *gorm.DB) error {
+		approval, err = models.RequestApproval(tx, actor, models.ApprovalRequestModel{
+			Operation:   models.APPROVAL_USER_ROLE,
+			TargetId:    userForUpdate.AuthId,
+			RequestedBy: logeduser.ID,
+		}, models.UserRoleChange{Role: data.Role})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if approval != nil {
+		return &userForUpdate, approval, nil
+	}
+
+	before := userForUpdate
+
+	role := models.UserRole(data.Role)
+	userForUpdate.Role = role
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&userForUpdate).Error; err != nil {
+			return err
+		}
+
+		return models.Audit(tx, actor, models.AUDIT_USER_CHANGE_ROLE, "user", userForUpdate.ID, before, userForUpdate)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &userForUpdate, nil
+	return &userForUpdate, nil, nil
 }
@@ -126,13 +126,13 @@ func checkForUpdateUser(claims jwt.MapClaims, updateUser *models.UserModel, data
 	}
 
 	result := db.
-		Preload("ServicePermissions").
+		Scopes(models.PreloadGrants).
 		Where(&loginUser).First(&loginUser)
 	if result.Error != nil {
 		return result.Error
 	}
 
-	if !loginUser.IsGrant(models.USER_MANAGER) && (loginUser.IsGrant(models.USER_MERCHANT) && loginUser.AuthId != updateUser.AuthId) {
+	if loginUser.AuthId != updateUser.AuthId && !loginUser.Can(models.PERM_PLATFORM_USERS, "") {
 		err := fmt.Errorf("permission non accordé")
 		return err
 	}
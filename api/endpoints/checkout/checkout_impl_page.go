@@ -0,0 +1,169 @@
+package checkout
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type pageData struct {
+	Service    *models.ServiceModel
+	Link       *models.PaymentLinkModel
+	Providers  []models.ProviderModel
+	Action     string
+	ProviderId string
+	Phone      string
+	Error      string
+}
+
+// Page
+// @Summary      	Hosted checkout page
+// @Description  	Page de paiement hébergée d'un lien de paiement
+// @Tags         	Checkout
+// @Product       	html
+// @Router       	/checkout/:code [get]
+func (s *CheckoutRessource) Page() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			return notFound(c)
+		}
+
+		if err := checkUsable(paymentLink); err != nil {
+			return renderError(c, http.StatusGone, paymentLink.Service, err)
+		}
+
+		return renderPage(c, http.StatusOK, paymentLink, pageData{})
+	}
+}
+
+// Pay
+// @Summary      	Pay a payment link
+// @Description  	Création de la transaction et demande de paiement auprès du provider choisi
+// @Tags         	Checkout
+// @accept 			x-www-form-urlencoded
+// @Product       	html
+// @Param        	provider_id formData string true "Identifiant du provider"
+// @Param        	phone formData string true "Numéro de téléphone du payeur"
+// @Router       	/checkout/:code [post]
+func (s *CheckoutRessource) Pay() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			return notFound(c)
+		}
+
+		data := pageData{
+			ProviderId: c.FormValue("provider_id"),
+			Phone:      c.FormValue("phone"),
+		}
+
+		service := models.ServiceModel{}
+		service.ID = paymentLink.ServiceId
+		if paymentLink.Service != nil {
+			service = *paymentLink.Service
+		}
+
+		// Validation selon le plan de numérotation du pays de la boutique
+		number, err := utils.ParsePayerPhone(service.Country, data.Phone)
+		if err != nil {
+			data.Error = err.Error()
+			return renderPage(c, http.StatusBadRequest, paymentLink, data)
+		}
+
+		data.Phone = number.E164
+
+		providers, err := linkProviders(paymentLink)
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return renderError(c, http.StatusInternalServerError, paymentLink.Service, fmt.Errorf("service indisponible"))
+		}
+
+		var provider *models.ProviderModel
+		for i := range providers {
+			if providers[i].ID == data.ProviderId {
+				provider = &providers[i]
+				break
+			}
+		}
+
+		if provider == nil {
+			data.Error = "moyen de paiement non disponible"
+			return renderPage(c, http.StatusBadRequest, paymentLink, data)
+		}
+
+		if err := utils.CheckProviderOperator(*provider, *number); err != nil {
+			data.Error = err.Error()
+			return renderPage(c, http.StatusBadRequest, paymentLink, data)
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return renderError(c, http.StatusInternalServerError, paymentLink.Service, fmt.Errorf("service indisponible"))
+		}
+
+		transaction := models.TransactionModel{
+			ModeLive:       paymentLink.ModeLive,
+			Amount:         paymentLink.Amount,
+			AmountWithFee:  paymentLink.Amount,
+			OperationMode:  models.OPERATION_CREDIT,
+			OperationState: models.TRANSACTION_PENDING,
+			ProviderId:     provider.ID,
+			ServiceId:      paymentLink.ServiceId,
+			ReferenceId:    paymentLink.Code,
+			Currency:       paymentLink.Currency,
+			PayerPhone:     data.Phone,
+			PayerIp:        c.RealIP(),
+			PaymentLinkId:  paymentLink.ID,
+		}
+
+		// Le lien est revérifié au moment de la création
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := paymentLink.CheckUsable(tx, time.Now()); err != nil {
+				return err
+			}
+
+			customer, err := utils.SaveCustomer(tx, service, models.CustomerModel{Phone: data.Phone})
+			if err != nil {
+				return err
+			}
+
+			transaction.CustomerId = customer.ID
+
+			return tx.Create(&transaction).Error
+		})
+		if err != nil {
+			data.Error = err.Error()
+			return renderPage(c, http.StatusBadRequest, paymentLink, data)
+		}
+
+		// Un refus du provider est affiché sur la page de suivi
+		if err := utils.ProviderStartTransaction(db, *provider, &transaction); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		return c.Redirect(http.StatusSeeOther, checkoutPath(paymentLink.Code, transaction.ID))
+	}
+}
+
+func renderPage(c echo.Context, status int, paymentLink *models.PaymentLinkModel, data pageData) error {
+	providers, err := linkProviders(paymentLink)
+	if err != nil {
+		log.Error().Err(err).Msgf("")
+		return renderError(c, http.StatusInternalServerError, paymentLink.Service, fmt.Errorf("service indisponible"))
+	}
+
+	data.Service = paymentLink.Service
+	data.Link = paymentLink
+	data.Providers = providers
+	data.Action = checkoutPath(paymentLink.Code)
+
+	return render(c, status, "checkout.html", data)
+}
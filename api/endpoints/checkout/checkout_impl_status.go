@@ -0,0 +1,149 @@
+package checkout
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// StatusPage
+// @Summary      	Checkout status page
+// @Description  	Suivi en direct de l'état du paiement
+// @Tags         	Checkout
+// @Product       	html
+// @Router       	/checkout/:code/:transaction_id [get]
+func (s *CheckoutRessource) StatusPage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			return notFound(c)
+		}
+
+		transaction, err := getLinkTransaction(c, paymentLink)
+		if err != nil {
+			return renderError(c, http.StatusNotFound, paymentLink.Service, err)
+		}
+
+		return render(c, http.StatusOK, "status.html", map[string]interface{}{
+			"Service":      paymentLink.Service,
+			"Link":         paymentLink,
+			"Transaction":  transaction,
+			"Labels":       stateLabels,
+			"StatusUrl":    checkoutPath(paymentLink.Code, transaction.ID, "status"),
+			"CancelAction": checkoutPath(paymentLink.Code, transaction.ID, "cancel"),
+		})
+	}
+}
+
+// Status
+// @Summary      	Checkout transaction status
+// @Description  	Etat du paiement, vérifié auprès du provider tant qu'il est en attente
+// @Tags         	Checkout
+// @Product       	json
+// @Router       	/checkout/:code/:transaction_id/status [get]
+func (s *CheckoutRessource) Status() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "lien de paiement inexistant"})
+		}
+
+		transaction, err := getLinkTransaction(c, paymentLink)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "service indisponible"})
+		}
+
+		// Une indisponibilité du provider ne bloque pas le suivi
+		if err := utils.ProviderSyncTransaction(db, transaction); err != nil {
+			log.Error().Err(err).Msgf("")
+		}
+
+		type statusData struct {
+			State   string `json:"state"`
+			Message string `json:"message"`
+			Final   bool   `json:"final"`
+		}
+
+		return c.JSON(http.StatusOK, statusData{
+			State:   transaction.OperationState,
+			Message: transaction.OperationMsg,
+			Final:   transaction.IsFinal(),
+		})
+	}
+}
+
+// Cancel
+// @Summary      	Cancel checkout transaction
+// @Description  	Abandon du paiement par le payeur puis retour au marchand
+// @Tags         	Checkout
+// @Product       	html
+// @Router       	/checkout/:code/:transaction_id/cancel [post]
+func (s *CheckoutRessource) Cancel() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paymentLink, ok := c.Get("PAYMENT_LINK").(*models.PaymentLinkModel)
+		if !ok {
+			return notFound(c)
+		}
+
+		transaction, err := getLinkTransaction(c, paymentLink)
+		if err != nil {
+			return renderError(c, http.StatusNotFound, paymentLink.Service, err)
+		}
+
+		db, err := models.GetDB()
+		if err != nil {
+			log.Error().Err(err).Msgf("")
+			return renderError(c, http.StatusInternalServerError, paymentLink.Service, fmt.Errorf("service indisponible"))
+		}
+
+		if !transaction.IsFinal() {
+			err := cancelTransaction(db, transaction)
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return renderError(c, http.StatusBadRequest, paymentLink.Service, err)
+			}
+		}
+
+		if paymentLink.CancelUrl != "" && transaction.OperationState != models.TRANSACTION_SUCCESS {
+			return c.Redirect(http.StatusSeeOther, paymentLink.CancelUrl)
+		}
+
+		return c.Redirect(http.StatusSeeOther, checkoutPath(paymentLink.Code, transaction.ID))
+	}
+}
+
+// cancelTransaction annulation chez le provider, sans support de l'annulation la transaction
+// reste en attente de l'état final du provider
+func cancelTransaction(db *gorm.DB, transaction *models.TransactionModel) error {
+	if !transaction.Provider.SupportCancel() {
+		return nil
+	}
+
+	providerRes, err := utils.ProviderCancelTransaction(transaction.Provider, *transaction)
+	if err != nil {
+		return err
+	}
+
+	msg := providerRes.Message
+	if msg == "" {
+		msg = "paiement abandonné par le payeur"
+	}
+
+	if providerRes.Reference != "" {
+		transaction.ProviderReference = providerRes.Reference
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return transaction.SetState(tx, providerRes.State, msg)
+	})
+}
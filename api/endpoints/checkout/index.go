@@ -0,0 +1,95 @@
+package checkout
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"spay/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Libellés des états affichés au payeur
+var stateLabels = map[string]string{
+	models.TRANSACTION_PENDING: "Paiement en attente de confirmation",
+	models.TRANSACTION_SUCCESS: "Paiement réussi",
+	models.TRANSACTION_CANCEL:  "Paiement annulé",
+	models.TRANSACTION_FAIL:    "Paiement échoué",
+}
+
+var templates = template.Must(template.New("checkout").Funcs(template.FuncMap{
+	"amount": func(amount float64) string {
+		return fmt.Sprintf("%.2f", amount)
+	},
+	"stateLabel": func(state string) string {
+		if label, ok := stateLabels[state]; ok {
+			return label
+		}
+
+		return state
+	},
+}).ParseFS(templateFS, "templates/*.html"))
+
+type CheckoutRessource struct {
+	*models.PaymentLinkModel
+}
+
+// AttachCheckout page de paiement hébergée: /checkout/:code
+func AttachCheckout(server *echo.Echo) {
+	checkout := &CheckoutRessource{&models.PaymentLinkModel{}}
+
+	checkoutServer := server.Group("/checkout/:code", checkout.GetOnMid())
+	{
+		// Checkout Page
+		checkoutServer.GET("", checkout.Page())
+
+		// Pay
+		checkoutServer.POST("", checkout.Pay())
+
+		// Transaction Status Page
+		checkoutServer.GET("/:transaction_id", checkout.StatusPage())
+
+		// Transaction Status (polling)
+		checkoutServer.GET("/:transaction_id/status", checkout.Status())
+
+		// Cancel Transaction
+		checkoutServer.POST("/:transaction_id/cancel", checkout.Cancel())
+	}
+}
+
+// render génération d'une page html
+func render(c echo.Context, status int, name string, data interface{}) error {
+	buf := new(bytes.Buffer)
+	if err := templates.ExecuteTemplate(buf, name, data); err != nil {
+		return err
+	}
+
+	return c.HTMLBlob(status, buf.Bytes())
+}
+
+// renderError page d'erreur
+func renderError(c echo.Context, status int, service *models.ServiceModel, err error) error {
+	return render(c, status, "error.html", map[string]interface{}{
+		"Service": service,
+		"Error":   err.Error(),
+	})
+}
+
+// checkoutPath adresse relative d'une page de paiement
+func checkoutPath(code string, parts ...string) string {
+	path := "/checkout/" + code
+	for _, part := range parts {
+		path += "/" + part
+	}
+
+	return path
+}
+
+func notFound(c echo.Context) error {
+	return renderError(c, http.StatusNotFound, nil, fmt.Errorf("lien de paiement inexistant"))
+}
@@ -0,0 +1,111 @@
+package checkout
+
+import (
+	"fmt"
+	"net/http"
+	"spay/models"
+	"spay/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// GetOnMid chargement du lien de paiement à partir de son code public
+func (s *CheckoutRessource) GetOnMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			db, err := models.GetDB()
+			if err != nil {
+				log.Error().Err(err).Msgf("")
+				return renderError(c, http.StatusInternalServerError, nil, fmt.Errorf("service indisponible"))
+			}
+
+			paymentLink := models.PaymentLinkModel{}
+			result := db.
+				Preload("Service").
+				Where("code = ?", c.Param("code")).
+				First(&paymentLink)
+			if result.Error != nil {
+				if strings.Contains(result.Error.Error(), "record not found") {
+					return notFound(c)
+				}
+
+				log.Error().Err(result.Error).Msgf("")
+				return renderError(c, http.StatusInternalServerError, nil, fmt.Errorf("service indisponible"))
+			}
+
+			// Seules les redirections http(s) sont reprises dans la page et les redirections
+			paymentLink.SafeRedirects()
+
+			// Logo affiché par adresse signée
+			if err := utils.SignServiceLogo(paymentLink.Service); err != nil {
+				log.Error().Err(err).Msgf("")
+			}
+
+			c.Set("PAYMENT_LINK", &paymentLink)
+
+			return next(c)
+		}
+	}
+}
+
+// getLinkTransaction transaction du lien de paiement
+func getLinkTransaction(c echo.Context, paymentLink *models.PaymentLinkModel) (*models.TransactionModel, error) {
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := models.TransactionModel{}
+	result := db.
+		Preload("Provider").
+		Where("id = ? AND payment_link_id = ?", c.Param("transaction_id"), paymentLink.ID).
+		First(&transaction)
+	if result.Error != nil {
+		return nil, fmt.Errorf("transaction inexistante")
+	}
+
+	return &transaction, nil
+}
+
+// linkProviders providers disponibles pour le pays de la boutique
+func linkProviders(paymentLink *models.PaymentLinkModel) ([]models.ProviderModel, error) {
+	db, err := models.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []models.ProviderModel{}
+	result := db.Order("name asc").Find(&providers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if paymentLink.Service == nil || paymentLink.Service.Country == "" {
+		return providers, nil
+	}
+
+	supported := []models.ProviderModel{}
+	for _, provider := range providers {
+		for _, country := range provider.SupportCountry {
+			if strings.EqualFold(strings.TrimSpace(country), paymentLink.Service.Country) {
+				supported = append(supported, provider)
+				break
+			}
+		}
+	}
+
+	return supported, nil
+}
+
+// checkUsable le lien est utilisable pour un nouveau paiement
+func checkUsable(paymentLink *models.PaymentLinkModel) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	return paymentLink.CheckUsable(db, time.Now())
+}
@@ -13,6 +13,7 @@ require (
 	github.com/labstack/echo/v4 v4.10.2
 	github.com/rs/zerolog v1.29.1
 	github.com/satori/go.uuid v1.2.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/spf13/viper v1.16.0
 	github.com/swaggo/swag v1.16.1
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// VoidAuthorizations libération des autorisations expirées sans capture,
+// une libération refusée par le provider est retentée au passage suivant
+func VoidAuthorizations(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	transactions := []models.TransactionModel{}
+	result := db.
+		Preload("Provider").
+		Where("operation_state = ? AND captured_amount = ? AND authorization_expires_at < ?", models.TRANSACTION_AUTHORIZED, 0, now).
+		FindInBatches(&transactions, 100, func(tx *gorm.DB, batch int) error {
+			for i := range transactions {
+				err := utils.ProviderVoidTransaction(db, transactions[i].Provider, &transactions[i], "autorisation expirée")
+				if err != nil {
+					log.Error().Err(err).Str("transaction", transactions[i].ID).Msgf("")
+				}
+			}
+
+			return nil
+		})
+
+	return result.Error
+}
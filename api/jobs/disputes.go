@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ExpireDisputes les contestations dont la boutique n'a pas fourni de pièces
+// avant la date limite sont perdues
+func ExpireDisputes(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	disputes := []models.DisputeModel{}
+	result := db.
+		Where("state = ? AND evidence_due_at < ?", models.DISPUTE_EVIDENCE_REQUIRED, now).
+		FindInBatches(&disputes, 100, func(tx *gorm.DB, batch int) error {
+			for i := range disputes {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return disputes[i].Resolve(tx, models.DISPUTE_LOST, "pièces justificatives non fournies dans le délai", now)
+				})
+				if err != nil {
+					log.Error().Err(err).Str("dispute", disputes[i].ID).Msgf("")
+					continue
+				}
+
+				utils.NotifyDispute(db, disputes[i])
+			}
+
+			return nil
+		})
+
+	return result.Error
+}
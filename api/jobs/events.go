@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"spay/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeliverEvents livraison des événements en attente aux webhooks des boutiques
+func DeliverEvents(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	events := []models.EventModel{}
+	result := db.
+		Select("services_events.*").
+		Joins("JOIN services ON services.id = services_events.service_id AND services.webhook_url <> ''").
+		Where("services_events.delivered = ? AND services_events.attempts < ?", false, models.EVENT_MAX_ATTEMPTS).
+		Where("services_events.next_attempt_at IS NULL OR services_events.next_attempt_at <= ?", now).
+		Order("services_events.created_at asc").
+		Limit(100).
+		Find(&events)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	services := map[string]models.ServiceModel{}
+	for i := range events {
+		service, ok := services[events[i].ServiceId]
+		if !ok {
+			result := db.Where("id = ?", events[i].ServiceId).First(&service)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			services[service.ID] = service
+		}
+
+		if err := deliverEvent(db, service, &events[i], now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliverEvent envoi signé d'un événement, les échecs sont relancés avec un délai croissant
+func deliverEvent(db *gorm.DB, service models.ServiceModel, event *models.EventModel, now time.Time) error {
+	event.Attempts++
+
+	errPost := postEvent(service, *event)
+	if errPost == nil {
+		event.Delivered = true
+		event.LastError = ""
+		event.NextAttemptAt = nil
+	} else {
+		event.LastError = errPost.Error()
+		nextAttemptAt := now.Add(time.Minute * time.Duration(event.Attempts*event.Attempts))
+		event.NextAttemptAt = &nextAttemptAt
+	}
+
+	return db.Model(&models.EventModel{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"delivered":       event.Delivered,
+			"attempts":        event.Attempts,
+			"last_error":      event.LastError,
+			"next_attempt_at": event.NextAttemptAt,
+		}).Error
+}
+
+func postEvent(service models.ServiceModel, event models.EventModel) error {
+	ctx, ctxCancelFunc := context.WithTimeout(context.Background(), models.ConnectTimeout)
+	defer ctxCancelFunc()
+
+	body := []byte(fmt.Sprintf(`{"id":%q,"type":%q,"object_type":%q,"object_id":%q,"created_at":%q,"data":%v}`,
+		event.ID, event.Type, event.ObjectType, event.ObjectId, event.CreatedAt.Format(time.RFC3339), event.Payload))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, service.WebhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	// Signature HMAC-SHA256 du contenu avec la clé client de la boutique
+	mac := hmac.New(sha256.New, []byte(service.ClientKey))
+	mac.Write(body)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Spay-Event", event.Type)
+	req.Header.Set("X-Spay-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook en échec (%v)", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// DailyExport export des transactions et du relevé de la veille pour chaque boutique
+// dans EXPORT_DIR/<boutique>/, l'échec d'une boutique n'empêche pas l'export des suivantes
+func DailyExport(now time.Time) error {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	format := config.ExportFormat
+	if format == "" {
+		format = utils.EXPORT_CSV
+	}
+
+	exportTransactions, err := utils.TransactionExporter(config.ExportCurrency)
+	if err != nil {
+		return err
+	}
+
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -1)
+
+	var errs []error
+
+	services := []models.ServiceModel{}
+	result := db.Model(&models.ServiceModel{}).FindInBatches(&services, 100, func(tx *gorm.DB, batch int) error {
+		for _, service := range services {
+			dir := filepath.Join(config.ExportDir, service.NameSlug)
+
+			transactionsDb := db.Model(&models.TransactionModel{}).
+				Where("service_id = ? AND created_at >= ? AND created_at < ?", service.ID, start, end).
+				Order("created_at asc")
+
+			err := exportFile(db, transactionsDb, dir, fmt.Sprintf("transactions-%v.%v", start.Format("2006-01-02"), format), format, exportTransactions)
+			if err != nil {
+				log.Error().Err(err).Str("service_id", service.ID).Msg("export des transactions")
+				errs = append(errs, fmt.Errorf("boutique %v: %w", service.ID, err))
+			}
+
+			ledgerDb := db.Model(&models.LedgerEntryModel{}).
+				Where("service_id = ? AND created_at >= ? AND created_at < ?", service.ID, start, end).
+				Order("created_at asc")
+
+			err = exportFile(db, ledgerDb, dir, fmt.Sprintf("releve-%v.%v", start.Format("2006-01-02"), format), format, utils.ExportLedger)
+			if err != nil {
+				log.Error().Err(err).Str("service_id", service.ID).Msg("export du relevé")
+				errs = append(errs, fmt.Errorf("boutique %v: %w", service.ID, err))
+			}
+		}
+
+		return nil
+	})
+	if result.Error != nil {
+		errs = append(errs, result.Error)
+	}
+
+	return errors.Join(errs...)
+}
+
+// exportFile écriture d'un export dans un fichier, rien n'est écrit si la sélection est vide
+func exportFile(
+	db *gorm.DB,
+	reqDb *gorm.DB,
+	dir string,
+	fileName string,
+	format string,
+	export func(db *gorm.DB, reqDb *gorm.DB, writer utils.ExportWriter) (int64, error),
+) error {
+	var count int64
+	if result := reqDb.Session(&gorm.Session{}).Count(&count); result.Error != nil {
+		return result.Error
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmpName := filepath.Join(dir, "."+fileName)
+	file, err := os.Create(tmpName)
+	if err != nil {
+		return err
+	}
+
+	writer, err := utils.NewExportWriter(format, file, "export")
+	if err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	_, err = export(db, reqDb, writer)
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return os.Rename(tmpName, filepath.Join(dir, fileName))
+}
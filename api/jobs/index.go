@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"spay/models"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Start lancement des tâches planifiées
+func Start(log *zerolog.Logger) {
+	config, err := models.LoadConfig()
+	if err != nil {
+		log.Error().Err(err).Msg("tâches planifiées non démarrées")
+		return
+	}
+
+	// Exports journaliers
+	if config.ExportDir != "" {
+		go Daily(log, "daily-export", config.ExportHour, DailyExport)
+	}
+
+	// Prélèvements des abonnements
+	go Every(log, "subscriptions", time.Minute*5, BillSubscriptions)
+
+	// Retards des échéanciers
+	go Every(log, "installments", time.Hour, RefreshInstallments)
+
+	// Livraison des événements aux webhooks
+	go Every(log, "events", time.Minute, DeliverEvents)
+
+	// Libération des autorisations expirées
+	go Every(log, "authorizations", time.Minute*5, VoidAuthorizations)
+
+	// Contestations sans pièces justificatives à la date limite
+	go Every(log, "disputes", time.Hour, ExpireDisputes)
+
+	// Versement du solde des boutiques
+	go Every(log, "settlements", time.Hour, RunSettlements)
+
+	// Chargement des taux de change, dès le démarrage puis chaque heure
+	if config.FxRatesFile != "" || config.FxRatesUrl != "" {
+		go run(log, "exchange-rates", RefreshExchangeRates)
+		go Every(log, "exchange-rates", time.Hour, RefreshExchangeRates)
+	}
+}
+
+// Daily exécution quotidienne du job à l'heure indiquée
+func Daily(log *zerolog.Logger, name string, hour int, job func(now time.Time) error) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+
+		time.Sleep(time.Until(next))
+
+		run(log, name, job)
+	}
+}
+
+// Every exécution périodique du job
+func Every(log *zerolog.Logger, name string, interval time.Duration, job func(now time.Time) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		run(log, name, job)
+	}
+}
+
+func run(log *zerolog.Logger, name string, job func(now time.Time) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Str("job", name).Msgf("job interrompu: %v", r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job(start); err != nil {
+		log.Error().Err(err).Str("job", name).Msg("job en échec")
+		return
+	}
+
+	log.Info().Str("job", name).Str("elapsed", time.Since(start).String()).Msg("job terminé")
+}
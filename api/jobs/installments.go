@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"spay/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshInstallments passage en retard des échéanciers dont une échéance est dépassée
+func RefreshInstallments(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	plans := []models.InstallmentPlanModel{}
+	result := db.
+		Preload("Dues").
+		Where("status IN (?)", []string{models.INSTALLMENT_PENDING, models.INSTALLMENT_PARTIALLY_PAID}).
+		FindInBatches(&plans, 100, func(tx *gorm.DB, batch int) error {
+			for i := range plans {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return plans[i].RefreshStatus(tx, now)
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	return result.Error
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"errors"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RefreshExchangeRates chargement des taux depuis FX_RATES_FILE et FX_RATES_URL,
+// un taux déjà connu à la même date est remplacé
+func RefreshExchangeRates(now time.Time) error {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if config.FxRatesFile != "" {
+		count, err := utils.LoadRatesFile(db, config.FxRatesFile)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			log.Info().Int("rates", count).Str("file", config.FxRatesFile).Msg("taux de change chargés")
+		}
+	}
+
+	if config.FxRatesUrl != "" {
+		count, err := utils.FetchRatesAPI(db, config.FxRatesUrl)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			log.Info().Int("rates", count).Msg("taux de change récupérés")
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// RunSettlements versement du solde des boutiques arrivées à leur date de règlement,
+// un versement sous le minimum est reporté à la date suivante
+func RunSettlements(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	configs := []models.SettlementConfigModel{}
+	result := db.
+		Where("active = ? AND next_run_at <= ?", true, now).
+		FindInBatches(&configs, 100, func(tx *gorm.DB, batch int) error {
+			for i := range configs {
+				var settlement *models.SettlementModel
+
+				err := db.Transaction(func(tx *gorm.DB) error {
+					var err error
+					settlement, err = models.CreateSettlement(tx, configs[i], now)
+					if err != nil {
+						return err
+					}
+
+					nextRunAt := configs[i].NextRun(now)
+
+					return tx.Model(&models.SettlementConfigModel{}).
+						Where("id = ?", configs[i].ID).
+						Update("next_run_at", nextRunAt).Error
+				})
+				if err != nil {
+					log.Error().Err(err).Str("service", configs[i].ServiceId).Msgf("")
+					continue
+				}
+
+				if settlement != nil {
+					utils.NotifySettlement(db, *settlement)
+				}
+			}
+
+			return nil
+		})
+
+	return result.Error
+}
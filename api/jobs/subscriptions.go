@@ -0,0 +1,233 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"spay/models"
+	"spay/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// BillSubscriptions suivi des prélèvements en cours puis création des prélèvements échus,
+// l'échec d'un abonnement est journalisé et n'empêche pas le traitement des suivants
+func BillSubscriptions(now time.Time) error {
+	db, err := models.GetDB()
+	if err != nil {
+		return err
+	}
+
+	// Prélèvements en cours
+	pending := []models.SubscriptionModel{}
+	result := db.
+		Preload("Plan").
+		Where("pending_transaction_id <> ''").
+		Find(&pending)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var errs []error
+
+	for i := range pending {
+		if err := settleSubscription(db, &pending[i], now); err != nil {
+			log.Error().Err(err).Str("subscription_id", pending[i].ID).Msg("suivi du prélèvement")
+			errs = append(errs, fmt.Errorf("abonnement %v: %w", pending[i].ID, err))
+		}
+	}
+
+	// Echéances et relances
+	due := []models.SubscriptionModel{}
+	result = db.
+		Preload("Plan").
+		Preload("Provider").
+		Where("(pending_transaction_id = '' OR pending_transaction_id IS NULL)").
+		Where("(state = ? AND next_billing_at <= ?) OR (state = ? AND next_retry_at <= ?)", models.SUBSCRIPTION_ACTIVE, now, models.SUBSCRIPTION_PAST_DUE, now).
+		Find(&due)
+	if result.Error != nil {
+		return errors.Join(append(errs, result.Error)...)
+	}
+
+	for i := range due {
+		if err := chargeSubscription(db, &due[i], now); err != nil {
+			log.Error().Err(err).Str("subscription_id", due[i].ID).Msg("prélèvement de l'échéance")
+			errs = append(errs, fmt.Errorf("abonnement %v: %w", due[i].ID, err))
+
+			// Le prélèvement refusé (plafond, anti-fraude, boutique non approuvée) compte
+			// comme un échec de paiement et l'abonnement est relancé plus tard
+			if errFail := recordPayment(db, &due[i], nil, now); errFail != nil {
+				log.Error().Err(errFail).Str("subscription_id", due[i].ID).Msg("report du prélèvement")
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// chargeSubscription création du prélèvement de l'échéance, l'abonnement est réservé
+// par une mise à jour conditionnelle: une seule instance crée le prélèvement
+func chargeSubscription(db *gorm.DB, subscription *models.SubscriptionModel, now time.Time) error {
+	if subscription.Plan == nil || subscription.Provider == nil {
+		return fmt.Errorf("abonnement %v incomplet", subscription.ID)
+	}
+
+	referenceId := subscription.ReferenceId
+	if referenceId == "" {
+		referenceId = subscription.ID
+	}
+
+	transaction := models.TransactionModel{
+		ModeLive:       subscription.Plan.ModeLive,
+		Amount:         subscription.Plan.Amount,
+		AmountWithFee:  subscription.Plan.Amount,
+		OperationMode:  models.OPERATION_CREDIT,
+		OperationState: models.TRANSACTION_PENDING,
+		ProviderId:     subscription.ProviderId,
+		ServiceId:      subscription.ServiceId,
+		ReferenceId:    referenceId,
+		Currency:       subscription.Plan.Currency,
+		PayerPhone:     subscription.PayerPhone,
+		CustomerId:     subscription.CustomerId,
+		SubscriptionId: subscription.ID,
+	}
+
+	claimed := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Verrou de la ligne jusqu'à la fin de la transaction, un abonnement
+		// déjà pris par une autre instance n'est plus échu
+		result := tx.Model(&models.SubscriptionModel{}).
+			Where("id = ? AND (pending_transaction_id = '' OR pending_transaction_id IS NULL)", subscription.ID).
+			Where("(state = ? AND next_billing_at <= ?) OR (state = ? AND next_retry_at <= ?)", models.SUBSCRIPTION_ACTIVE, now, models.SUBSCRIPTION_PAST_DUE, now).
+			UpdateColumn("updated_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		claimed = true
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		subscription.PendingTransactionId = transaction.ID
+		subscription.LastTransactionId = transaction.ID
+
+		return tx.Model(&models.SubscriptionModel{}).
+			Where("id = ?", subscription.ID).
+			Updates(map[string]interface{}{
+				"pending_transaction_id": subscription.PendingTransactionId,
+				"last_transaction_id":    subscription.LastTransactionId,
+			}).Error
+	})
+	if err != nil {
+		// Création annulée, l'abonnement n'a pas de prélèvement en cours
+		subscription.PendingTransactionId = ""
+		return err
+	}
+
+	if !claimed {
+		return nil
+	}
+
+	// Un refus du provider est traité comme un échec au prochain passage
+	if err := utils.ProviderStartTransaction(db, *subscription.Provider, &transaction); err != nil {
+		log.Error().Err(err).Str("subscription_id", subscription.ID).Str("transaction_id", transaction.ID).Msg("envoi du prélèvement")
+	}
+
+	return nil
+}
+
+// settleSubscription application du résultat d'un prélèvement: échéance suivante ou relance
+func settleSubscription(db *gorm.DB, subscription *models.SubscriptionModel, now time.Time) error {
+	transaction := models.TransactionModel{}
+	result := db.
+		Preload("Provider").
+		Where("id = ?", subscription.PendingTransactionId).
+		First(&transaction)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if !transaction.IsFinal() {
+		// Provider indisponible, nouvelle vérification au prochain passage
+		if err := utils.ProviderSyncTransaction(db, &transaction); err != nil || !transaction.IsFinal() {
+			return nil
+		}
+	}
+
+	return recordPayment(db, subscription, &transaction, now)
+}
+
+// recordPayment application du résultat d'un prélèvement, transaction nil si le prélèvement
+// n'a pas pu être créé. Seul le prélèvement encore en cours sur l'abonnement est appliqué
+func recordPayment(db *gorm.DB, subscription *models.SubscriptionModel, transaction *models.TransactionModel, now time.Time) error {
+	if subscription.Plan == nil {
+		return fmt.Errorf("abonnement %v incomplet", subscription.ID)
+	}
+
+	eventType := models.EVENT_SUBSCRIPTION_PAYMENT_SUCCEEDED
+
+	pendingTransactionId := subscription.PendingTransactionId
+	subscription.PendingTransactionId = ""
+	if transaction != nil && transaction.OperationState == models.TRANSACTION_SUCCESS {
+		subscription.RetryCount = 0
+		subscription.NextRetryAt = nil
+		subscription.NextBillingAt = subscription.Plan.NextDate(subscription.NextBillingAt)
+		if subscription.State == models.SUBSCRIPTION_PAST_DUE {
+			subscription.State = models.SUBSCRIPTION_ACTIVE
+		}
+	} else {
+		subscription.RetryCount++
+
+		// Un abonnement suspendu ou résilié entre temps garde son état
+		billing := subscription.State == models.SUBSCRIPTION_ACTIVE || subscription.State == models.SUBSCRIPTION_PAST_DUE
+
+		if subscription.RetryCount > subscription.Plan.MaxRetries {
+			eventType = models.EVENT_SUBSCRIPTION_UNPAID
+			subscription.NextRetryAt = nil
+			if billing {
+				subscription.State = models.SUBSCRIPTION_UNPAID
+			}
+		} else {
+			eventType = models.EVENT_SUBSCRIPTION_PAYMENT_FAILED
+			nextRetryAt := now.Add(subscription.Plan.RetryDelay())
+			subscription.NextRetryAt = &nextRetryAt
+			if billing {
+				subscription.State = models.SUBSCRIPTION_PAST_DUE
+			}
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		reqDb := tx.Model(&models.SubscriptionModel{}).Where("id = ?", subscription.ID)
+		if pendingTransactionId != "" {
+			reqDb = reqDb.Where("pending_transaction_id = ?", pendingTransactionId)
+		} else {
+			reqDb = reqDb.Where("(pending_transaction_id = '' OR pending_transaction_id IS NULL)")
+		}
+
+		result := reqDb.
+			Updates(map[string]interface{}{
+				"pending_transaction_id": subscription.PendingTransactionId,
+				"retry_count":            subscription.RetryCount,
+				"next_retry_at":          subscription.NextRetryAt,
+				"next_billing_at":        subscription.NextBillingAt,
+				"state":                  subscription.State,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Déjà appliqué par une autre instance
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return models.PublishEvent(tx, subscription.ServiceId, eventType, "subscription", subscription.ID, map[string]interface{}{
+			"subscription": subscription,
+			"transaction":  transaction,
+		})
+	})
+}
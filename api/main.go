@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"spay/endpoints/api"
+	"spay/endpoints/checkout"
+	"spay/jobs"
 	"spay/models"
 	"spay/utils"
 	"syscall"
@@ -50,6 +52,9 @@ func main() {
 	// bind OS events to the signal channel
 	signal.Notify(stopChan, syscall.SIGTERM, syscall.SIGINT)
 
+	// Tâches planifiées
+	jobs.Start(&npLog)
+
 	// HTTP
 	go func(port string) {
 		config, err := models.LoadConfig()
@@ -73,6 +78,7 @@ func main() {
 		server.Use(middleware.Recover())
 		server.Use(middleware.CORS())
 		server.Use(middleware.Logger())
+		server.Use(middleware.RequestID())
 		// server.Pre(middleware.AddTrailingSlash())
 
 		server.Validator = models.NewCustomValidator()
@@ -83,6 +89,9 @@ func main() {
 
 		api.AttachAPI(server, &npLog)
 
+		// Page de paiement hébergée
+		checkout.AttachCheckout(server)
+
 		npLog.Info().Msgf("Create HTTP server in port%v", port)
 		go func() {
 			if err := server.Start(port); err != nil {
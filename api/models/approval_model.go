@@ -0,0 +1,469 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Opérations soumises à approbation selon leur politique
+const (
+	APPROVAL_SETTLEMENT_PAYOUT  = "settlement.payout"         // Emission ou confirmation d'un versement
+	APPROVAL_TRANSFER           = "transfer.create"           // Virement interne entre boutiques
+	APPROVAL_USER_ROLE          = "user.change_role"          // Changement du rôle d'un utilisateur
+	APPROVAL_SERVICE_CLIENT     = "service.regenerate_client" // Régénération de la clé client d'une boutique
+	APPROVAL_REFUND             = "dispute.refund"            // Rétrocession au payeur d'une contestation perdue
+	APPROVAL_TRANSACTION_REFUND = "transaction.refund"        // Remboursement d'un paiement à l'initiative de la boutique
+)
+
+// Etats d'une demande d'approbation
+const (
+	APPROVAL_PENDING  = "PENDING"
+	APPROVAL_APPROVED = "APPROVED" // Approuvée et exécutée
+	APPROVAL_REJECTED = "REJECTED"
+	APPROVAL_CANCELED = "CANCELED" // Retirée par son initiateur
+	APPROVAL_FAILED   = "FAILED"   // Approuvée mais l'exécution a échoué
+)
+
+// Evénements des demandes d'approbation portant sur une boutique
+const (
+	EVENT_APPROVAL_REQUESTED = "approval.requested"
+	EVENT_APPROVAL_APPROVED  = "approval.approved"
+	EVENT_APPROVAL_REJECTED  = "approval.rejected"
+	EVENT_APPROVAL_FAILED    = "approval.failed"
+)
+
+// ApprovalExecutor exécution de l'opération approuvée dans la transaction de l'approbation,
+// la modification est journalisée au nom de l'approbateur
+type ApprovalExecutor func(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error)
+
+var approvalExecutors = map[string]ApprovalExecutor{
+	APPROVAL_SETTLEMENT_PAYOUT:  executeSettlementPayout,
+	APPROVAL_TRANSFER:           executeTransfer,
+	APPROVAL_USER_ROLE:          executeUserRole,
+	APPROVAL_SERVICE_CLIENT:     executeServiceClient,
+	APPROVAL_REFUND:             executeRefund,
+	APPROVAL_TRANSACTION_REFUND: executeTransactionRefund,
+}
+
+// IsApprovalOperation opération prise en charge par les approbations
+func IsApprovalOperation(operation string) bool {
+	_, ok := approvalExecutors[operation]
+	return ok
+}
+
+// ApprovalPolicyModel politique d'approbation d'une opération,
+// sans politique active l'opération est exécutée immédiatement
+type ApprovalPolicyModel struct {
+	Model
+
+	Operation    string  `json:"operation" form:"operation" validate:"required" gorm:"uniqueIndex"`
+	Active       bool    `json:"active" form:"active" validate:"-"`
+	MinAmount    float64 `json:"min_amount" form:"min_amount" validate:"gte=0"`                                                      // Approbation à partir de ce montant, 0: toujours
+	ApproverRole string  `json:"approver_role" form:"approver_role" validate:"required,oneof=USER_MANAGER USER_ADMIN SERVICE_ADMIN"` // SERVICE_ADMIN: administrateur de la boutique concernée
+	Description  string  `json:"description,omitempty" form:"description" validate:"omitempty"`
+}
+
+// TableName changement du nom de la table
+func (ApprovalPolicyModel) TableName() string {
+	return "approvals_policies"
+}
+
+func (p *ApprovalPolicyModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	p.ID = uuid.String()
+
+	return
+}
+
+// ApprovalRequestModel demande d'exécution d'une opération sensible en attente d'un second utilisateur
+type ApprovalRequestModel struct {
+	Model
+
+	Operation string  `json:"operation" form:"-" validate:"-" gorm:"index"`
+	ServiceId string  `json:"service_id,omitempty" form:"-" validate:"-" gorm:"index"` // Boutique concernée
+	TargetId  string  `json:"target_id,omitempty" form:"-" validate:"-" gorm:"index"`  // Objet de l'opération
+	Amount    float64 `json:"amount,omitempty" form:"-" validate:"-"`
+	Currency  string  `json:"currency,omitempty" form:"-" validate:"-"`
+	Payload   string  `json:"payload" form:"-" validate:"-"` // Paramètres de l'opération au format json
+
+	State        string     `json:"state" form:"-" validate:"-" gorm:"index"`
+	ApproverRole string     `json:"approver_role" form:"-" validate:"-"` // Repris de la politique à la demande
+	RequestedBy  string     `json:"requested_by" form:"-" validate:"-" gorm:"index"`
+	DecidedBy    string     `json:"decided_by,omitempty" form:"-" validate:"-"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty" form:"-" validate:"-"`
+	Reason       string     `json:"reason,omitempty" form:"-" validate:"-"` // Motif du rejet
+	Result       string     `json:"result,omitempty" form:"-" validate:"-"` // Objet produit par l'exécution au format json
+	Error        string     `json:"error,omitempty" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (ApprovalRequestModel) TableName() string {
+	return "approvals_requests"
+}
+
+func (r *ApprovalRequestModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	if r.State == "" {
+		r.State = APPROVAL_PENDING
+	}
+
+	return
+}
+
+// RequestApproval enregistrement d'une demande si la politique de l'opération l'exige,
+// renvoie nil lorsque l'opération peut être exécutée immédiatement
+func RequestApproval(tx *gorm.DB, actor AuditActor, request ApprovalRequestModel, payload interface{}) (*ApprovalRequestModel, error) {
+	policy := ApprovalPolicyModel{}
+	result := tx.Where("operation = ? AND active = ?", request.Operation, true).Limit(1).Find(&policy)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 || (policy.MinAmount > 0 && request.Amount < policy.MinAmount) {
+		return nil, nil
+	}
+
+	// Une seule demande en attente par opération et par objet
+	if request.TargetId != "" {
+		var count int64
+		result := tx.Model(&ApprovalRequestModel{}).
+			Where("operation = ? AND target_id = ? AND state = ?", request.Operation, request.TargetId, APPROVAL_PENDING).
+			Count(&count)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		if count > 0 {
+			return nil, fmt.Errorf("une demande d'approbation est déjà en attente pour cette opération")
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Payload = string(data)
+	request.ApproverRole = policy.ApproverRole
+	request.State = APPROVAL_PENDING
+
+	if err := tx.Create(&request).Error; err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_APPROVAL_REQUEST, "approval", request.ID, nil, request); err != nil {
+		return nil, err
+	}
+
+	return &request, request.publish(tx, EVENT_APPROVAL_REQUESTED)
+}
+
+// CanDecide contrôle de l'approbateur: second utilisateur disposant du rôle de la politique
+func (r *ApprovalRequestModel) CanDecide(user UserModel) error {
+	if user.ID == r.RequestedBy {
+		return fmt.Errorf("une demande ne peut être approuvée ou rejetée par son initiateur")
+	}
+
+	allowed := false
+	switch r.ApproverRole {
+	case USER_ADMIN.String():
+		allowed = user.IsGrant(USER_ADMIN)
+
+	case SERVICE_ADMIN.String():
+		allowed = user.Can(PERM_APPROVALS_DECIDE, r.ServiceId)
+
+	default:
+		allowed = user.Can(PERM_PLATFORM_APPROVALS, "")
+	}
+
+	if !allowed {
+		return fmt.Errorf("permission non accordé, rôle %v requis", r.ApproverRole)
+	}
+
+	return nil
+}
+
+// Approve approbation puis exécution de l'opération, une erreur d'exécution annule la transaction
+// et doit être enregistrée ensuite avec Fail
+func (r *ApprovalRequestModel) Approve(tx *gorm.DB, actor AuditActor, user UserModel, now time.Time) (interface{}, error) {
+	if err := r.CanDecide(user); err != nil {
+		return nil, err
+	}
+
+	executor, ok := approvalExecutors[r.Operation]
+	if !ok {
+		return nil, fmt.Errorf("opération inconnue %v", r.Operation)
+	}
+
+	if err := r.decide(tx, APPROVAL_APPROVED, user.ID, "", now); err != nil {
+		return nil, err
+	}
+
+	object, err := executor(tx, actor, r, now)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Result = string(data)
+
+	result := tx.Model(&ApprovalRequestModel{}).Where("id = ?", r.ID).Update("result", r.Result)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return object, r.publish(tx, EVENT_APPROVAL_APPROVED)
+}
+
+// Fail enregistrement de l'échec de l'exécution d'une demande approuvée
+func (r *ApprovalRequestModel) Fail(tx *gorm.DB, user UserModel, cause error, now time.Time) error {
+	if err := r.decide(tx, APPROVAL_FAILED, user.ID, "", now); err != nil {
+		return err
+	}
+
+	r.Error = cause.Error()
+
+	result := tx.Model(&ApprovalRequestModel{}).Where("id = ?", r.ID).Update("error", r.Error)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return r.publish(tx, EVENT_APPROVAL_FAILED)
+}
+
+// Reject rejet de la demande, l'opération n'est pas exécutée
+func (r *ApprovalRequestModel) Reject(tx *gorm.DB, user UserModel, reason string, now time.Time) error {
+	if err := r.CanDecide(user); err != nil {
+		return err
+	}
+
+	if err := r.decide(tx, APPROVAL_REJECTED, user.ID, reason, now); err != nil {
+		return err
+	}
+
+	return r.publish(tx, EVENT_APPROVAL_REJECTED)
+}
+
+// Cancel retrait de la demande par son initiateur
+func (r *ApprovalRequestModel) Cancel(tx *gorm.DB, user UserModel, now time.Time) error {
+	if user.ID != r.RequestedBy {
+		return fmt.Errorf("seul l'initiateur peut retirer sa demande")
+	}
+
+	return r.decide(tx, APPROVAL_CANCELED, user.ID, "", now)
+}
+
+// decide changement d'état conditionnel, une demande n'est traitée qu'une fois
+func (r *ApprovalRequestModel) decide(tx *gorm.DB, state string, userId string, reason string, now time.Time) error {
+	result := tx.Model(&ApprovalRequestModel{}).
+		Where("id = ? AND state = ?", r.ID, APPROVAL_PENDING).
+		Updates(map[string]interface{}{
+			"state":      state,
+			"decided_by": userId,
+			"decided_at": now,
+			"reason":     reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("demande déjà traitée")
+	}
+
+	r.State = state
+	r.DecidedBy = userId
+	r.DecidedAt = &now
+	r.Reason = reason
+
+	return nil
+}
+
+// publish événement de la boutique concernée
+func (r *ApprovalRequestModel) publish(tx *gorm.DB, eventType string) error {
+	if r.ServiceId == "" {
+		return nil
+	}
+
+	return PublishEvent(tx, r.ServiceId, eventType, "approval", r.ID, r)
+}
+
+// Decode lecture des paramètres de l'opération
+func (r *ApprovalRequestModel) Decode(payload interface{}) error {
+	return json.Unmarshal([]byte(r.Payload), payload)
+}
+
+// SettlementPayout paramètres d'un changement d'état de versement soumis à approbation
+type SettlementPayout struct {
+	State     string `json:"state"`
+	Reference string `json:"reference"`
+}
+
+func executeSettlementPayout(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	payout := SettlementPayout{}
+	if err := request.Decode(&payout); err != nil {
+		return nil, err
+	}
+
+	settlement := SettlementModel{}
+	result := tx.Where("id = ?", request.TargetId).First(&settlement)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	before := settlement
+	if err := settlement.SetState(tx, payout.State, payout.Reference, "", now); err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_SETTLEMENT_STATE, "settlement", settlement.ID, before, settlement); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func executeTransfer(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	transfer := TransferModel{}
+	if err := request.Decode(&transfer); err != nil {
+		return nil, err
+	}
+
+	transfer.ApprovalId = request.ID
+
+	if err := CreateTransfer(tx, &transfer, now); err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_SERVICE_TRANSFER, "transfer", transfer.ID, nil, transfer); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// UserRoleChange paramètres d'un changement de rôle soumis à approbation
+type UserRoleChange struct {
+	Role int `json:"role"`
+}
+
+func executeUserRole(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	change := UserRoleChange{}
+	if err := request.Decode(&change); err != nil {
+		return nil, err
+	}
+
+	user := UserModel{}
+	result := tx.Where("auth_id = ?", request.TargetId).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	before := user
+	user.Role = UserRole(change.Role)
+
+	if err := tx.Save(&user).Error; err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_USER_CHANGE_ROLE, "user", user.ID, before, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func executeServiceClient(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	service := ServiceModel{}
+	result := tx.Where("id = ?", request.TargetId).First(&service)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	before := service
+	service.GenerateClient()
+
+	// Seules les clés sont écrites, le solde est tenu par le relevé
+	result = tx.Model(&ServiceModel{}).Where("id = ?", service.ID).Updates(map[string]interface{}{
+		"client_id":  service.ClientId,
+		"client_key": service.ClientKey,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := Audit(tx, actor, AUDIT_SERVICE_REGENERATE, "service", service.ID, before, service); err != nil {
+		return nil, err
+	}
+
+	// La nouvelle clé est consultée par la boutique, elle n'est pas reprise dans la demande
+	return map[string]string{"service_id": service.ID, "client_id": service.ClientId}, nil
+}
+
+// DisputeResolution paramètres d'une résolution de contestation soumise à approbation
+type DisputeResolution struct {
+	State      string `json:"state"`
+	Resolution string `json:"resolution"`
+}
+
+func executeRefund(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	resolution := DisputeResolution{}
+	if err := request.Decode(&resolution); err != nil {
+		return nil, err
+	}
+
+	dispute := DisputeModel{}
+	result := tx.Where("id = ?", request.TargetId).First(&dispute)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	before := dispute
+	if err := dispute.Resolve(tx, resolution.State, resolution.Resolution, now); err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_DISPUTE_RESOLVE, "dispute", dispute.ID, before, dispute); err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func executeTransactionRefund(tx *gorm.DB, actor AuditActor, request *ApprovalRequestModel, now time.Time) (interface{}, error) {
+	refund := RefundModel{}
+	if err := request.Decode(&refund); err != nil {
+		return nil, err
+	}
+
+	refund.TransactionId = request.TargetId
+	refund.ApprovalId = request.ID
+
+	if err := RefundTransaction(tx, &refund); err != nil {
+		return nil, err
+	}
+
+	if err := Audit(tx, actor, AUDIT_TRANSACTION_REFUND, "refund", refund.ID, nil, refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
@@ -0,0 +1,281 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Actions journalisées
+const (
+	AUDIT_PROVIDER_CREATE        = "provider.create"
+	AUDIT_USER_CHANGE_ROLE       = "user.change_role"
+	AUDIT_SERVICE_REGENERATE     = "service.regenerate_client"
+	AUDIT_SERVICE_PERMISSION_ADD = "service.permission_add"
+	AUDIT_SERVICE_PERMISSION_SET = "service.permission_update"
+	AUDIT_SERVICE_PERMISSION_DEL = "service.permission_remove"
+	AUDIT_SERVICE_LEAVE          = "service.leave"
+	AUDIT_SERVICE_INVITE         = "service.invite"
+	AUDIT_SERVICE_INVITE_REVOKE  = "service.invite_revoke"
+	AUDIT_SERVICE_ROLE_ADD       = "service.role_add"
+	AUDIT_SERVICE_ROLE_SET       = "service.role_update"
+	AUDIT_SERVICE_ROLE_DEL       = "service.role_remove"
+	AUDIT_SERVICE_TRANSFER       = "service.transfer"
+	AUDIT_SERVICE_ONBOARDING     = "service.onboarding"
+	AUDIT_SETTLEMENT_STATE       = "settlement.state"
+	AUDIT_DISPUTE_RESOLVE        = "dispute.resolve"
+	AUDIT_TRANSACTION_REFUND     = "transaction.refund"
+	AUDIT_APPROVAL_REQUEST       = "approval.request"
+	AUDIT_APPROVAL_APPROVE       = "approval.approve"
+	AUDIT_APPROVAL_REJECT        = "approval.reject"
+	AUDIT_APPROVAL_CANCEL        = "approval.cancel"
+	AUDIT_APPROVAL_POLICY_SET    = "approval.policy_set"
+)
+
+// Identifiant de la tête de chaîne du journal
+const AUDIT_HEAD = "audit"
+
+// Champs masqués dans le journal
+var auditRedacted = []string{"client_key", "password", "secret", "token"}
+
+// AuditActor auteur d'une action, repris de la requête
+type AuditActor struct {
+	ActorId   string // sub du JWT
+	Ip        string
+	RequestId string
+}
+
+// AuditLogModel entrée du journal d'audit, chaque entrée est chaînée à la précédente
+// par son empreinte: toute modification ou suppression casse la chaîne
+type AuditLogModel struct {
+	Model
+
+	Sequence int64 `json:"sequence" gorm:"uniqueIndex"`
+
+	ActorId    string `json:"actor_id" gorm:"index"`
+	Action     string `json:"action" gorm:"index"`
+	EntityType string `json:"entity_type" gorm:"index"`
+	EntityId   string `json:"entity_id" gorm:"index"`
+	Before     string `json:"before,omitempty"` // Etat avant l'action au format json
+	After      string `json:"after,omitempty"`  // Etat après l'action au format json
+	Diff       string `json:"diff,omitempty"`   // Champs modifiés {"champ": [avant, après]}
+	Ip         string `json:"ip,omitempty"`
+	RequestId  string `json:"request_id,omitempty" gorm:"index"`
+
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// TableName changement du nom de la table
+func (AuditLogModel) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeUpdate le journal est en ajout seul
+func (a *AuditLogModel) BeforeUpdate(tx *gorm.DB) error {
+	return fmt.Errorf("journal d'audit non modifiable")
+}
+
+// BeforeDelete le journal est en ajout seul
+func (a *AuditLogModel) BeforeDelete(tx *gorm.DB) error {
+	return fmt.Errorf("journal d'audit non modifiable")
+}
+
+// ComputeHash empreinte de l'entrée, calculée sur son contenu et l'empreinte précédente
+func (a *AuditLogModel) ComputeHash() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		a.PrevHash,
+		fmt.Sprint(a.Sequence),
+		a.CreatedAt.UTC().Format(time.RFC3339Nano),
+		a.ActorId,
+		a.Action,
+		a.EntityType,
+		a.EntityId,
+		a.Before,
+		a.After,
+		a.Ip,
+		a.RequestId,
+	}, "|")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditHeadModel dernière entrée du journal, sa mise à jour sérialise les écritures
+type AuditHeadModel struct {
+	ID       string `json:"id" gorm:"primary_key"`
+	Sequence int64  `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// TableName changement du nom de la table
+func (AuditHeadModel) TableName() string {
+	return "audit_head"
+}
+
+// Audit enregistrement d'une action dans la transaction de la modification,
+// before ou after peuvent être nil pour une création ou une suppression
+func Audit(tx *gorm.DB, actor AuditActor, action string, entityType string, entityId string, before interface{}, after interface{}) error {
+	beforeMap, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+
+	afterMap, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	entry := AuditLogModel{
+		ActorId:    actor.ActorId,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Before:     auditJson(beforeMap),
+		After:      auditJson(afterMap),
+		Diff:       auditJson(auditDiff(beforeMap, afterMap)),
+		Ip:         actor.Ip,
+		RequestId:  actor.RequestId,
+	}
+
+	// Verrou de la tête de chaîne jusqu'à la fin de la transaction
+	result := tx.Model(&AuditHeadModel{}).
+		Where("id = ?", AUDIT_HEAD).
+		Update("sequence", gorm.Expr("sequence + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	head := AuditHeadModel{ID: AUDIT_HEAD, Sequence: 1}
+	if result.RowsAffected == 0 {
+		if err := tx.Create(&head).Error; err != nil {
+			return err
+		}
+	} else if err := tx.Where("id = ?", AUDIT_HEAD).First(&head).Error; err != nil {
+		return err
+	}
+
+	entry.Sequence = head.Sequence
+	entry.PrevHash = head.Hash
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Millisecond) // Précision commune aux bases supportées
+	entry.UpdatedAt = entry.CreatedAt
+	entry.Hash = entry.ComputeHash()
+
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&AuditHeadModel{}).Where("id = ?", AUDIT_HEAD).Update("hash", entry.Hash).Error
+}
+
+// VerifyAudit contrôle de la chaîne, renvoie le nombre d'entrées contrôlées
+// et la séquence de la première entrée altérée (0 si la chaîne est intacte)
+func VerifyAudit(tx *gorm.DB) (int64, int64, error) {
+	var checked int64
+	prevHash := ""
+
+	for {
+		entries := []AuditLogModel{}
+		result := tx.Where("sequence > ?", checked).Order("sequence asc").Limit(500).Find(&entries)
+		if result.Error != nil {
+			return checked, 0, result.Error
+		}
+
+		if len(entries) == 0 {
+			break
+		}
+
+		for _, entry := range entries {
+			if entry.Sequence != checked+1 || entry.PrevHash != prevHash || entry.ComputeHash() != entry.Hash {
+				return checked, checked + 1, nil
+			}
+
+			checked++
+			prevHash = entry.Hash
+		}
+	}
+
+	// Entrées supprimées en fin de journal
+	head := AuditHeadModel{}
+	if err := tx.Where("id = ?", AUDIT_HEAD).Limit(1).Find(&head).Error; err != nil {
+		return checked, 0, err
+	}
+
+	if head.Sequence != checked || head.Hash != prevHash {
+		return checked, checked + 1, nil
+	}
+
+	return checked, 0, nil
+}
+
+// auditSnapshot conversion en map json avec masquage des champs sensibles
+func auditSnapshot(object interface{}) (map[string]interface{}, error) {
+	if object == nil || (reflect.ValueOf(object).Kind() == reflect.Ptr && reflect.ValueOf(object).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]interface{}{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	for key := range snapshot {
+		for _, redacted := range auditRedacted {
+			if strings.Contains(strings.ToLower(key), redacted) {
+				snapshot[key] = "***"
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// auditDiff champs dont la valeur a changé
+func auditDiff(before map[string]interface{}, after map[string]interface{}) map[string]interface{} {
+	diff := map[string]interface{}{}
+
+	for key, value := range after {
+		if key == "updated_at" {
+			continue
+		}
+
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			diff[key] = []interface{}{before[key], value}
+		}
+	}
+
+	for key, value := range before {
+		if _, ok := after[key]; !ok && after != nil {
+			diff[key] = []interface{}{value, nil}
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+
+	return diff
+}
+
+func auditJson(object map[string]interface{}) string {
+	if object == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -8,7 +8,8 @@ import (
 
 // Config strcuture de configuration
 type Config struct {
-	PortAPI string `mapstructure:"PORT_API"`
+	PortAPI   string `mapstructure:"PORT_API"`
+	PublicUrl string `mapstructure:"PUBLIC_URL"` // Adresse publique de l'api (page de paiement)
 
 	// Keycloak client data
 	KeyCloakHost         string `mapstructure:"KEYCLOAK_HOST"`
@@ -34,7 +35,30 @@ type Config struct {
 	SmtpPass     string `mapstructure:"SMTP_PASS"`
 
 	// Uploads
-	UploadDir string `mapstructure:"UPLOAD_DIR"`
+	UploadDir      string `mapstructure:"UPLOAD_DIR"`
+	StorageDriver  string `mapstructure:"STORAGE_DRIVER"`   // local (UPLOAD_DIR), s3 à venir
+	StorageSignKey string `mapstructure:"STORAGE_SIGN_KEY"` // Clé de signature des adresses de téléchargement
+	StorageUrlTtl  string `mapstructure:"STORAGE_URL_TTL"`  // Validité d'une adresse signée (ex: 15m)
+
+	// Invitations aux boutiques
+	InvitationTtl string `mapstructure:"INVITATION_TTL"` // Validité d'une invitation (ex: 168h)
+	InvitationUrl string `mapstructure:"INVITATION_URL"` // Page d'acceptation, le jeton est ajouté en paramètre token
+
+	// Délai de dépôt des pièces d'une contestation, en jours
+	DisputeEvidenceDays int `mapstructure:"DISPUTE_EVIDENCE_DAYS"`
+
+	// Exports journaliers (désactivés si vide)
+	ExportDir    string `mapstructure:"EXPORT_DIR"`
+	ExportFormat string `mapstructure:"EXPORT_FORMAT"` // csv, xlsx
+	ExportHour     int    `mapstructure:"EXPORT_HOUR"`
+	ExportCurrency string `mapstructure:"EXPORT_CURRENCY"` // transaction, settlement
+
+	// Durée de validité d'une autorisation avant libération automatique (ex: 168h)
+	AuthorizationExpiry string `mapstructure:"AUTHORIZATION_EXPIRY"`
+
+	// Taux de change, rechargés chaque heure (désactivés si vide)
+	FxRatesFile string `mapstructure:"FX_RATES_FILE"` // Fichier csv ou json
+	FxRatesUrl  string `mapstructure:"FX_RATES_URL"`  // Api renvoyant {"base","date","rates"}
 }
 
 // LoadConfig load config
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Origine d'un taux de change
+const (
+	RATE_SOURCE_MANUAL = "MANUAL"
+	RATE_SOURCE_FILE   = "FILE"
+	RATE_SOURCE_API    = "API"
+)
+
+// Validation des codes ISO-4217
+var currencyValidate = validator.New()
+
+// ExchangeRateModel taux de change: 1 BaseCurrency = Rate QuoteCurrency à partir de EffectiveAt
+type ExchangeRateModel struct {
+	Model
+
+	BaseCurrency  string    `json:"base_currency" form:"base_currency" validate:"required,currency" gorm:"uniqueIndex:idx_exchange_rate"`
+	QuoteCurrency string    `json:"quote_currency" form:"quote_currency" validate:"required,currency" gorm:"uniqueIndex:idx_exchange_rate"`
+	Rate          float64   `json:"rate" form:"rate" validate:"required,gt=0"`
+	EffectiveAt   time.Time `json:"effective_at" form:"effective_at" validate:"-" gorm:"uniqueIndex:idx_exchange_rate"`
+	Source        string    `json:"source" form:"-" validate:"-"` // MANUAL, FILE, API
+}
+
+// TableName changement du nom de la table
+func (ExchangeRateModel) TableName() string {
+	return "exchange_rates"
+}
+
+func (r *ExchangeRateModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	return
+}
+
+// IsCurrency code devise ISO-4217 (ex: XOF, EUR)
+func IsCurrency(code string) bool {
+	return currencyValidate.Var(strings.ToUpper(code), "iso4217") == nil
+}
+
+// NormalizeCurrency code devise en majuscule, erreur si le code n'est pas ISO-4217
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !IsCurrency(code) {
+		return "", fmt.Errorf("devise invalide %v, code ISO-4217 attendu", code)
+	}
+
+	return code, nil
+}
+
+// SaveExchangeRates enregistrement des taux, un taux existant à la même date est remplacé
+func SaveExchangeRates(tx *gorm.DB, rates []ExchangeRateModel) error {
+	for i := range rates {
+		base, err := NormalizeCurrency(rates[i].BaseCurrency)
+		if err != nil {
+			return err
+		}
+
+		quote, err := NormalizeCurrency(rates[i].QuoteCurrency)
+		if err != nil {
+			return err
+		}
+
+		if base == quote {
+			return fmt.Errorf("taux %v/%v invalide, devises identiques", base, quote)
+		}
+
+		if rates[i].Rate <= 0 || math.IsInf(rates[i].Rate, 0) || math.IsNaN(rates[i].Rate) {
+			return fmt.Errorf("taux %v/%v invalide %v", base, quote, rates[i].Rate)
+		}
+
+		rates[i].BaseCurrency = base
+		rates[i].QuoteCurrency = quote
+
+		if rates[i].EffectiveAt.IsZero() {
+			rates[i].EffectiveAt = time.Now()
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).CreateInBatches(rates, 100).Error
+}
+
+// FindExchangeRate dernier taux connu à la date indiquée pour convertir from vers to,
+// le taux inverse est utilisé si seule la paire opposée existe
+func FindExchangeRate(tx *gorm.DB, from string, to string, at time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	rate := ExchangeRateModel{}
+	result := tx.
+		Where("base_currency = ? AND quote_currency = ? AND effective_at <= ?", from, to, at).
+		Order("effective_at desc").
+		Limit(1).
+		Find(&rate)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return rate.Rate, nil
+	}
+
+	result = tx.
+		Where("base_currency = ? AND quote_currency = ? AND effective_at <= ?", to, from, at).
+		Order("effective_at desc").
+		Limit(1).
+		Find(&rate)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return 1 / rate.Rate, nil
+	}
+
+	return 0, ResErrorAPI{ErrorAPI{
+		Code:    "EXCHANGE_RATE_NOT_FOUND",
+		Message: fmt.Sprintf("taux de change %v/%v introuvable", from, to),
+	}}
+}
+
+// ConvertAmount conversion d'un montant au dernier taux connu, le montant est inchangé
+// si les devises sont identiques ou si l'une d'elles est vide
+func ConvertAmount(tx *gorm.DB, amount float64, from string, to string, at time.Time) (float64, error) {
+	if from == "" || to == "" || from == to {
+		return amount, nil
+	}
+
+	rate, err := FindExchangeRate(tx, from, to, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return RoundAmount(amount * rate), nil
+}
+
+// RoundAmount arrondi d'un montant converti au centime
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// convertSettlement contrôle de la devise et conversion dans la devise de règlement de la boutique,
+// le taux appliqué et les deux montants sont conservés sur la transaction
+func convertSettlement(tx *gorm.DB, t *TransactionModel) error {
+	service := ServiceModel{}
+	result := tx.Model(&ServiceModel{}).Select("id", "settlement_currency").Where("id = ?", t.ServiceId).Limit(1).Find(&service)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if t.Currency == "" {
+		t.Currency = service.SettlementCurrency
+	}
+
+	if t.Currency == "" {
+		return fmt.Errorf("devise obligatoire")
+	}
+
+	currency, err := NormalizeCurrency(t.Currency)
+	if err != nil {
+		return err
+	}
+
+	t.Currency = currency
+
+	// Sans devise de règlement la transaction est réglée dans sa devise
+	t.SettlementCurrency = service.SettlementCurrency
+	if t.SettlementCurrency == "" {
+		t.SettlementCurrency = t.Currency
+	}
+
+	t.ExchangeRate, err = FindExchangeRate(tx, t.Currency, t.SettlementCurrency, time.Now())
+	if err != nil {
+		return err
+	}
+
+	t.SettlementAmount, _ = t.InSettlement(t.Amount)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerModel payeur d'une boutique, identifié par son numéro de téléphone
+type CustomerModel struct {
+	Model
+
+	// Service
+	ServiceId string `json:"service_id" form:"service_id" validate:"required" gorm:"uniqueIndex:idx_customer_phone"`
+
+	Name    string `json:"name,omitempty" form:"name" validate:"omitempty"`
+	Email   string `json:"email,omitempty" form:"email" validate:"omitempty,email"`
+	Country string `json:"country,omitempty" form:"country" validate:"omitempty" gorm:"index"` // CIV
+
+	PhonePrefix string `json:"phone_prefix" form:"-" validate:"-"`                                           // +225
+	PhoneNumber string `json:"phone_number" form:"-" validate:"-"`                                           // Numéro national
+	Phone       string `json:"phone" form:"phone" validate:"required" gorm:"uniqueIndex:idx_customer_phone"` // E.164
+	Operator    string `json:"operator,omitempty" form:"-" validate:"-" gorm:"index"`                        // Opérateur détecté par le préfixe
+
+	Transactions []TransactionModel `json:"transactions,omitempty" form:"-" gorm:"foreignKey:CustomerId"`
+}
+
+// TableName changement du nom de la table
+func (CustomerModel) TableName() string {
+	return "customers"
+}
+
+func (c *CustomerModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	c.ID = uuid.String()
+
+	return
+}
@@ -72,6 +72,50 @@ func CreateUpdateTable(db *gorm.DB) error {
 		&ServicePermissionModel{},
 		&ProviderModel{},
 		&TransactionModel{},
+		&LedgerEntryModel{},
+		&ReconciliationMappingModel{},
+		&ReconciliationModel{},
+		&ReconciliationLineModel{},
+		&PaymentLinkModel{},
+		&EventModel{},
+		&PlanModel{},
+		&SubscriptionModel{},
+		&InstallmentPlanModel{},
+		&InstallmentDueModel{},
+		&CustomerModel{},
+		&LimitModel{},
+		&FraudRuleModel{},
+		&FraudListModel{},
+		&FraudReviewModel{},
+		&DisputeModel{},
+		&DisputeEvidenceModel{},
+		&RefundModel{},
+		&ExchangeRateModel{},
+		&SettlementConfigModel{},
+		&SettlementModel{},
+		&SettlementItemModel{},
+		&TransactionSplitModel{},
+		&TransferModel{},
+		&ApprovalPolicyModel{},
+		&ApprovalRequestModel{},
+		&AuditLogModel{},
+		&AuditHeadModel{},
+		&ServiceDocumentModel{},
+		&ServiceInvitationModel{},
+		&ServiceRoleModel{},
+		&ExchangeRateModel{},
+		&SettlementConfigModel{},
+		&SettlementModel{},
+		&SettlementItemModel{},
+		&TransactionSplitModel{},
+		&TransferModel{},
+		&ApprovalPolicyModel{},
+		&ApprovalRequestModel{},
+		&AuditLogModel{},
+		&AuditHeadModel{},
+		&ServiceDocumentModel{},
+		&ServiceInvitationModel{},
+		&ServiceRoleModel{},
 	)
 }
 
@@ -82,5 +126,23 @@ func DropTable(db *gorm.DB) error {
 		&ServicePermissionModel{},
 		&ProviderModel{},
 		&TransactionModel{},
+		&LedgerEntryModel{},
+		&ReconciliationMappingModel{},
+		&ReconciliationModel{},
+		&ReconciliationLineModel{},
+		&PaymentLinkModel{},
+		&EventModel{},
+		&PlanModel{},
+		&SubscriptionModel{},
+		&InstallmentPlanModel{},
+		&InstallmentDueModel{},
+		&CustomerModel{},
+		&LimitModel{},
+		&FraudRuleModel{},
+		&FraudListModel{},
+		&FraudReviewModel{},
+		&DisputeModel{},
+		&DisputeEvidenceModel{},
+		&RefundModel{},
 	)
 }
@@ -0,0 +1,335 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Etat d'une contestation
+const (
+	DISPUTE_OPENED            = "OPENED"
+	DISPUTE_EVIDENCE_REQUIRED = "EVIDENCE_REQUIRED"
+	DISPUTE_WON               = "WON"  // Contestation rejetée, les fonds retenus sont rendus à la boutique
+	DISPUTE_LOST              = "LOST" // Contestation acceptée, le montant est rétrocédé au payeur
+)
+
+// Mouvements du relevé liés aux contestations
+const (
+	LEDGER_DISPUTE_HOLD    = "DISPUTE_HOLD"
+	LEDGER_DISPUTE_RELEASE = "DISPUTE_RELEASE"
+	LEDGER_CHARGEBACK      = "CHARGEBACK"
+)
+
+// Evénements des contestations
+const (
+	EVENT_DISPUTE_OPENED            = "dispute.opened"
+	EVENT_DISPUTE_EVIDENCE_REQUIRED = "dispute.evidence_required"
+	EVENT_DISPUTE_EVIDENCE_ADDED    = "dispute.evidence_added"
+	EVENT_DISPUTE_WON               = "dispute.won"
+	EVENT_DISPUTE_LOST              = "dispute.lost"
+)
+
+// Transitions autorisées entre états
+var disputeTransitions = map[string][]string{
+	DISPUTE_OPENED:            {DISPUTE_EVIDENCE_REQUIRED, DISPUTE_WON, DISPUTE_LOST},
+	DISPUTE_EVIDENCE_REQUIRED: {DISPUTE_OPENED, DISPUTE_WON, DISPUTE_LOST},
+}
+
+// DisputeModel contestation d'une transaction par le payeur
+type DisputeModel struct {
+	Model
+
+	// Service
+	ServiceId string        `json:"service_id" form:"-" validate:"-" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
+
+	// Transaction contestée
+	TransactionId string            `json:"transaction_id" form:"transaction_id" validate:"required" gorm:"index"`
+	Transaction   *TransactionModel `json:"transaction,omitempty" form:"-" validate:"-"`
+
+	Amount   float64 `json:"amount" form:"amount" validate:"gte=0"` // Montant contesté, retenu sur le solde
+	Currency string  `json:"currency" form:"-" validate:"-"`
+	Reason   string  `json:"reason" form:"reason" validate:"required"`
+	ModeLive bool    `json:"mode_live" form:"-" validate:"-"`
+
+	// Montant retenu dans la devise de règlement de la boutique, au taux de la transaction
+	SettlementAmount   float64 `json:"settlement_amount" form:"-" validate:"-"`
+	SettlementCurrency string  `json:"settlement_currency,omitempty" form:"-" validate:"-"`
+
+	State         string     `json:"state" form:"-" validate:"-" gorm:"index"` // OPENED, EVIDENCE_REQUIRED, WON, LOST
+	EvidenceDueAt *time.Time `json:"evidence_due_at,omitempty" form:"-" validate:"-" gorm:"index"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty" form:"-" validate:"-"`
+	Resolution    string     `json:"resolution,omitempty" form:"-" validate:"-"`
+
+	OpenedById string `json:"opened_by_id" form:"-" validate:"-"`
+
+	Evidences []DisputeEvidenceModel `json:"evidences,omitempty" form:"-" gorm:"foreignKey:DisputeId;constraint:OnDelete:CASCADE;"`
+}
+
+// DisputeEvidenceModel pièce justificative déposée par la boutique
+type DisputeEvidenceModel struct {
+	Model
+
+	DisputeId   string `json:"dispute_id" form:"-" validate:"-" gorm:"index"`
+	FileName    string `json:"file_name" form:"-" validate:"-"`
+	ContentType string `json:"content_type" form:"-" validate:"-"`
+	Size        int64  `json:"size" form:"-" validate:"-"`
+	Path        string `json:"-" form:"-" validate:"-"` // Chemin relatif au dossier d'upload
+	Description string `json:"description,omitempty" form:"description" validate:"omitempty"`
+
+	UploadedById string `json:"uploaded_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (DisputeModel) TableName() string {
+	return "disputes"
+}
+
+// TableName changement du nom de la table
+func (DisputeEvidenceModel) TableName() string {
+	return "disputes_evidences"
+}
+
+func (d *DisputeModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	d.ID = uuid.String()
+
+	if d.State == "" {
+		d.State = DISPUTE_OPENED
+	}
+
+	return
+}
+
+func (e *DisputeEvidenceModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	e.ID = uuid.String()
+
+	return
+}
+
+// IsResolved la contestation est tranchée
+func (d *DisputeModel) IsResolved() bool {
+	return d.State == DISPUTE_WON || d.State == DISPUTE_LOST
+}
+
+// CheckTransition contrôle du passage vers un nouvel état
+func (d *DisputeModel) CheckTransition(state string) error {
+	for _, allowed := range disputeTransitions[d.State] {
+		if allowed == state {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("passage de %v à %v non autorisé", d.State, state)
+}
+
+// OpenDispute enregistrement de la contestation et retenue du montant sur le solde de la boutique,
+// doit être appelé dans une transaction de base de donnée.
+// Le total retenu ou rétrocédé sur la transaction ne peut dépasser son montant
+func OpenDispute(tx *gorm.DB, dispute *DisputeModel, transaction TransactionModel) error {
+	// Verrou de la transaction, les ouvertures concurrentes passent l'une après l'autre
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transaction.ID).Limit(1).Find(&transaction)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("transaction inexistante")
+	}
+
+	if transaction.OperationState != TRANSACTION_SUCCESS || transaction.OperationMode != OPERATION_CREDIT {
+		return fmt.Errorf("seul un paiement réussi peut être contesté")
+	}
+
+	var count int64
+	result = tx.Model(&DisputeModel{}).
+		Where("transaction_id = ? AND state IN (?)", transaction.ID, []string{DISPUTE_OPENED, DISPUTE_EVIDENCE_REQUIRED}).
+		Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if count > 0 {
+		return fmt.Errorf("une contestation est déjà en cours pour cette transaction")
+	}
+
+	// Montant déjà remboursé ou rétrocédé par les contestations perdues
+	charged, err := engagedAmount(tx, &transaction, false)
+	if err != nil {
+		return err
+	}
+
+	// Sans montant, la contestation porte sur le reste de la transaction
+	if dispute.Amount == 0 {
+		dispute.Amount = RoundAmount(transaction.Amount - charged)
+	}
+
+	if dispute.Amount <= 0 || RoundAmount(charged+dispute.Amount) > transaction.Amount {
+		return fmt.Errorf("montant contesté supérieur au montant restant de la transaction (%v)", RoundAmount(transaction.Amount-charged))
+	}
+
+	dispute.ServiceId = transaction.ServiceId
+	dispute.TransactionId = transaction.ID
+	dispute.Currency = transaction.Currency
+	dispute.ModeLive = transaction.ModeLive
+	dispute.SettlementAmount, dispute.SettlementCurrency = transaction.InSettlement(dispute.Amount)
+	dispute.State = DISPUTE_OPENED
+
+	if err := tx.Create(dispute).Error; err != nil {
+		return err
+	}
+
+	if err := dispute.postLedger(tx, LEDGER_DISPUTE_HOLD, -dispute.SettlementAmount); err != nil {
+		return err
+	}
+
+	return PublishEvent(tx, dispute.ServiceId, EVENT_DISPUTE_OPENED, "dispute", dispute.ID, dispute)
+}
+
+// RequestEvidence demande de pièces justificatives à la boutique avant la date limite
+func (d *DisputeModel) RequestEvidence(tx *gorm.DB, dueAt time.Time) error {
+	if err := d.CheckTransition(DISPUTE_EVIDENCE_REQUIRED); err != nil {
+		return err
+	}
+
+	d.State = DISPUTE_EVIDENCE_REQUIRED
+	d.EvidenceDueAt = &dueAt
+
+	result := tx.Model(&DisputeModel{}).
+		Where("id = ?", d.ID).
+		Updates(map[string]interface{}{
+			"state":           d.State,
+			"evidence_due_at": d.EvidenceDueAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return PublishEvent(tx, d.ServiceId, EVENT_DISPUTE_EVIDENCE_REQUIRED, "dispute", d.ID, d)
+}
+
+// AddEvidence ajout d'une pièce, la contestation attendant des pièces repasse à l'examen
+func (d *DisputeModel) AddEvidence(tx *gorm.DB, evidence *DisputeEvidenceModel) error {
+	if d.IsResolved() {
+		return fmt.Errorf("contestation déjà tranchée")
+	}
+
+	evidence.DisputeId = d.ID
+	if err := tx.Create(evidence).Error; err != nil {
+		return err
+	}
+
+	if d.State == DISPUTE_EVIDENCE_REQUIRED {
+		d.State = DISPUTE_OPENED
+
+		result := tx.Model(&DisputeModel{}).Where("id = ?", d.ID).Update("state", d.State)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	d.Evidences = append(d.Evidences, *evidence)
+
+	return PublishEvent(tx, d.ServiceId, EVENT_DISPUTE_EVIDENCE_ADDED, "dispute", d.ID, d)
+}
+
+// Resolve issue de la contestation: gagnée, la retenue est levée; perdue, la retenue devient une rétrocession
+func (d *DisputeModel) Resolve(tx *gorm.DB, state string, resolution string, now time.Time) error {
+	if state != DISPUTE_WON && state != DISPUTE_LOST {
+		return fmt.Errorf("issue de contestation invalide %v", state)
+	}
+
+	if err := d.CheckTransition(state); err != nil {
+		return err
+	}
+
+	// Le changement d'état conditionnel évite une double résolution
+	result := tx.Model(&DisputeModel{}).
+		Where("id = ? AND state = ?", d.ID, d.State).
+		Updates(map[string]interface{}{
+			"state":       state,
+			"resolution":  resolution,
+			"resolved_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("contestation modifiée entre temps")
+	}
+
+	d.State = state
+	d.Resolution = resolution
+	d.ResolvedAt = &now
+
+	if err := d.postLedger(tx, LEDGER_DISPUTE_RELEASE, d.SettlementAmount); err != nil {
+		return err
+	}
+
+	eventType := EVENT_DISPUTE_WON
+	if state == DISPUTE_LOST {
+		eventType = EVENT_DISPUTE_LOST
+
+		chargeback, err := d.chargeback(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := d.postLedger(tx, LEDGER_CHARGEBACK, -chargeback); err != nil {
+			return err
+		}
+	}
+
+	return PublishEvent(tx, d.ServiceId, eventType, "dispute", d.ID, d)
+}
+
+// chargeback montant rétrocédé par la boutique, les bénéficiaires des parts
+// de la transaction supportent leur quote-part de la rétrocession
+func (d *DisputeModel) chargeback(tx *gorm.DB) (float64, error) {
+	transaction := TransactionModel{}
+	result := tx.Where("id = ?", d.TransactionId).Limit(1).Find(&transaction)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if result.RowsAffected == 0 || d.Amount == 0 {
+		return d.SettlementAmount, nil
+	}
+
+	share, err := RefundSplits(tx, &transaction, d.Amount)
+	if err != nil {
+		return 0, err
+	}
+
+	return RoundAmount(d.SettlementAmount * share / d.Amount), nil
+}
+
+// postLedger mouvement sur le solde de la boutique, transactions live uniquement
+func (d *DisputeModel) postLedger(tx *gorm.DB, kind string, amount float64) error {
+	if !d.ModeLive || amount == 0 {
+		return nil
+	}
+
+	return PostLedger(tx, &LedgerEntryModel{
+		ServiceId:     d.ServiceId,
+		TransactionId: d.TransactionId,
+		Kind:          kind,
+		Label:         d.Reason,
+		Amount:        amount,
+		Currency:      d.SettlementCurrency,
+	})
+}
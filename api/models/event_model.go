@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Types d'événements émis vers les boutiques
+const (
+	EVENT_SUBSCRIPTION_CREATED           = "subscription.created"
+	EVENT_SUBSCRIPTION_PAUSED            = "subscription.paused"
+	EVENT_SUBSCRIPTION_RESUMED           = "subscription.resumed"
+	EVENT_SUBSCRIPTION_CANCELED          = "subscription.canceled"
+	EVENT_SUBSCRIPTION_PAYMENT_SUCCEEDED = "subscription.payment_succeeded"
+	EVENT_SUBSCRIPTION_PAYMENT_FAILED    = "subscription.payment_failed"
+	EVENT_SUBSCRIPTION_UNPAID            = "subscription.unpaid"
+)
+
+// Nombre maximum de tentatives de livraison d'un événement au webhook
+const EVENT_MAX_ATTEMPTS = 10
+
+// EventModel événement d'une boutique, consultable par l'api et livré au webhook de la boutique
+type EventModel struct {
+	Model
+
+	ServiceId  string `json:"service_id" form:"-" validate:"-" gorm:"index"`
+	Type       string `json:"type" form:"-" validate:"-" gorm:"index"`
+	ObjectType string `json:"object_type" form:"-" validate:"-"`
+	ObjectId   string `json:"object_id" form:"-" validate:"-" gorm:"index"`
+	Payload    string `json:"payload" form:"-" validate:"-"` // Objet au format json
+
+	// Livraison webhook
+	Delivered     bool       `json:"delivered" form:"-" validate:"-" gorm:"index"`
+	Attempts      int        `json:"attempts" form:"-" validate:"-"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" form:"-" validate:"-"`
+	LastError     string     `json:"last_error,omitempty" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (EventModel) TableName() string {
+	return "services_events"
+}
+
+// PublishEvent enregistrement d'un événement dans la transaction en cours
+func PublishEvent(tx *gorm.DB, serviceId string, eventType string, objectType string, objectId string, object interface{}) error {
+	payload, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&EventModel{
+		ServiceId:  serviceId,
+		Type:       eventType,
+		ObjectType: objectType,
+		ObjectId:   objectId,
+		Payload:    string(payload),
+	}).Error
+}
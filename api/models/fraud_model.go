@@ -0,0 +1,406 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Types de règles anti-fraude
+const (
+	FRAUD_RULE_PHONE_SERVICES = "PHONE_SERVICES" // Même téléphone sur plus de Threshold boutiques en WindowMinutes
+	FRAUD_RULE_NEAR_LIMIT     = "NEAR_LIMIT"     // Plus de Threshold montants proches du plafond (Ratio) en WindowMinutes
+	FRAUD_RULE_AMOUNT_ABOVE   = "AMOUNT_ABOVE"   // Montant supérieur ou égal à Amount
+	FRAUD_RULE_BLOCKED_PHONE  = "BLOCKED_PHONE"  // Téléphone présent dans la liste de blocage
+	FRAUD_RULE_BLOCKED_IP     = "BLOCKED_IP"     // Adresse IP présente dans la liste de blocage
+)
+
+// Actions anti-fraude, par ordre de sévérité
+const (
+	FRAUD_ALLOW  = "ALLOW"
+	FRAUD_REVIEW = "REVIEW"
+	FRAUD_BLOCK  = "BLOCK"
+)
+
+// Types de listes de blocage
+const (
+	FRAUD_LIST_PHONE = "PHONE"
+	FRAUD_LIST_IP    = "IP"
+)
+
+// Etat d'une revue anti-fraude
+const (
+	REVIEW_PENDING  = "PENDING"
+	REVIEW_APPROVED = "APPROVED"
+	REVIEW_REJECTED = "REJECTED"
+)
+
+// Code d'erreur d'une transaction bloquée
+const FRAUD_BLOCKED = "FRAUD_BLOCKED"
+
+var fraudSeverity = map[string]int{
+	FRAUD_ALLOW:  0,
+	FRAUD_REVIEW: 1,
+	FRAUD_BLOCK:  2,
+}
+
+// FraudRuleModel règle anti-fraude évaluée à la création de chaque transaction
+type FraudRuleModel struct {
+	Model
+
+	Name        string `json:"name" form:"name" validate:"required"`
+	Description string `json:"description,omitempty" form:"description" validate:"omitempty"`
+	Kind        string `json:"kind" form:"kind" validate:"required,oneof=PHONE_SERVICES NEAR_LIMIT AMOUNT_ABOVE BLOCKED_PHONE BLOCKED_IP" gorm:"index"`
+	Active      bool   `json:"active" form:"active" validate:"-"`
+
+	// Paramètres de la règle selon son type
+	Threshold     int64   `json:"threshold" form:"threshold" validate:"gte=0"`
+	WindowMinutes int     `json:"window_minutes" form:"window_minutes" validate:"gte=0"`
+	Amount        float64 `json:"amount" form:"amount" validate:"gte=0"`                  // Montant de référence, plafond des limites par défaut
+	Currency      string  `json:"currency" form:"currency" validate:"omitempty,currency"` // Devise de Amount, vide: devise de règlement de la transaction
+	Ratio         float64 `json:"ratio" form:"ratio" validate:"gte=0,lte=1"`
+
+	// Résultat lorsque la règle est déclenchée
+	Score  int    `json:"score" form:"score" validate:"gte=0"`
+	Action string `json:"action" form:"action" validate:"required,oneof=ALLOW REVIEW BLOCK"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (FraudRuleModel) TableName() string {
+	return "fraud_rules"
+}
+
+func (r *FraudRuleModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	return
+}
+
+// FraudListModel téléphone ou adresse IP bloqué
+type FraudListModel struct {
+	Model
+
+	Kind   string `json:"kind" form:"kind" validate:"required,oneof=PHONE IP" gorm:"uniqueIndex:idx_fraud_list"`
+	Value  string `json:"value" form:"value" validate:"required" gorm:"uniqueIndex:idx_fraud_list"`
+	Reason string `json:"reason,omitempty" form:"reason" validate:"omitempty"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (FraudListModel) TableName() string {
+	return "fraud_lists"
+}
+
+func (l *FraudListModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	l.ID = uuid.String()
+
+	return
+}
+
+// FraudReviewModel transaction signalée en attente de décision d'un gestionnaire
+type FraudReviewModel struct {
+	Model
+
+	TransactionId string            `json:"transaction_id" form:"-" validate:"-" gorm:"uniqueIndex"`
+	Transaction   *TransactionModel `json:"transaction,omitempty" form:"-" validate:"-"`
+	ServiceId     string            `json:"service_id" form:"-" validate:"-" gorm:"index"`
+
+	Score   int      `json:"score" form:"-" validate:"-"`
+	Reasons []string `json:"reasons" form:"-" validate:"-" gorm:"type:text;serializer:json"` // Règles déclenchées, tableau json (un nom de règle peut contenir une virgule)
+
+	State        string     `json:"state" form:"-" validate:"-" gorm:"index"` // PENDING, APPROVED, REJECTED
+	ReviewedById string     `json:"reviewed_by_id,omitempty" form:"-" validate:"-"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty" form:"-" validate:"-"`
+	Comment      string     `json:"comment,omitempty" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (FraudReviewModel) TableName() string {
+	return "fraud_reviews"
+}
+
+func (r *FraudReviewModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	if r.State == "" {
+		r.State = REVIEW_PENDING
+	}
+
+	return
+}
+
+// FraudResult résultat de l'évaluation des règles
+type FraudResult struct {
+	Score   int      `json:"score"`
+	Action  string   `json:"action"`
+	Reasons []string `json:"reasons"`
+}
+
+// Match la règle est déclenchée par la transaction
+func (r *FraudRuleModel) Match(tx *gorm.DB, t *TransactionModel, now time.Time) (bool, error) {
+	since := now.Add(-time.Duration(r.WindowMinutes) * time.Minute)
+
+	switch r.Kind {
+	case FRAUD_RULE_AMOUNT_ABOVE:
+		if r.Amount == 0 {
+			return false, nil
+		}
+
+		amount, err := ConvertAmount(tx, t.SettlementAmount, t.SettlementCurrency, r.Currency, now)
+		if err != nil {
+			return false, err
+		}
+
+		return amount >= r.Amount, nil
+
+	case FRAUD_RULE_BLOCKED_PHONE:
+		return isListed(tx, FRAUD_LIST_PHONE, t.PayerPhone)
+
+	case FRAUD_RULE_BLOCKED_IP:
+		return isListed(tx, FRAUD_LIST_IP, t.PayerIp)
+
+	case FRAUD_RULE_PHONE_SERVICES:
+		if t.PayerPhone == "" {
+			return false, nil
+		}
+
+		services := []string{}
+		result := tx.Model(&TransactionModel{}).
+			Where("payer_phone = ? AND created_at >= ? AND service_id <> ?", t.PayerPhone, since, t.ServiceId).
+			Distinct("service_id").
+			Pluck("service_id", &services)
+		if result.Error != nil {
+			return false, result.Error
+		}
+
+		// La boutique de la nouvelle transaction est comptée
+		return int64(len(services)+1) > r.Threshold, nil
+
+	case FRAUD_RULE_NEAR_LIMIT:
+		if t.PayerPhone == "" {
+			return false, nil
+		}
+
+		// Les montants sont comparés dans la devise de règlement de la transaction
+		var reference float64
+		var err error
+		if r.Amount > 0 {
+			reference, err = ConvertAmount(tx, r.Amount, r.Currency, t.SettlementCurrency, now)
+		} else {
+			reference, err = lowestMaxAmount(tx, t, now)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		floor := reference * r.Ratio
+		if reference == 0 || t.SettlementAmount < floor || t.SettlementAmount > reference {
+			return false, nil
+		}
+
+		var count int64
+		result := tx.Model(&TransactionModel{}).
+			Where("payer_phone = ? AND created_at >= ?", t.PayerPhone, since).
+			Where("settlement_currency = ? AND settlement_amount >= ? AND settlement_amount <= ?", t.SettlementCurrency, floor, reference).
+			Count(&count)
+		if result.Error != nil {
+			return false, result.Error
+		}
+
+		return count+1 > r.Threshold, nil
+	}
+
+	return false, fmt.Errorf("type de règle inconnu %v", r.Kind)
+}
+
+// EvaluateFraud évaluation des règles actives: le score est la somme des règles déclenchées
+// et l'action la plus sévère l'emporte
+func EvaluateFraud(tx *gorm.DB, t *TransactionModel) (*FraudResult, error) {
+	rules := []FraudRuleModel{}
+	result := tx.Where("active = ?", true).Order("created_at asc").Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	fraud := FraudResult{Action: FRAUD_ALLOW, Reasons: []string{}}
+	now := time.Now()
+	for _, rule := range rules {
+		match, err := rule.Match(tx, t, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if !match {
+			continue
+		}
+
+		fraud.Score += rule.Score
+		fraud.Reasons = append(fraud.Reasons, rule.Name)
+		if fraudSeverity[rule.Action] > fraudSeverity[fraud.Action] {
+			fraud.Action = rule.Action
+		}
+	}
+
+	return &fraud, nil
+}
+
+// checkFraud refus des transactions bloquées, les transactions à revoir sont retenues
+// jusqu'à la décision d'un gestionnaire
+func checkFraud(tx *gorm.DB, t *TransactionModel) error {
+	fraud, err := EvaluateFraud(tx, t)
+	if err != nil {
+		return err
+	}
+
+	t.FraudScore = fraud.Score
+	t.FraudAction = fraud.Action
+
+	if fraud.Action == FRAUD_BLOCK {
+		return ResErrorAPI{
+			ErrorAPI{
+				Code:    FRAUD_BLOCKED,
+				Message: "transaction refusée par le contrôle anti-fraude",
+				Data:    fraud,
+			},
+		}
+	}
+
+	if fraud.Action == FRAUD_REVIEW {
+		t.fraudReasons = fraud.Reasons
+	}
+
+	return nil
+}
+
+// openFraudReview ajout de la transaction à la file de revue
+func openFraudReview(tx *gorm.DB, t *TransactionModel) error {
+	if t.FraudAction != FRAUD_REVIEW {
+		return nil
+	}
+
+	return tx.Create(&FraudReviewModel{
+		TransactionId: t.ID,
+		ServiceId:     t.ServiceId,
+		Score:         t.FraudScore,
+		Reasons:       t.fraudReasons,
+	}).Error
+}
+
+func isListed(tx *gorm.DB, kind string, value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	var count int64
+	result := tx.Model(&FraudListModel{}).Where("kind = ? AND value = ?", kind, value).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+// lowestMaxAmount plus petit montant maximum par transaction des limites applicables,
+// converti dans la devise de règlement de la transaction
+func lowestMaxAmount(tx *gorm.DB, t *TransactionModel, now time.Time) (float64, error) {
+	limits := []LimitModel{}
+	result := tx.Model(&LimitModel{}).
+		Where("active = ? AND mode_live = ? AND max_amount > 0", true, t.ModeLive).
+		Where("(scope = ? AND scope_id IN ?) OR (scope = ? AND scope_id IN ?) OR (scope = ? AND scope_id IN ?)",
+			LIMIT_SCOPE_SERVICE, []string{t.ServiceId, ""},
+			LIMIT_SCOPE_PROVIDER, []string{t.ProviderId, ""},
+			LIMIT_SCOPE_PAYER, []string{t.PayerPhone, ""},
+		).
+		Select("max_amount", "currency").
+		Find(&limits)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var lowest float64
+	for _, limit := range limits {
+		amount, err := ConvertAmount(tx, limit.MaxAmount, limit.Currency, t.SettlementCurrency, now)
+		if err != nil {
+			return 0, err
+		}
+
+		if lowest == 0 || amount < lowest {
+			lowest = amount
+		}
+	}
+
+	return lowest, nil
+}
+
+// Decide décision d'un gestionnaire sur une revue en attente: l'approbation libère la transaction,
+// le rejet la passe en FAIL, doit être appelé dans une transaction de base de donnée
+func (r *FraudReviewModel) Decide(tx *gorm.DB, state string, reviewerId string, comment string) error {
+	now := time.Now()
+
+	result := tx.Model(&FraudReviewModel{}).
+		Where("id = ? AND state = ?", r.ID, REVIEW_PENDING).
+		Updates(map[string]interface{}{
+			"state":          state,
+			"reviewed_by_id": reviewerId,
+			"reviewed_at":    now,
+			"comment":        comment,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("revue déjà traitée")
+	}
+
+	r.State = state
+	r.ReviewedById = reviewerId
+	r.ReviewedAt = &now
+	r.Comment = comment
+
+	transaction := TransactionModel{}
+	result = tx.Where("id = ?", r.TransactionId).First(&transaction)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	transaction.FraudAction = FRAUD_ALLOW
+	if state == REVIEW_REJECTED {
+		transaction.FraudAction = FRAUD_BLOCK
+	}
+
+	result = tx.Model(&TransactionModel{}).Where("id = ?", transaction.ID).Update("fraud_action", transaction.FraudAction)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if state == REVIEW_REJECTED && !transaction.IsFinal() {
+		if err := transaction.SetState(tx, TRANSACTION_FAIL, "transaction refusée par la revue anti-fraude"); err != nil {
+			return err
+		}
+	}
+
+	r.Transaction = &transaction
+
+	return nil
+}
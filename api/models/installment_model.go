@@ -0,0 +1,216 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statut d'un échéancier
+const (
+	INSTALLMENT_PENDING        = "PENDING"
+	INSTALLMENT_PARTIALLY_PAID = "PARTIALLY_PAID"
+	INSTALLMENT_PAID           = "PAID"
+	INSTALLMENT_OVERDUE        = "OVERDUE"
+)
+
+// Evénements des échéanciers
+const (
+	EVENT_INSTALLMENT_PAID    = "installment.paid"
+	EVENT_INSTALLMENT_OVERDUE = "installment.overdue"
+)
+
+// InstallmentPlanModel échéancier de paiement d'une facture (ReferenceId) en plusieurs fois
+type InstallmentPlanModel struct {
+	Model
+
+	// Service
+	ServiceId string `json:"service_id" form:"service_id" validate:"required" gorm:"uniqueIndex:idx_installments_reference"`
+
+	ReferenceId string  `json:"reference_id" form:"reference_id" validate:"required" gorm:"uniqueIndex:idx_installments_reference"` // Id facture payée par les transactions
+	Description string  `json:"description" form:"description" validate:"omitempty"`
+	ModeLive    bool    `json:"mode_live" form:"mode_live" validate:"-" gorm:"uniqueIndex:idx_installments_reference"`
+	Currency    string  `json:"currency" form:"currency" validate:"required"`
+	TotalDue    float64 `json:"total_due" form:"total_due" validate:"required,gt=0"`
+	PaidAmount  float64 `json:"paid_amount" form:"-" validate:"-"`
+	Status      string  `json:"status" form:"-" validate:"-" gorm:"index"`
+
+	Dues []InstallmentDueModel `json:"dues,omitempty" form:"dues" validate:"required,min=1,dive" gorm:"foreignKey:PlanId;constraint:OnDelete:CASCADE;"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (InstallmentPlanModel) TableName() string {
+	return "installments"
+}
+
+// InstallmentDueModel échéance d'un échéancier
+type InstallmentDueModel struct {
+	Model
+
+	PlanId  string    `json:"plan_id" form:"-" validate:"-" gorm:"index"`
+	DueDate time.Time `json:"due_date" form:"due_date" validate:"required"`
+	Amount  float64   `json:"amount" form:"amount" validate:"required,gt=0"`
+}
+
+// TableName changement du nom de la table
+func (InstallmentDueModel) TableName() string {
+	return "installments_dues"
+}
+
+// Outstanding solde restant dû
+func (p *InstallmentPlanModel) Outstanding() float64 {
+	return math.Max(p.TotalDue-p.PaidAmount, 0)
+}
+
+// CheckSchedule les échéances doivent couvrir exactement le total dû
+func (p *InstallmentPlanModel) CheckSchedule() error {
+	var total float64
+	for i, due := range p.Dues {
+		if i > 0 && !due.DueDate.After(p.Dues[i-1].DueDate) {
+			return fmt.Errorf("les dates d'échéance doivent être croissantes")
+		}
+
+		total += due.Amount
+	}
+
+	if math.Abs(total-p.TotalDue) >= 0.005 {
+		return fmt.Errorf("la somme des échéances (%.2f) doit être égale au total dû (%.2f)", total, p.TotalDue)
+	}
+
+	return nil
+}
+
+// ComputeStatus statut de l'échéancier à la date indiquée, les échéances doivent être chargées
+func (p *InstallmentPlanModel) ComputeStatus(now time.Time) string {
+	if p.PaidAmount+0.005 >= p.TotalDue {
+		return INSTALLMENT_PAID
+	}
+
+	// Montant exigible à date
+	var due float64
+	for _, item := range p.Dues {
+		if item.DueDate.Before(now) {
+			due += item.Amount
+		}
+	}
+
+	if p.PaidAmount+0.005 < due {
+		return INSTALLMENT_OVERDUE
+	}
+
+	if p.PaidAmount > 0 {
+		return INSTALLMENT_PARTIALLY_PAID
+	}
+
+	return INSTALLMENT_PENDING
+}
+
+// RefreshStatus recalcul et enregistrement du statut, un événement est émis à l'entrée en PAID ou OVERDUE
+func (p *InstallmentPlanModel) RefreshStatus(tx *gorm.DB, now time.Time) error {
+	status := p.ComputeStatus(now)
+	if status == p.Status {
+		return nil
+	}
+
+	p.Status = status
+
+	result := tx.Model(&InstallmentPlanModel{}).Where("id = ?", p.ID).Update("status", p.Status)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	switch status {
+	case INSTALLMENT_PAID:
+		return PublishEvent(tx, p.ServiceId, EVENT_INSTALLMENT_PAID, "installment", p.ID, p)
+	case INSTALLMENT_OVERDUE:
+		return PublishEvent(tx, p.ServiceId, EVENT_INSTALLMENT_OVERDUE, "installment", p.ID, p)
+	}
+
+	return nil
+}
+
+// attachInstallment rattachement d'une nouvelle transaction à l'échéancier de sa réference,
+// un montant supérieur au solde restant dû (paiements en attente inclus) est refusé
+func attachInstallment(tx *gorm.DB, t *TransactionModel) error {
+	if t.OperationMode != OPERATION_CREDIT || t.ReferenceId == "" {
+		return nil
+	}
+
+	plans := []InstallmentPlanModel{}
+	result := tx.
+		Where("service_id = ? AND reference_id = ? AND mode_live = ?", t.ServiceId, t.ReferenceId, t.ModeLive).
+		Limit(1).
+		Find(&plans)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if len(plans) == 0 {
+		return nil
+	}
+
+	// Verrou de l'échéancier, portable sur tous les moteurs: les paiements concurrents
+	// sont contrôlés l'un après l'autre puis le solde est relu
+	result = tx.Model(&InstallmentPlanModel{}).Where("id = ?", plans[0].ID).UpdateColumn("updated_at", gorm.Expr("updated_at"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	plan := InstallmentPlanModel{}
+	if err := tx.Where("id = ?", plans[0].ID).First(&plan).Error; err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(plan.Currency, t.Currency) {
+		return fmt.Errorf("devise de l'échéancier attendue %v", plan.Currency)
+	}
+
+	var pending float64
+	result = tx.Model(&TransactionModel{}).
+		Select("coalesce(sum(amount), 0)").
+		Where("installment_plan_id = ? AND operation_state IN ?", plan.ID, []string{TRANSACTION_PENDING, TRANSACTION_AUTHORIZED}).
+		Scan(&pending)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if t.Amount > plan.Outstanding()-pending+0.005 {
+		return fmt.Errorf("montant supérieur au solde restant dû (%.2f %v)", math.Max(plan.Outstanding()-pending, 0), plan.Currency)
+	}
+
+	t.InstallmentPlanId = plan.ID
+
+	return nil
+}
+
+// applyInstallment mise à jour du montant payé à l'entrée ou à la sortie de l'état SUCCESS
+func applyInstallment(tx *gorm.DB, t *TransactionModel, success bool) error {
+	if t.InstallmentPlanId == "" {
+		return nil
+	}
+
+	amount := t.Amount
+	if !success {
+		amount = -amount
+	}
+
+	result := tx.Model(&InstallmentPlanModel{}).
+		Where("id = ?", t.InstallmentPlanId).
+		Update("paid_amount", gorm.Expr("paid_amount + ?", amount))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	plan := InstallmentPlanModel{}
+	result = tx.Preload("Dues").Where("id = ?", t.InstallmentPlanId).First(&plan)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return plan.RefreshStatus(tx, time.Now())
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Etats d'une invitation, une invitation PENDING dont la date d'expiration est passée n'est plus acceptable
+const (
+	INVITATION_PENDING  = "PENDING"
+	INVITATION_ACCEPTED = "ACCEPTED"
+	INVITATION_REVOKED  = "REVOKED"
+)
+
+// Durée de validité d'une invitation si INVITATION_TTL n'est pas configuré
+const DEFAULT_INVITATION_TTL = 7 * 24 * time.Hour
+
+// Evénements des invitations
+const (
+	EVENT_INVITATION_CREATED  = "invitation.created"
+	EVENT_INVITATION_ACCEPTED = "invitation.accepted"
+	EVENT_INVITATION_REVOKED  = "invitation.revoked"
+)
+
+// ServiceInvitationModel invitation d'une adresse e-mail à rejoindre une boutique avec un rôle
+type ServiceInvitationModel struct {
+	Model
+
+	ServiceId string        `json:"service_id" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" gorm:"foreignKey:ServiceId"`
+
+	Email     string      `json:"email" gorm:"index"`
+	Role      ServiceRole `json:"role" gorm:"type:text"`
+	State     string      `json:"state" gorm:"index"`
+	TokenHash string      `json:"-" gorm:"uniqueIndex"` // Empreinte sha256 du jeton envoyé par e-mail
+	ExpiresAt time.Time   `json:"expires_at"`
+
+	InvitedById  string     `json:"invited_by_id"`
+	AcceptedById string     `json:"accepted_by_id,omitempty"`
+	AcceptedAt   *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName changement du nom de la table
+func (ServiceInvitationModel) TableName() string {
+	return "services_invitations"
+}
+
+func (i *ServiceInvitationModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	i.ID = uuid.String()
+
+	return
+}
+
+// IsExpired invitation non acceptée dont la date limite est passée
+func (i *ServiceInvitationModel) IsExpired(now time.Time) bool {
+	return i.State == INVITATION_PENDING && !now.Before(i.ExpiresAt)
+}
+
+// CreateInvitation enregistrement d'une invitation, retourne le jeton en clair à envoyer à l'invité
+func CreateInvitation(tx *gorm.DB, i *ServiceInvitationModel, ttl time.Duration, now time.Time) (string, error) {
+	i.Email = strings.ToLower(strings.TrimSpace(i.Email))
+
+	// L'adresse ne doit pas déjà être membre de la boutique
+	var members int64
+	result := tx.Model(&ServicePermissionModel{}).
+		Joins("JOIN users ON users.id = services_permissions.user_id").
+		Where("services_permissions.service_id = ? AND lower(users.email) = ?", i.ServiceId, i.Email).
+		Count(&members)
+	if result.Error != nil {
+		return "", result.Error
+	}
+
+	if members > 0 {
+		return "", fmt.Errorf("utilisateur déjà ajouté a cette boutique")
+	}
+
+	var pending int64
+	result = tx.Model(&ServiceInvitationModel{}).
+		Where("service_id = ? AND email = ? AND state = ? AND expires_at > ?", i.ServiceId, i.Email, INVITATION_PENDING, now).
+		Count(&pending)
+	if result.Error != nil {
+		return "", result.Error
+	}
+
+	if pending > 0 {
+		return "", fmt.Errorf("invitation déjà en attente pour %v", i.Email)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(raw)
+
+	i.TokenHash = invitationHash(token)
+	i.State = INVITATION_PENDING
+	i.ExpiresAt = now.Add(ttl)
+
+	if err := tx.Create(i).Error; err != nil {
+		return "", err
+	}
+
+	if err := PublishEvent(tx, i.ServiceId, EVENT_INVITATION_CREATED, "invitation", i.ID, i); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// FindInvitation invitation correspondant au jeton reçu par e-mail
+func FindInvitation(tx *gorm.DB, token string) (*ServiceInvitationModel, error) {
+	invitation := ServiceInvitationModel{}
+
+	result := tx.
+		Preload("Service").
+		Where("token_hash = ?", invitationHash(token)).
+		Limit(1).
+		Find(&invitation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("invitation inexistante")
+	}
+
+	return &invitation, nil
+}
+
+// Accept acceptation de l'invitation par l'utilisateur, création de sa permission sur la boutique.
+// Seul le compte de l'adresse e-mail invitée peut accepter
+func (i *ServiceInvitationModel) Accept(tx *gorm.DB, user *UserModel, now time.Time) (*ServicePermissionModel, error) {
+	if strings.ToLower(strings.TrimSpace(user.Email)) != i.Email {
+		return nil, fmt.Errorf("invitation destinée à une autre adresse e-mail")
+	}
+
+	if i.State != INVITATION_PENDING {
+		return nil, fmt.Errorf("invitation déjà utilisée ou révoquée")
+	}
+
+	if i.IsExpired(now) {
+		return nil, fmt.Errorf("invitation expirée")
+	}
+
+	var exists int64
+	result := tx.Model(&ServicePermissionModel{}).Where("service_id = ? AND user_id = ?", i.ServiceId, user.ID).Count(&exists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if exists > 0 {
+		return nil, fmt.Errorf("utilisateur déjà ajouté a cette boutique")
+	}
+
+	permission := ServicePermissionModel{
+		ServiceId: i.ServiceId,
+		UserId:    user.ID,
+		Role:      i.Role,
+	}
+
+	if err := tx.Create(&permission).Error; err != nil {
+		return nil, err
+	}
+
+	result = tx.Model(&ServiceInvitationModel{}).
+		Where("id = ? AND state = ?", i.ID, INVITATION_PENDING).
+		Updates(map[string]interface{}{
+			"state":          INVITATION_ACCEPTED,
+			"accepted_by_id": user.ID,
+			"accepted_at":    now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("invitation modifiée entre temps")
+	}
+
+	i.State = INVITATION_ACCEPTED
+	i.AcceptedById = user.ID
+	i.AcceptedAt = &now
+
+	if err := PublishEvent(tx, i.ServiceId, EVENT_INVITATION_ACCEPTED, "invitation", i.ID, i); err != nil {
+		return nil, err
+	}
+
+	return &permission, nil
+}
+
+// Revoke révocation d'une invitation en attente
+func (i *ServiceInvitationModel) Revoke(tx *gorm.DB, now time.Time) error {
+	if i.State != INVITATION_PENDING {
+		return fmt.Errorf("seule une invitation en attente peut être révoquée")
+	}
+
+	result := tx.Model(&ServiceInvitationModel{}).
+		Where("id = ? AND state = ?", i.ID, INVITATION_PENDING).
+		Updates(map[string]interface{}{
+			"state":      INVITATION_REVOKED,
+			"revoked_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("invitation modifiée entre temps")
+	}
+
+	i.State = INVITATION_REVOKED
+	i.RevokedAt = &now
+
+	return PublishEvent(tx, i.ServiceId, EVENT_INVITATION_REVOKED, "invitation", i.ID, i)
+}
+
+func invitationHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Type de mouvement sur le solde d'une boutique
+const (
+	LEDGER_TRANSACTION = "TRANSACTION"
+	LEDGER_REVERSAL    = "REVERSAL"
+)
+
+// LedgerEntryModel mouvement sur le solde d'une boutique (relevé)
+type LedgerEntryModel struct {
+	Model
+
+	// Service
+	ServiceId string        `json:"service_id" form:"service_id" validate:"required" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
+
+	// Transaction à l'origine du mouvement
+	TransactionId string `json:"transaction_id,omitempty" form:"transaction_id" validate:"-" gorm:"index"`
+
+	// Virement interne à l'origine du mouvement
+	TransferId string `json:"transfer_id,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	Kind         string  `json:"kind" form:"kind" validate:"required" gorm:"index"`
+	Label        string  `json:"label" form:"label" validate:"-"`
+	Amount       float64 `json:"amount" form:"amount" validate:"required"` // Montant signé
+	BalanceAfter float64 `json:"balance_after" form:"-" validate:"-"`
+	Currency     string  `json:"currency" form:"currency" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (LedgerEntryModel) TableName() string {
+	return "services_ledger"
+}
+
+// PostLedger enregistrement d'un mouvement et mise à jour du solde de la boutique,
+// doit être appelé dans une transaction de base de donnée
+func PostLedger(tx *gorm.DB, entry *LedgerEntryModel) error {
+	result := tx.Model(&ServiceModel{}).
+		Where("id = ?", entry.ServiceId).
+		Update("current_amount", gorm.Expr("current_amount + ?", entry.Amount))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	service := ServiceModel{}
+	result = tx.Model(&ServiceModel{}).Select("id", "current_amount").Where("id = ?", entry.ServiceId).First(&service)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	entry.BalanceAfter = service.CurrentAmount
+
+	return tx.Create(entry).Error
+}
+
+// ledgerRemaining montant d'une transaction encore porté au solde de la boutique,
+// mouvement initial diminué des annulations, remboursements et rétrocessions déjà passés
+func ledgerRemaining(tx *gorm.DB, serviceId string, transactionId string, currency string) (float64, error) {
+	var remaining float64
+	result := tx.Model(&LedgerEntryModel{}).
+		Where("service_id = ? AND transaction_id = ? AND currency = ?", serviceId, transactionId, currency).
+		Where("kind IN ?", []string{LEDGER_TRANSACTION, LEDGER_REVERSAL, LEDGER_REFUND, LEDGER_CHARGEBACK}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&remaining)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return RoundAmount(remaining), nil
+}
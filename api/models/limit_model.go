@@ -0,0 +1,235 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Portée d'une limite
+const (
+	LIMIT_SCOPE_SERVICE  = "SERVICE"
+	LIMIT_SCOPE_PROVIDER = "PROVIDER"
+	LIMIT_SCOPE_PAYER    = "PAYER"
+)
+
+// Codes d'erreur de dépassement
+const (
+	LIMIT_AMOUNT_EXCEEDED  = "LIMIT_AMOUNT_EXCEEDED"
+	LIMIT_DAILY_EXCEEDED   = "LIMIT_DAILY_EXCEEDED"
+	LIMIT_MONTHLY_EXCEEDED = "LIMIT_MONTHLY_EXCEEDED"
+	LIMIT_HOURLY_EXCEEDED  = "LIMIT_HOURLY_COUNT_EXCEEDED"
+)
+
+// Colonne de la transaction correspondant à chaque portée
+var limitScopeColumns = map[string]string{
+	LIMIT_SCOPE_SERVICE:  "service_id",
+	LIMIT_SCOPE_PROVIDER: "provider_id",
+	LIMIT_SCOPE_PAYER:    "payer_phone",
+}
+
+// LimitModel plafonds appliqués à la création des transactions, une valeur à 0 désactive le plafond
+type LimitModel struct {
+	Model
+
+	Scope       string `json:"scope" form:"scope" validate:"required,oneof=SERVICE PROVIDER PAYER" gorm:"index"`
+	ScopeId     string `json:"scope_id" form:"scope_id" validate:"omitempty" gorm:"index"` // Boutique, provider ou téléphone E.164, vide pour tous
+	ModeLive    bool   `json:"mode_live" form:"mode_live" validate:"-"`
+	Active      bool   `json:"active" form:"active" validate:"-"`
+	Description string `json:"description,omitempty" form:"description" validate:"omitempty"`
+	Currency    string `json:"currency" form:"currency" validate:"omitempty,currency"` // Devise des plafonds, vide: devise de règlement de chaque transaction
+
+	MaxAmount     float64 `json:"max_amount" form:"max_amount" validate:"gte=0"`         // Montant maximum par transaction
+	DailyAmount   float64 `json:"daily_amount" form:"daily_amount" validate:"gte=0"`     // Total journalier
+	MonthlyAmount float64 `json:"monthly_amount" form:"monthly_amount" validate:"gte=0"` // Total mensuel
+	HourlyCount   int64   `json:"hourly_count" form:"hourly_count" validate:"gte=0"`     // Nombre de transactions sur une heure glissante
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// LimitUsage consommation courante d'une limite
+type LimitUsage struct {
+	ScopeId       string  `json:"scope_id"`
+	Currency      string  `json:"currency"`
+	DailyAmount   float64 `json:"daily_amount"`
+	MonthlyAmount float64 `json:"monthly_amount"`
+	HourlyCount   int64   `json:"hourly_count"`
+}
+
+// TableName changement du nom de la table
+func (LimitModel) TableName() string {
+	return "limits"
+}
+
+func (l *LimitModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	l.ID = uuid.String()
+
+	return
+}
+
+// Usage consommation de la limite pour une boutique, un provider ou un téléphone dans la devise indiquée,
+// seules les transactions en attente, autorisées ou réussies sont comptées.
+// Les montants réglés sont totalisés par devise de règlement puis convertis
+func (l *LimitModel) Usage(tx *gorm.DB, scopeId string, currency string, now time.Time) (*LimitUsage, error) {
+	column, ok := limitScopeColumns[l.Scope]
+	if !ok {
+		return nil, fmt.Errorf("portée de limite inconnue %v", l.Scope)
+	}
+
+	usage := LimitUsage{ScopeId: scopeId, Currency: currency}
+
+	query := func() *gorm.DB {
+		return tx.Model(&TransactionModel{}).
+			Where(column+" = ?", scopeId).
+			Where("mode_live = ?", l.ModeLive).
+			Where("operation_state IN ?", committedStates)
+	}
+
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var err error
+	usage.DailyAmount, err = sumSettled(tx, query().Where("created_at >= ?", dayStart), currency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.MonthlyAmount, err = sumSettled(tx, query().Where("created_at >= ?", monthStart), currency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := query().Where("created_at >= ?", now.Add(-time.Hour)).Count(&usage.HourlyCount)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &usage, nil
+}
+
+// sumSettled total des montants réglés converti dans la devise indiquée
+func sumSettled(tx *gorm.DB, query *gorm.DB, currency string, now time.Time) (float64, error) {
+	rows := []struct {
+		SettlementCurrency string
+		Total              float64
+	}{}
+
+	result := query.
+		Select("settlement_currency, COALESCE(SUM(settlement_amount), 0) AS total").
+		Group("settlement_currency").
+		Scan(&rows)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var total float64
+	for _, row := range rows {
+		amount, err := ConvertAmount(tx, row.Total, row.SettlementCurrency, currency, now)
+		if err != nil {
+			return 0, err
+		}
+
+		total += amount
+	}
+
+	return RoundAmount(total), nil
+}
+
+// Check contrôle d'un nouveau montant au regard de la consommation courante
+func (l *LimitModel) Check(usage LimitUsage, amount float64) error {
+	switch {
+	case l.MaxAmount > 0 && amount > l.MaxAmount:
+		return l.exceeded(LIMIT_AMOUNT_EXCEEDED, fmt.Sprintf("montant maximum par transaction dépassé (%v)", l.MaxAmount), usage)
+
+	case l.DailyAmount > 0 && usage.DailyAmount+amount > l.DailyAmount:
+		return l.exceeded(LIMIT_DAILY_EXCEEDED, fmt.Sprintf("plafond journalier dépassé (%v)", l.DailyAmount), usage)
+
+	case l.MonthlyAmount > 0 && usage.MonthlyAmount+amount > l.MonthlyAmount:
+		return l.exceeded(LIMIT_MONTHLY_EXCEEDED, fmt.Sprintf("plafond mensuel dépassé (%v)", l.MonthlyAmount), usage)
+
+	case l.HourlyCount > 0 && usage.HourlyCount+1 > l.HourlyCount:
+		return l.exceeded(LIMIT_HOURLY_EXCEEDED, fmt.Sprintf("nombre de transactions par heure dépassé (%v)", l.HourlyCount), usage)
+	}
+
+	return nil
+}
+
+func (l *LimitModel) exceeded(code string, message string, usage LimitUsage) error {
+	return ResErrorAPI{
+		ErrorAPI{
+			Code:    code,
+			Message: message,
+			Data: map[string]interface{}{
+				"limit_id": l.ID,
+				"scope":    l.Scope,
+				"usage":    usage,
+			},
+		},
+	}
+}
+
+// checkLimits contrôle des limites actives à la création d'une transaction,
+// les limites concernées sont verrouillées jusqu'à la fin de la transaction de base de donnée
+// afin que deux créations simultanées ne dépassent pas ensemble un plafond
+func checkLimits(tx *gorm.DB, t *TransactionModel) error {
+	scopeIds := map[string]string{
+		LIMIT_SCOPE_SERVICE:  t.ServiceId,
+		LIMIT_SCOPE_PROVIDER: t.ProviderId,
+		LIMIT_SCOPE_PAYER:    t.PayerPhone,
+	}
+
+	now := time.Now()
+	for _, scope := range []string{LIMIT_SCOPE_SERVICE, LIMIT_SCOPE_PROVIDER, LIMIT_SCOPE_PAYER} {
+		scopeId := scopeIds[scope]
+		if scopeId == "" {
+			continue
+		}
+
+		limits := []LimitModel{}
+		result := tx.
+			Where("scope = ? AND active = ? AND mode_live = ?", scope, true, t.ModeLive).
+			Where("scope_id = ? OR scope_id = ?", scopeId, "").
+			Order("id asc").
+			Find(&limits)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for _, limit := range limits {
+			// Verrou de la ligne, portable sur tous les moteurs
+			result := tx.Model(&LimitModel{}).Where("id = ?", limit.ID).UpdateColumn("updated_at", gorm.Expr("updated_at"))
+			if result.Error != nil {
+				return result.Error
+			}
+
+			// Les montants sont comparés dans la devise de la limite
+			currency := limit.Currency
+			if currency == "" {
+				currency = t.SettlementCurrency
+			}
+
+			amount, err := ConvertAmount(tx, t.SettlementAmount, t.SettlementCurrency, currency, now)
+			if err != nil {
+				return err
+			}
+
+			usage, err := limit.Usage(tx, scopeId, currency, now)
+			if err != nil {
+				return err
+			}
+
+			if err := limit.Check(*usage, amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Etats de l'inscription d'une boutique, seules les boutiques APPROVED acceptent les transactions live
+const (
+	ONBOARDING_DRAFT     = "DRAFT"
+	ONBOARDING_SUBMITTED = "SUBMITTED"
+	ONBOARDING_APPROVED  = "APPROVED"
+	ONBOARDING_SUSPENDED = "SUSPENDED"
+)
+
+// Types de pièces KYC
+const (
+	KYC_ID_CARD               = "ID_CARD"               // Pièce d'identité du dirigeant
+	KYC_BUSINESS_REGISTRATION = "BUSINESS_REGISTRATION" // Registre du commerce
+	KYC_TAX_CERTIFICATE       = "TAX_CERTIFICATE"       // Attestation fiscale
+	KYC_PROOF_OF_ADDRESS      = "PROOF_OF_ADDRESS"      // Justificatif de domicile
+	KYC_BANK_DETAILS          = "BANK_DETAILS"          // Relevé d'identité bancaire
+	KYC_OTHER                 = "OTHER"
+)
+
+// Pièces obligatoires pour soumettre l'inscription
+var KycRequiredKinds = []string{KYC_ID_CARD, KYC_BUSINESS_REGISTRATION}
+
+// Evénements de l'inscription
+const (
+	EVENT_ONBOARDING_SUBMITTED = "service.onboarding_submitted"
+	EVENT_ONBOARDING_APPROVED  = "service.onboarding_approved"
+	EVENT_ONBOARDING_REJECTED  = "service.onboarding_rejected"
+	EVENT_ONBOARDING_SUSPENDED = "service.onboarding_suspended"
+)
+
+// Décisions possibles des gestionnaires selon l'état, un rejet renvoie l'inscription en DRAFT
+var onboardingReviews = map[string][]string{
+	ONBOARDING_SUBMITTED: {ONBOARDING_APPROVED, ONBOARDING_DRAFT},
+	ONBOARDING_APPROVED:  {ONBOARDING_SUSPENDED},
+	ONBOARDING_SUSPENDED: {ONBOARDING_APPROVED},
+}
+
+// ServiceDocumentModel pièce KYC déposée pour l'inscription d'une boutique
+type ServiceDocumentModel struct {
+	Model
+
+	ServiceId   string `json:"service_id" form:"-" validate:"-" gorm:"index"`
+	Kind        string `json:"kind" form:"kind" validate:"required,oneof=ID_CARD BUSINESS_REGISTRATION TAX_CERTIFICATE PROOF_OF_ADDRESS BANK_DETAILS OTHER"`
+	FileName    string `json:"file_name" form:"-" validate:"-"`
+	ContentType string `json:"content_type" form:"-" validate:"-"`
+	Size        int64  `json:"size" form:"-" validate:"-"`
+	Path        string `json:"-" form:"-" validate:"-"` // Chemin relatif au dossier d'upload
+	Description string `json:"description,omitempty" form:"description" validate:"omitempty"`
+
+	UploadedById string `json:"uploaded_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (ServiceDocumentModel) TableName() string {
+	return "services_documents"
+}
+
+func (d *ServiceDocumentModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	d.ID = uuid.String()
+
+	return
+}
+
+// SubmitOnboarding soumission de l'inscription aux gestionnaires, les pièces obligatoires doivent être déposées
+func (s *ServiceModel) SubmitOnboarding(tx *gorm.DB, now time.Time) error {
+	if s.OnboardingState != ONBOARDING_DRAFT {
+		return fmt.Errorf("seule une inscription en brouillon peut être soumise")
+	}
+
+	kinds := []string{}
+	result := tx.Model(&ServiceDocumentModel{}).Where("service_id = ?", s.ID).Distinct().Pluck("kind", &kinds)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, kind := range KycRequiredKinds {
+		if !slices.Contains(kinds, kind) {
+			return fmt.Errorf("pièce obligatoire manquante %v", kind)
+		}
+	}
+
+	if err := s.setOnboarding(tx, ONBOARDING_SUBMITTED, map[string]interface{}{
+		"submitted_at":      now,
+		"onboarding_reason": "",
+	}); err != nil {
+		return err
+	}
+
+	s.SubmittedAt = &now
+	s.OnboardingReason = ""
+
+	return PublishEvent(tx, s.ID, EVENT_ONBOARDING_SUBMITTED, "service", s.ID, s)
+}
+
+// ReviewOnboarding décision d'un gestionnaire: approbation, rejet (DRAFT) ou suspension, motif obligatoire sauf approbation
+func (s *ServiceModel) ReviewOnboarding(tx *gorm.DB, state string, reason string, reviewerId string, now time.Time) error {
+	if !slices.Contains(onboardingReviews[s.OnboardingState], state) {
+		return fmt.Errorf("passage de %v à %v non autorisé", s.OnboardingState, state)
+	}
+
+	if state != ONBOARDING_APPROVED && reason == "" {
+		return fmt.Errorf("motif obligatoire")
+	}
+
+	eventType := EVENT_ONBOARDING_APPROVED
+	switch state {
+	case ONBOARDING_DRAFT:
+		eventType = EVENT_ONBOARDING_REJECTED
+
+	case ONBOARDING_SUSPENDED:
+		eventType = EVENT_ONBOARDING_SUSPENDED
+	}
+
+	if err := s.setOnboarding(tx, state, map[string]interface{}{
+		"reviewed_at":       now,
+		"reviewed_by":       reviewerId,
+		"onboarding_reason": reason,
+	}); err != nil {
+		return err
+	}
+
+	s.ReviewedAt = &now
+	s.ReviewedBy = reviewerId
+	s.OnboardingReason = reason
+
+	return PublishEvent(tx, s.ID, eventType, "service", s.ID, s)
+}
+
+// setOnboarding changement d'état conditionnel, évite deux décisions simultanées
+func (s *ServiceModel) setOnboarding(tx *gorm.DB, state string, updates map[string]interface{}) error {
+	updates["onboarding_state"] = state
+
+	result := tx.Model(&ServiceModel{}).
+		Where("id = ? AND onboarding_state = ?", s.ID, s.OnboardingState).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("inscription modifiée entre temps")
+	}
+
+	s.OnboardingState = state
+
+	return nil
+}
+
+// checkOnboarding les transactions live sont réservées aux boutiques approuvées, le sandbox reste ouvert
+func checkOnboarding(tx *gorm.DB, t *TransactionModel) error {
+	if !t.ModeLive {
+		return nil
+	}
+
+	service := ServiceModel{}
+	result := tx.Model(&ServiceModel{}).Select("id", "onboarding_state").Where("id = ?", t.ServiceId).Limit(1).Find(&service)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if service.OnboardingState != ONBOARDING_APPROVED {
+		return ResErrorAPI{ErrorAPI{
+			Code:    "SERVICE_NOT_APPROVED",
+			Message: "boutique non approuvée, seules les transactions sandbox sont acceptées",
+		}}
+	}
+
+	return nil
+}
@@ -1,27 +1,75 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 // PaginationModel n
 type PaginationModel struct {
-	Count     int64    `json:"count"`
-	Sorts     []string `json:"sorts,omitempty"`
-	Page      int      `json:"page,omitempty"`
-	PageCount int      `json:"page_count,omitempty"`
-	Query     string   `json:"query,omitempty"`
-	Limit     int      `json:"limit"`
-	Offset    int      `json:"offset,omitempty"`
+	Count      *int64   `json:"count,omitempty"` // Toujours présent sauf with-count=false
+	Sorts      []string `json:"sorts,omitempty"`
+	Page       int      `json:"page,omitempty"`
+	PageCount  int      `json:"page_count,omitempty"`
+	Query      string   `json:"query,omitempty"`
+	Limit      int      `json:"limit"`
+	Offset     int      `json:"offset,omitempty"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	PrevCursor string   `json:"prev_cursor,omitempty"`
 	// Contents  interface{} `json:"contents"`
 }
 
-// PaginationMid middleware de gestion de pagination
-func PaginationMid() func(next echo.HandlerFunc) echo.HandlerFunc {
+// Cursor position d'une page en pagination par curseur sur (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	Prev      bool      `json:"p,omitempty"` // Page précédente
+}
+
+// CursorKey position de l'enregistrement pour la pagination par curseur
+func (base Model) CursorKey() Cursor {
+	return Cursor{
+		CreatedAt: base.CreatedAt,
+		ID:        base.ID,
+	}
+}
+
+// Encode valeur opaque transmise au client
+func (cur Cursor) Encode() string {
+	data, _ := json.Marshal(cur)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor lecture d'un curseur transmis par le client, vide pour la première page
+func DecodeCursor(value string) (*Cursor, error) {
+	cursor := Cursor{}
+	if value == "" {
+		return &cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("curseur invalide")
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("curseur invalide")
+	}
+
+	return &cursor, nil
+}
+
+// PaginationMid middleware de gestion de pagination, seules les colonnes
+// de sortables sont acceptées dans le paramètre sorts
+func PaginationMid(sortables ...string) func(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			resp, ok := c.Get("RESP").(*ResponseAPI[interface{}])
@@ -38,13 +86,17 @@ func PaginationMid() func(next echo.HandlerFunc) echo.HandlerFunc {
 				limit, _ = strconv.Atoi(c.QueryParam("limit"))
 			}
 
+			if limit <= 0 {
+				limit = 10
+			}
+
 			// Page
 			if c.QueryParam("page") != "" {
 				offset, _ = strconv.Atoi(c.QueryParam("page"))
 			}
 
 			// Sorts
-			orders := orderString(c.QueryParam("sorts"))
+			orders := orderString(c.QueryParam("sorts"), sortables)
 
 			if offset <= 0 {
 				offset = 1
@@ -52,10 +104,28 @@ func PaginationMid() func(next echo.HandlerFunc) echo.HandlerFunc {
 
 			offset = limit * (offset - 1)
 
+			// Pagination par curseur si le paramètre cursor est présent (vide pour la première page)
+			var cursor *Cursor
+			if c.QueryParams().Has("cursor") {
+				var err error
+				cursor, err = DecodeCursor(c.QueryParam("cursor"))
+				if err != nil {
+					return resp.SendError(c, err.Error(), TransformErr(err))
+				}
+			}
+
+			// Le total est toujours calculé, sauf demande explicite with-count=false
+			withCount := true
+			if value, err := strconv.ParseBool(c.QueryParam("with-count")); err == nil && !value {
+				withCount = false
+			}
+
 			c.Set("LIMIT", limit)
 			c.Set("OFFSET", offset)
 			c.Set("QUERY", query)
 			c.Set("ORDERS", orders)
+			c.Set("CURSOR", cursor)
+			c.Set("WITH_COUNT", withCount)
 
 			if err := next(c); err != nil {
 				return resp.SendError(c, "Une erreur c'est produite", []ErrorAPI{
@@ -81,16 +151,88 @@ func PaginationJson(pageCount int64, limit int64, offset int64, query string) ma
 	}
 }
 
-func orderString(sortQuery string) []string {
+func orderString(sortQuery string, sortables []string) []string {
 	sorts := strings.Split(sortQuery, ",")
 	orders := []string{}
 	for _, sort := range sorts {
 		srt := strings.Split(strings.Trim(sort, " "), " ")
 
-		if len(srt) == 2 && (strings.ToLower(srt[1]) == "asc" || strings.ToLower(srt[1]) == "desc") {
+		if len(srt) == 2 && isSortable(srt[0], sortables) && (strings.ToLower(srt[1]) == "asc" || strings.ToLower(srt[1]) == "desc") {
 			orders = append(orders, fmt.Sprintf("%v %v", string(srt[0]), strings.ToLower(string(srt[1]))))
 		}
 	}
 
 	return orders
 }
+
+func isSortable(column string, sortables []string) bool {
+	for _, sortable := range sortables {
+		if column == sortable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CursorQuery application du curseur à la requête, les tris sont remplacés par (created_at, id).
+// Un enregistrement supplémentaire est chargé pour détecter l'existence d'une page suivante
+func CursorQuery(reqDb *gorm.DB, cursor *Cursor, limit int) *gorm.DB {
+	if cursor.ID == "" {
+		return reqDb.Order("created_at desc").Order("id desc").Limit(limit + 1)
+	}
+
+	if cursor.Prev {
+		return reqDb.
+			Where("(created_at > ? OR (created_at = ? AND id > ?))", cursor.CreatedAt, cursor.CreatedAt, cursor.ID).
+			Order("created_at asc").
+			Order("id asc").
+			Limit(limit + 1)
+	}
+
+	return reqDb.
+		Where("(created_at < ? OR (created_at = ? AND id < ?))", cursor.CreatedAt, cursor.CreatedAt, cursor.ID).
+		Order("created_at desc").
+		Order("id desc").
+		Limit(limit + 1)
+}
+
+// CursorResult mise en ordre des enregistrements chargés par CursorQuery et calcul des curseurs suivant et précédent
+func CursorResult[T interface{ CursorKey() Cursor }](items []T, cursor *Cursor, limit int) ([]T, string, string) {
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	if cursor.Prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) == 0 {
+		return items, "", ""
+	}
+
+	first := items[0].CursorKey()
+	first.Prev = true
+	last := items[len(items)-1].CursorKey()
+
+	nextCursor, prevCursor := "", ""
+
+	if cursor.Prev {
+		nextCursor = last.Encode()
+		if hasMore {
+			prevCursor = first.Encode()
+		}
+	} else {
+		if hasMore {
+			nextCursor = last.Encode()
+		}
+		if cursor.ID != "" {
+			prevCursor = first.Encode()
+		}
+	}
+
+	return items, nextCursor, prevCursor
+}
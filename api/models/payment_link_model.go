@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentLinkModel lien de paiement d'une boutique, payé via la page de paiement hébergée
+type PaymentLinkModel struct {
+	Model
+
+	// Service
+	ServiceId string        `json:"service_id" form:"service_id" validate:"required" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
+
+	Code        string     `json:"code" form:"-" validate:"-" gorm:"unique"` // Identifiant public du lien
+	Amount      float64    `json:"amount" form:"amount" validate:"required,gt=0"`
+	Currency    string     `json:"currency" form:"currency" validate:"required"`
+	Description string     `json:"description" form:"description" validate:"omitempty"`
+	ModeLive    bool       `json:"mode_live" form:"mode_live" validate:"-"`
+	SingleUse   bool       `json:"single_use" form:"single_use" validate:"-"` // Un seul paiement réussi accepté
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" form:"expires_at" validate:"-"`
+	Active      bool       `json:"active" form:"active" validate:"-"`
+
+	// Redirection vers le marchand, adresses http ou https uniquement
+	SuccessUrl string `json:"success_url,omitempty" form:"success_url" validate:"omitempty,http_url"`
+	CancelUrl  string `json:"cancel_url,omitempty" form:"cancel_url" validate:"omitempty,http_url"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+
+	CheckoutUrl string `json:"checkout_url,omitempty" form:"-" validate:"-" gorm:"-"` // Adresse de la page de paiement
+}
+
+// TableName changement du nom de la table
+func (PaymentLinkModel) TableName() string {
+	return "payments_links"
+}
+
+func (p *PaymentLinkModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	p.ID = uuid.String()
+
+	// Le code public est dérivé d'un second identifiant aléatoire
+	code, err := newLinkCode()
+	if err != nil {
+		return err
+	}
+
+	p.Code = code
+
+	return
+}
+
+// SafeRedirects retrait des redirections dont le schéma n'est pas http ou https
+// (javascript:, data:...), enregistrées avant le contrôle du formulaire
+func (p *PaymentLinkModel) SafeRedirects() {
+	if !isHttpUrl(p.SuccessUrl) {
+		p.SuccessUrl = ""
+	}
+
+	if !isHttpUrl(p.CancelUrl) {
+		p.CancelUrl = ""
+	}
+}
+
+func isHttpUrl(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+
+	return (scheme == "http" || scheme == "https") && u.Host != ""
+}
+
+// IsExpired la date d'expiration du lien est dépassée
+func (p *PaymentLinkModel) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && now.After(*p.ExpiresAt)
+}
+
+// CheckUsable le lien peut encore être utilisé pour un paiement
+func (p *PaymentLinkModel) CheckUsable(tx *gorm.DB, now time.Time) error {
+	if !p.Active {
+		return fmt.Errorf("lien de paiement désactivé")
+	}
+
+	if p.IsExpired(now) {
+		return fmt.Errorf("lien de paiement expiré")
+	}
+
+	if !p.SingleUse {
+		return nil
+	}
+
+	// Un lien à usage unique est bloqué dès qu'un paiement est réussi ou en cours
+	var count int64
+	result := tx.Model(&TransactionModel{}).
+		Where("payment_link_id = ? AND operation_state IN (?)", p.ID, []string{TRANSACTION_PENDING, TRANSACTION_SUCCESS}).
+		Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if count > 0 {
+		return fmt.Errorf("lien de paiement déjà utilisé")
+	}
+
+	return nil
+}
+
+func newLinkCode() (string, error) {
+	code, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(code.String(), "-", ""), nil
+}
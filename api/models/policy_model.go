@@ -0,0 +1,284 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Permission droit nommé "ressource:action", contrôlé par UserModel.Can
+type Permission string
+
+// Permissions sur une boutique, accordées par le rôle du membre (ou son rôle personnalisé)
+// et à toutes les boutiques par le rôle de l'utilisateur
+const (
+	PERM_SERVICE_READ              Permission = "service:read"
+	PERM_SERVICE_UPDATE            Permission = "service:update"
+	PERM_SERVICE_DELETE            Permission = "service:delete"
+	PERM_SERVICE_KEYS_READ         Permission = "service:keys:read"
+	PERM_SERVICE_KEYS_ROTATE       Permission = "service:keys:rotate"
+	PERM_SERVICE_EVENTS_READ       Permission = "service:events:read"
+	PERM_SERVICE_ONBOARDING        Permission = "service:onboarding" // Pièces KYC et soumission de l'inscription
+	PERM_SERVICE_SETTLEMENT_READ   Permission = "service:settlement:read"
+	PERM_SERVICE_SETTLEMENT_UPDATE Permission = "service:settlement:update"
+	PERM_SERVICE_STATEMENT_EXPORT  Permission = "service:statement:export"
+	PERM_MEMBERS_READ              Permission = "members:read"
+	PERM_MEMBERS_MANAGE            Permission = "members:manage" // Ajout, rôle, retrait, invitations et rôles personnalisés
+	PERM_TRANSFERS_READ            Permission = "transfers:read"
+	PERM_TRANSFERS_CREATE          Permission = "transfers:create"
+	PERM_TRANSACTIONS_READ         Permission = "transactions:read"
+	PERM_TRANSACTIONS_CREATE       Permission = "transactions:create"
+	PERM_TRANSACTIONS_CANCEL       Permission = "transactions:cancel"
+	PERM_TRANSACTIONS_CAPTURE      Permission = "transactions:capture" // Capture et annulation d'une autorisation
+	PERM_TRANSACTIONS_REFUND       Permission = "transactions:refund"
+	PERM_PAYMENT_LINKS_READ        Permission = "payment_links:read"
+	PERM_PAYMENT_LINKS_MANAGE      Permission = "payment_links:manage"
+	PERM_SUBSCRIPTIONS_READ        Permission = "subscriptions:read"
+	PERM_SUBSCRIPTIONS_CREATE      Permission = "subscriptions:create"
+	PERM_SUBSCRIPTIONS_MANAGE      Permission = "subscriptions:manage" // Plans, pause, reprise et annulation
+	PERM_INSTALLMENTS_READ         Permission = "installments:read"
+	PERM_INSTALLMENTS_CREATE       Permission = "installments:create"
+	PERM_DISPUTES_READ             Permission = "disputes:read"
+	PERM_DISPUTES_EVIDENCE         Permission = "disputes:evidence"
+	PERM_SETTLEMENTS_READ          Permission = "settlements:read"
+	PERM_APPROVALS_READ            Permission = "approvals:read"
+	PERM_APPROVALS_DECIDE          Permission = "approvals:decide" // Politiques dont l'approbateur est SERVICE_ADMIN
+)
+
+// Permissions de la plateforme, accordées uniquement par le rôle de l'utilisateur
+const (
+	PERM_PLATFORM_ACT_AS             Permission = "platform:act-as" // Création pour le compte d'un autre utilisateur (auth_id)
+	PERM_PLATFORM_ONBOARDING_REVIEW  Permission = "platform:onboarding:review"
+	PERM_PLATFORM_USERS              Permission = "platform:users"
+	PERM_PLATFORM_USERS_ROLE         Permission = "platform:users:role"
+	PERM_PLATFORM_PROVIDERS          Permission = "platform:providers"
+	PERM_PLATFORM_SETTLEMENTS        Permission = "platform:settlements"
+	PERM_PLATFORM_DISPUTES           Permission = "platform:disputes"
+	PERM_PLATFORM_LIMITS             Permission = "platform:limits"
+	PERM_PLATFORM_FRAUD              Permission = "platform:fraud"
+	PERM_PLATFORM_RATES              Permission = "platform:rates"
+	PERM_PLATFORM_RECONCILIATIONS    Permission = "platform:reconciliations"
+	PERM_PLATFORM_APPROVALS          Permission = "platform:approvals" // Décisions USER_MANAGER et lecture des politiques
+	PERM_PLATFORM_APPROVALS_POLICIES Permission = "platform:approvals:policies"
+	PERM_PLATFORM_AUDIT              Permission = "platform:audit"
+)
+
+// Toutes les permissions
+const PERM_ALL Permission = "*"
+
+// Permissions des rôles d'une boutique, chaque rôle reprend celles du rôle inférieur
+var servicePermissions = map[ServiceRole][]Permission{
+	SERVICE_DEV: {
+		PERM_SERVICE_READ,
+		PERM_SERVICE_KEYS_READ,
+		PERM_SERVICE_EVENTS_READ,
+		PERM_MEMBERS_READ,
+		PERM_TRANSACTIONS_READ,
+		PERM_TRANSACTIONS_CREATE,
+		PERM_PAYMENT_LINKS_READ,
+		PERM_SUBSCRIPTIONS_READ,
+		PERM_SUBSCRIPTIONS_CREATE,
+		PERM_INSTALLMENTS_READ,
+		PERM_DISPUTES_READ,
+	},
+	SERVICE_MANAGER: {
+		PERM_SERVICE_UPDATE,
+		PERM_SERVICE_KEYS_ROTATE,
+		PERM_SERVICE_SETTLEMENT_READ,
+		PERM_SERVICE_STATEMENT_EXPORT,
+		PERM_TRANSFERS_READ,
+		PERM_TRANSACTIONS_CANCEL,
+		PERM_TRANSACTIONS_CAPTURE,
+		PERM_TRANSACTIONS_REFUND,
+		PERM_PAYMENT_LINKS_MANAGE,
+		PERM_SUBSCRIPTIONS_MANAGE,
+		PERM_INSTALLMENTS_CREATE,
+		PERM_DISPUTES_EVIDENCE,
+		PERM_SETTLEMENTS_READ,
+		PERM_APPROVALS_READ,
+	},
+	SERVICE_ADMIN: {
+		PERM_SERVICE_DELETE,
+		PERM_SERVICE_ONBOARDING,
+		PERM_SERVICE_SETTLEMENT_UPDATE,
+		PERM_MEMBERS_MANAGE,
+		PERM_TRANSFERS_CREATE,
+		PERM_APPROVALS_DECIDE,
+	},
+}
+
+// Permissions des rôles utilisateur, valables pour toutes les boutiques
+var userPermissions = map[UserRole][]Permission{
+	USER_MERCHANT: {},
+	USER_MANAGER: append(ServicePermissions(SERVICE_ADMIN),
+		PERM_PLATFORM_ACT_AS,
+		PERM_PLATFORM_ONBOARDING_REVIEW,
+		PERM_PLATFORM_USERS,
+		PERM_PLATFORM_PROVIDERS,
+		PERM_PLATFORM_SETTLEMENTS,
+		PERM_PLATFORM_DISPUTES,
+		PERM_PLATFORM_LIMITS,
+		PERM_PLATFORM_FRAUD,
+		PERM_PLATFORM_RATES,
+		PERM_PLATFORM_RECONCILIATIONS,
+		PERM_PLATFORM_APPROVALS,
+	),
+	USER_ADMIN: {PERM_ALL},
+}
+
+// ServicePermissions permissions d'un rôle de boutique, cumulées avec celles des rôles inférieurs
+func ServicePermissions(role ServiceRole) []Permission {
+	perms := []Permission{}
+	for r := SERVICE_DEV; r <= role; r++ {
+		perms = append(perms, servicePermissions[r]...)
+	}
+
+	return perms
+}
+
+// IsServicePermission permission attribuable sur une boutique (rôles personnalisés)
+func IsServicePermission(perm Permission) bool {
+	return slices.Contains(ServicePermissions(SERVICE_ADMIN), perm)
+}
+
+// ServiceRoleModel rôle personnalisé d'une boutique, ensemble de permissions de boutique
+type ServiceRoleModel struct {
+	Model
+
+	ServiceId   string      `json:"service_id" form:"-" validate:"-" gorm:"index:service_role_name,unique"`
+	Name        string      `json:"name" form:"name" validate:"required" gorm:"index:service_role_name,unique"`
+	Description string      `json:"description,omitempty" form:"description" validate:"omitempty"`
+	Permissions StringArray `json:"permissions" form:"permissions" validate:"required,min=1" gorm:"type:text"`
+}
+
+// TableName changement du nom de la table
+func (ServiceRoleModel) TableName() string {
+	return "services_roles"
+}
+
+func (r *ServiceRoleModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	return r.check()
+}
+
+func (r *ServiceRoleModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	return r.check()
+}
+
+// check seules les permissions de boutique sont attribuables, sans doublon
+func (r *ServiceRoleModel) check() error {
+	perms := StringArray{}
+	for _, perm := range r.Permissions {
+		if !IsServicePermission(Permission(perm)) {
+			return fmt.Errorf("permission inconnue %v", perm)
+		}
+
+		if !slices.Contains(perms, perm) {
+			perms = append(perms, perm)
+		}
+	}
+
+	r.Permissions = perms
+
+	return nil
+}
+
+// Remove suppression du rôle, ses membres reprennent les permissions de leur rôle
+func (r *ServiceRoleModel) Remove(tx *gorm.DB) error {
+	result := tx.Model(&ServicePermissionModel{}).Where("custom_role_id = ?", r.ID).Update("custom_role_id", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	result = tx.Where("id = ?", r.ID).Delete(&ServiceRoleModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("rôle personnalisé inexistant")
+	}
+
+	return nil
+}
+
+// Grants permissions accordées par la permission d'un membre: rôle personnalisé s'il est défini, rôle de la boutique sinon
+func (p *ServicePermissionModel) Grants() []Permission {
+	if p.CustomRoleId != nil {
+		// Rôle personnalisé non chargé: aucun droit
+		if p.CustomRole == nil {
+			return []Permission{}
+		}
+
+		perms := []Permission{}
+		for _, perm := range p.CustomRole.Permissions {
+			perms = append(perms, Permission(perm))
+		}
+
+		return perms
+	}
+
+	return ServicePermissions(p.Role)
+}
+
+// Can contrôle d'une permission, sur une boutique ou sur toute la plateforme si serviceId est vide
+func (u *UserModel) Can(perm Permission, serviceId string) bool {
+	if grants(userPermissions[u.Role], perm) {
+		return true
+	}
+
+	if serviceId == "" || strings.HasPrefix(string(perm), "platform:") {
+		return false
+	}
+
+	for i := range u.ServicePermissions {
+		if u.ServicePermissions[i].ServiceId == serviceId && grants(u.ServicePermissions[i].Grants(), perm) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authorize erreur si la permission n'est pas accordée
+func (u *UserModel) Authorize(perm Permission, serviceId string) error {
+	if !u.Can(perm, serviceId) {
+		return fmt.Errorf("permission non accordé, %v requis", perm)
+	}
+
+	return nil
+}
+
+// ServicesWith boutiques sur lesquelles la permission est accordée, all si elle l'est sur toute la plateforme
+func (u *UserModel) ServicesWith(perm Permission) (serviceIds []string, all bool) {
+	if grants(userPermissions[u.Role], perm) {
+		return nil, true
+	}
+
+	serviceIds = []string{}
+	for i := range u.ServicePermissions {
+		if grants(u.ServicePermissions[i].Grants(), perm) {
+			serviceIds = append(serviceIds, u.ServicePermissions[i].ServiceId)
+		}
+	}
+
+	return serviceIds, false
+}
+
+// PreloadGrants chargement des permissions nécessaires à Can
+func PreloadGrants(db *gorm.DB) *gorm.DB {
+	return db.Preload("ServicePermissions").Preload("ServicePermissions.CustomRole")
+}
+
+func grants(perms []Permission, perm Permission) bool {
+	return slices.Contains(perms, PERM_ALL) || slices.Contains(perms, perm)
+}
@@ -65,8 +65,13 @@ type ProviderModel struct {
 	PayUrl      string `json:"pay_url" form:"pay_url" validate:"required"`
 	PayCheckUrl string `json:"pay_check_url" form:"pay_check_url" validate:"required"`
 	HealthUrl   string `json:"health_url" form:"health_url" validate:"required"`
+	CancelUrl   string `json:"cancel_url,omitempty" form:"cancel_url" validate:"omitempty"`   // Vide si l'annulation n'est pas supportée
+	QrUrl       string `json:"qr_url,omitempty" form:"qr_url" validate:"omitempty"`           // Vide si le provider n'a pas de QR marchand
+	CaptureUrl  string `json:"capture_url,omitempty" form:"capture_url" validate:"omitempty"` // Vide si l'autorisation / capture n'est pas supportée
+	VoidUrl     string `json:"void_url,omitempty" form:"void_url" validate:"omitempty"`
 
 	SupportCountry StringArray `json:"support_country" form:"support_country" gorm:"type:text[]" validate:"required"` // CIV
+	Operator       string      `json:"operator,omitempty" form:"operator" validate:"omitempty" gorm:"index"`          // Opérateur mobile (ORANGE, MTN, MOOV)
 
 	Transactions []TransactionModel `json:"transactions,omitempty" form:"transactions" gorm:"foreignKey:ProviderId"`
 }
@@ -75,3 +80,29 @@ type ProviderModel struct {
 func (ProviderModel) TableName() string {
 	return "providers"
 }
+
+// SupportCancel le provider permet l'annulation d'une transaction en attente
+func (p *ProviderModel) SupportCancel() bool {
+	return p.CancelUrl != ""
+}
+
+// SupportCountryCode le provider opère dans le pays
+func (p *ProviderModel) SupportCountryCode(country string) bool {
+	for _, code := range p.SupportCountry {
+		if strings.EqualFold(strings.TrimSpace(code), country) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportCapture le provider permet la réservation des fonds puis leur capture ou libération
+func (p *ProviderModel) SupportCapture() bool {
+	return p.CaptureUrl != "" && p.VoidUrl != ""
+}
+
+// SupportQr le provider fournit un QR code marchand natif
+func (p *ProviderModel) SupportQr() bool {
+	return p.QrUrl != ""
+}
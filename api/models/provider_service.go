@@ -0,0 +1,22 @@
+package models
+
+// ProviderRequest contenu envoyé aux urls du provider
+type ProviderRequest struct {
+	TransactionId     string  `json:"transaction_id"`
+	ReferenceId       string  `json:"reference_id"`
+	ProviderReference string  `json:"provider_reference,omitempty"`
+	Amount            float64 `json:"amount"`
+	Currency          string  `json:"currency"`
+	OperationMode     string  `json:"operation_mode"`
+	ModeLive          bool    `json:"mode_live"`
+	PayerPhone        string  `json:"payer_phone,omitempty"`
+	Capture           *bool   `json:"capture,omitempty"` // false: réservation des fonds sans capture
+}
+
+// ProviderResponse réponse attendue des urls du provider
+type ProviderResponse struct {
+	State     string `json:"state"`                // PENDING, SUCCESS, CANCEL, FAIL
+	Message   string `json:"message"`              // Message de l'opérateur
+	Reference string `json:"reference"`            // Réference de l'opération chez l'opérateur
+	QrPayload string `json:"qr_payload,omitempty"` // Contenu du QR code marchand (url QR)
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+)
+
+// Etat d'un rapprochement
+const (
+	RECONCILIATION_OPEN     = "OPEN"
+	RECONCILIATION_RESOLVED = "RESOLVED"
+)
+
+// Statut d'une ligne de rapprochement
+const (
+	RECONCILIATION_MATCHED         = "MATCHED"         // Trouvée et conforme
+	RECONCILIATION_MISSING         = "MISSING"         // Transaction réussie absente du relevé
+	RECONCILIATION_EXTRA           = "EXTRA"           // Ligne du relevé sans transaction
+	RECONCILIATION_AMOUNT_MISMATCH = "AMOUNT_MISMATCH" // Montants différents
+	RECONCILIATION_STATE_MISMATCH  = "STATE_MISMATCH"  // Transaction non réussie présente dans le relevé
+)
+
+// Actions de résolution
+const (
+	RESOLUTION_ACKNOWLEDGE     = "ACKNOWLEDGE"     // Ecart accepté sans modification
+	RESOLUTION_LINK            = "LINK"            // Rattachement manuel d'une ligne à une transaction
+	RESOLUTION_APPLY_STATEMENT = "APPLY_STATEMENT" // L'état de la transaction est aligné sur le relevé
+)
+
+// ReconciliationMappingModel correspondance des colonnes du relevé d'un provider
+type ReconciliationMappingModel struct {
+	Model
+
+	ProviderId string `json:"provider_id" form:"provider_id" validate:"required" gorm:"unique"`
+
+	Delimiter       string `json:"delimiter" form:"delimiter" validate:"omitempty,len=1"`        // Séparateur (défaut: ,)
+	HasHeader       bool   `json:"has_header" form:"has_header" validate:"-"`                    // Colonnes désignées par nom si vrai, par index (0..n) sinon
+	ReferenceColumn string `json:"reference_column" form:"reference_column" validate:"required"` // Réference opérateur
+	AmountColumn    string `json:"amount_column" form:"amount_column" validate:"required"`
+	DateColumn      string `json:"date_column" form:"date_column" validate:"required"`
+	DateLayout      string `json:"date_layout" form:"date_layout" validate:"omitempty"` // Format go de la date (ex: 2006-01-02 15:04:05)
+	CurrencyColumn  string `json:"currency_column" form:"currency_column" validate:"omitempty"`
+}
+
+// TableName changement du nom de la table
+func (ReconciliationMappingModel) TableName() string {
+	return "reconciliations_mappings"
+}
+
+// ReconciliationModel rapport de rapprochement d'un relevé provider
+type ReconciliationModel struct {
+	Model
+
+	// Provider
+	ProviderId string         `json:"provider_id" form:"provider_id" validate:"required" gorm:"index"`
+	Provider   *ProviderModel `json:"provider,omitempty" form:"-" validate:"-"`
+
+	FileName    string    `json:"file_name" form:"-" validate:"-"`
+	PeriodStart time.Time `json:"period_start" form:"-" validate:"-"`
+	PeriodEnd   time.Time `json:"period_end" form:"-" validate:"-"`
+	State       string    `json:"state" form:"-" validate:"-" gorm:"index"` // OPEN, RESOLVED
+
+	LineCount       int64 `json:"line_count" form:"-" validate:"-"`
+	MatchedCount    int64 `json:"matched_count" form:"-" validate:"-"`
+	MissingCount    int64 `json:"missing_count" form:"-" validate:"-"`
+	ExtraCount      int64 `json:"extra_count" form:"-" validate:"-"`
+	MismatchCount   int64 `json:"mismatch_count" form:"-" validate:"-"`
+	UnresolvedCount int64 `json:"unresolved_count" form:"-" validate:"-"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+
+	Lines []ReconciliationLineModel `json:"lines,omitempty" form:"-" gorm:"foreignKey:ReconciliationId;constraint:OnDelete:CASCADE;"`
+}
+
+// TableName changement du nom de la table
+func (ReconciliationModel) TableName() string {
+	return "reconciliations"
+}
+
+// ReconciliationLineModel ligne du rapport de rapprochement
+type ReconciliationLineModel struct {
+	Model
+
+	ReconciliationId string `json:"reconciliation_id" form:"-" validate:"-" gorm:"index"`
+	TransactionId    string `json:"transaction_id,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	LineNumber        int       `json:"line_number,omitempty" form:"-" validate:"-"` // 0 pour une transaction absente du relevé
+	ProviderReference string    `json:"provider_reference" form:"-" validate:"-" gorm:"index"`
+	Amount            float64   `json:"amount" form:"-" validate:"-"` // Montant du relevé
+	TransactionAmount float64   `json:"transaction_amount" form:"-" validate:"-"`
+	Currency          string    `json:"currency" form:"-" validate:"-"`
+	OperationDate     time.Time `json:"operation_date" form:"-" validate:"-"`
+	Status            string    `json:"status" form:"-" validate:"-" gorm:"index"`
+
+	Resolution     string     `json:"resolution,omitempty" form:"-" validate:"-"`
+	ResolutionNote string     `json:"resolution_note,omitempty" form:"-" validate:"-"`
+	ResolvedById   string     `json:"resolved_by_id,omitempty" form:"-" validate:"-"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (ReconciliationLineModel) TableName() string {
+	return "reconciliations_lines"
+}
+
+// IsResolved ligne conforme ou écart traité
+func (l *ReconciliationLineModel) IsResolved() bool {
+	return l.Status == RECONCILIATION_MATCHED || l.Resolution != ""
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Mouvement du relevé d'un remboursement
+const LEDGER_REFUND = "REFUND"
+
+// Evénement d'un remboursement
+const EVENT_TRANSACTION_REFUNDED = "transaction.refunded"
+
+// RefundModel remboursement total ou partiel d'un paiement réussi à l'initiative de la boutique
+type RefundModel struct {
+	Model
+
+	// Service
+	ServiceId string `json:"service_id" form:"-" validate:"-" gorm:"index"`
+
+	// Transaction remboursée
+	TransactionId string `json:"transaction_id" form:"-" validate:"-" gorm:"index"`
+
+	Amount   float64 `json:"amount" form:"amount" validate:"gte=0"` // Montant remboursé, devise de la transaction
+	Currency string  `json:"currency" form:"-" validate:"-"`
+	Reason   string  `json:"reason" form:"reason" validate:"required"`
+	ModeLive bool    `json:"mode_live" form:"-" validate:"-"`
+
+	// Part débitée du solde de la boutique, hors parts reprises aux bénéficiaires
+	SettlementAmount   float64 `json:"settlement_amount" form:"-" validate:"-"`
+	SettlementCurrency string  `json:"settlement_currency,omitempty" form:"-" validate:"-"`
+
+	ApprovalId  string `json:"approval_id,omitempty" form:"-" validate:"-"`
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (RefundModel) TableName() string {
+	return "transactions_refunds"
+}
+
+func (r *RefundModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	r.ID = uuid.String()
+
+	return
+}
+
+// RefundTransaction remboursement d'un paiement réussi: les parts des bénéficiaires sont reprises
+// proportionnellement et le reste est débité du solde de la boutique (transaction live uniquement).
+// Le total remboursé, retenu ou rétrocédé sur la transaction ne peut dépasser son montant,
+// doit être appelé dans une transaction de base de donnée
+func RefundTransaction(tx *gorm.DB, refund *RefundModel) error {
+	// Verrou de la transaction, partagé avec l'ouverture des contestations
+	transaction := TransactionModel{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", refund.TransactionId).Limit(1).Find(&transaction)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("transaction inexistante")
+	}
+
+	if transaction.OperationState != TRANSACTION_SUCCESS || transaction.OperationMode != OPERATION_CREDIT {
+		return fmt.Errorf("seul un paiement réussi peut être remboursé")
+	}
+
+	if transaction.IsHeld() {
+		return fmt.Errorf("transaction retenue par le contrôle anti-fraude")
+	}
+
+	engaged, err := engagedAmount(tx, &transaction, true)
+	if err != nil {
+		return err
+	}
+
+	// Sans montant, le remboursement porte sur le reste de la transaction
+	if refund.Amount == 0 {
+		refund.Amount = RoundAmount(transaction.Amount - engaged)
+	}
+
+	if refund.Amount <= 0 || RoundAmount(engaged+refund.Amount) > transaction.Amount {
+		return fmt.Errorf("montant remboursé supérieur au montant remboursable de la transaction (%v)", RoundAmount(transaction.Amount-engaged))
+	}
+
+	share, err := RefundSplits(tx, &transaction, refund.Amount)
+	if err != nil {
+		return err
+	}
+
+	refund.ServiceId = transaction.ServiceId
+	refund.Currency = transaction.Currency
+	refund.ModeLive = transaction.ModeLive
+	refund.SettlementAmount, refund.SettlementCurrency = transaction.InSettlement(share)
+
+	if err := tx.Create(refund).Error; err != nil {
+		return err
+	}
+
+	result = tx.Model(&TransactionModel{}).
+		Where("id = ?", transaction.ID).
+		UpdateColumn("refunded_amount", gorm.Expr("refunded_amount + ?", refund.Amount))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if refund.ModeLive && refund.SettlementAmount != 0 {
+		err := PostLedger(tx, &LedgerEntryModel{
+			ServiceId:     refund.ServiceId,
+			TransactionId: refund.TransactionId,
+			Kind:          LEDGER_REFUND,
+			Label:         refund.Reason,
+			Amount:        -refund.SettlementAmount,
+			Currency:      refund.SettlementCurrency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return PublishEvent(tx, refund.ServiceId, EVENT_TRANSACTION_REFUNDED, "refund", refund.ID, refund)
+}
+
+// engagedAmount montant de la transaction déjà remboursé ou rétrocédé par les contestations perdues,
+// augmenté si demandé des montants retenus par les contestations en cours
+func engagedAmount(tx *gorm.DB, transaction *TransactionModel, withOpened bool) (float64, error) {
+	states := []string{DISPUTE_LOST}
+	if withOpened {
+		states = append(states, DISPUTE_OPENED, DISPUTE_EVIDENCE_REQUIRED)
+	}
+
+	var disputed float64
+	result := tx.Model(&DisputeModel{}).
+		Where("transaction_id = ? AND state IN ?", transaction.ID, states).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&disputed)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return RoundAmount(transaction.RefundedAmount + disputed), nil
+}
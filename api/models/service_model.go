@@ -1,6 +1,9 @@
 package models
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/gosimple/slug"
 	"gorm.io/gorm"
@@ -18,6 +21,22 @@ type ServiceModel struct {
 	SiteWeb     string `json:"site_web,omitempty" form:"site_web" validate:"omitempty" gorm:"index"`
 	Logo        string `json:"logo" form:"logo" validate:"omitempty"`
 	Country     string `json:"country,omitempty" form:"country" gorm:"index"`
+	WebhookUrl  string `json:"webhook_url,omitempty" form:"webhook_url" validate:"omitempty,url"` // Réception des événements de la boutique
+
+	// Logo envoyé, servi par adresses signées
+	LogoPath          string `json:"-" form:"-" validate:"-"`
+	LogoThumbnailPath string `json:"-" form:"-" validate:"-"`
+	LogoUrl           string `json:"logo_url,omitempty" form:"-" validate:"-" gorm:"-"`
+	LogoThumbnailUrl  string `json:"logo_thumbnail_url,omitempty" form:"-" validate:"-" gorm:"-"`
+
+	SettlementCurrency string `json:"settlement_currency,omitempty" form:"settlement_currency" validate:"omitempty,currency"` // Devise du solde, vide: devise de chaque transaction
+
+	// Inscription et revue KYC, les boutiques existantes sont approuvées
+	OnboardingState  string     `json:"onboarding_state" form:"-" validate:"-" gorm:"index;default:APPROVED"` // DRAFT, SUBMITTED, APPROVED, SUSPENDED
+	OnboardingReason string     `json:"onboarding_reason,omitempty" form:"-" validate:"-"`                    // Motif du rejet ou de la suspension
+	SubmittedAt      *time.Time `json:"submitted_at,omitempty" form:"-" validate:"-"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty" form:"-" validate:"-"`
+	ReviewedBy       string     `json:"reviewed_by,omitempty" form:"-" validate:"-"`
 
 	ClientId  string `json:"-" form:"client_id" validate:"required"`
 	ClientKey string `json:"-" form:"client_key" validate:"required"`
@@ -43,6 +62,10 @@ func (s *ServiceModel) BeforeCreate(tx *gorm.DB) (err error) {
 	s.ID = uuid.String()
 	s.NameSlug = slug.Make(s.Name)
 
+	if s.OnboardingState == "" {
+		s.OnboardingState = ONBOARDING_DRAFT
+	}
+
 	// Génération des clés
 	s.ClientId = RandStr(32)
 	s.ClientKey = RandStr(64)
@@ -52,6 +75,7 @@ func (s *ServiceModel) BeforeCreate(tx *gorm.DB) (err error) {
 
 func (s *ServiceModel) BeforeSave(tx *gorm.DB) (err error) {
 	s.NameSlug = slug.Make(s.Name)
+	s.SettlementCurrency = strings.ToUpper(s.SettlementCurrency)
 
 	return
 }
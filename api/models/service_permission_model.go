@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
 type ServicePermissionModel struct {
 	Model
 
@@ -12,9 +19,108 @@ type ServicePermissionModel struct {
 	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
 
 	Role ServiceRole `json:"role" form:"role" gorm:"type:text" validate:"required"`
+
+	// Rôle personnalisé, remplace les permissions du rôle
+	CustomRoleId *string           `json:"custom_role_id,omitempty" form:"-" validate:"-" gorm:"index"`
+	CustomRole   *ServiceRoleModel `json:"custom_role,omitempty" form:"-" validate:"-" gorm:"constraint:OnDelete:SET NULL;"`
 }
 
 // TableName changement du nom de la table
 func (ServicePermissionModel) TableName() string {
 	return "services_permissions"
 }
+
+// ChangeRole changement du rôle d'un membre et de son rôle personnalisé (nil pour le retirer),
+// la boutique garde au moins un SERVICE_ADMIN
+func (p *ServicePermissionModel) ChangeRole(tx *gorm.DB, role ServiceRole, customRoleId *string) error {
+	if p.Role == role && p.sameCustomRole(customRoleId) {
+		return fmt.Errorf("rôle inchangé")
+	}
+
+	if err := lockService(tx, p.ServiceId); err != nil {
+		return err
+	}
+
+	result := tx.Model(&ServicePermissionModel{}).Where("id = ?", p.ID).Updates(map[string]interface{}{
+		"role":           role,
+		"custom_role_id": customRoleId,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("permission inexistante")
+	}
+
+	if err := checkServiceAdmin(tx, p.ServiceId); err != nil {
+		return err
+	}
+
+	p.Role = role
+	p.CustomRoleId = customRoleId
+	p.CustomRole = nil
+
+	return nil
+}
+
+func (p *ServicePermissionModel) sameCustomRole(customRoleId *string) bool {
+	if p.CustomRoleId == nil || customRoleId == nil {
+		return p.CustomRoleId == nil && customRoleId == nil
+	}
+
+	return *p.CustomRoleId == *customRoleId
+}
+
+// Remove retrait d'un membre de la boutique, la boutique garde au moins un SERVICE_ADMIN
+func (p *ServicePermissionModel) Remove(tx *gorm.DB) error {
+	if err := lockService(tx, p.ServiceId); err != nil {
+		return err
+	}
+
+	result := tx.Where("id = ?", p.ID).Delete(&ServicePermissionModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("permission inexistante")
+	}
+
+	return checkServiceAdmin(tx, p.ServiceId)
+}
+
+// lockService verrou (FOR UPDATE) de la boutique jusqu'à la fin de la transaction,
+// les changements de membres d'une même boutique passent l'un après l'autre et
+// checkServiceAdmin compte les administrateurs après les modifications déjà validées
+func lockService(tx *gorm.DB, serviceId string) error {
+	service := ServiceModel{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", serviceId).Limit(1).Find(&service)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("boutique inexistante")
+	}
+
+	return nil
+}
+
+// checkServiceAdmin contrôle fait après modification, l'erreur annule la transaction.
+// Un membre avec un rôle personnalisé n'a pas les droits SERVICE_ADMIN et n'est pas compté
+func checkServiceAdmin(tx *gorm.DB, serviceId string) error {
+	var admins int64
+	result := tx.Model(&ServicePermissionModel{}).
+		Where("service_id = ? AND role = ? AND custom_role_id IS NULL", serviceId, SERVICE_ADMIN).
+		Count(&admins)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if admins == 0 {
+		return fmt.Errorf("la boutique doit garder au moins un administrateur")
+	}
+
+	return nil
+}
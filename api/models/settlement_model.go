@@ -0,0 +1,428 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Moyen de versement
+const (
+	SETTLEMENT_METHOD_BANK   = "BANK"
+	SETTLEMENT_METHOD_WALLET = "WALLET"
+)
+
+// Fréquence des règlements
+const (
+	SETTLEMENT_DAILY   = "DAILY"
+	SETTLEMENT_WEEKLY  = "WEEKLY"
+	SETTLEMENT_MONTHLY = "MONTHLY"
+)
+
+// Etat d'un règlement
+const (
+	SETTLEMENT_PENDING    = "PENDING"    // Calculé, en attente de versement
+	SETTLEMENT_PROCESSING = "PROCESSING" // Versement émis
+	SETTLEMENT_PAID       = "PAID"       // Versement confirmé
+	SETTLEMENT_FAILED     = "FAILED"     // Versement rejeté, le montant est rendu au solde
+)
+
+// Mouvements du relevé liés aux règlements
+const (
+	LEDGER_SETTLEMENT          = "SETTLEMENT"
+	LEDGER_SETTLEMENT_FEE      = "SETTLEMENT_FEE"
+	LEDGER_SETTLEMENT_REVERSAL = "SETTLEMENT_REVERSAL"
+)
+
+// Evénements des règlements
+const (
+	EVENT_SETTLEMENT_CREATED    = "settlement.created"
+	EVENT_SETTLEMENT_PROCESSING = "settlement.processing"
+	EVENT_SETTLEMENT_PAID       = "settlement.paid"
+	EVENT_SETTLEMENT_FAILED     = "settlement.failed"
+)
+
+// Transitions autorisées entre états
+var settlementTransitions = map[string][]string{
+	SETTLEMENT_PENDING:    {SETTLEMENT_PROCESSING, SETTLEMENT_PAID, SETTLEMENT_FAILED},
+	SETTLEMENT_PROCESSING: {SETTLEMENT_PAID, SETTLEMENT_FAILED},
+}
+
+// SettlementConfigModel configuration du versement du solde d'une boutique
+type SettlementConfigModel struct {
+	Model
+
+	ServiceId string `json:"service_id" form:"-" validate:"-" gorm:"uniqueIndex"`
+	Active    bool   `json:"active" form:"active" validate:"-"`
+
+	// Compte de destination
+	Method         string `json:"method" form:"method" validate:"required,oneof=BANK WALLET"` // BANK, WALLET
+	AccountName    string `json:"account_name" form:"account_name" validate:"required"`
+	BankName       string `json:"bank_name,omitempty" form:"bank_name" validate:"required_if=Method BANK"`
+	AccountNumber  string `json:"account_number,omitempty" form:"account_number" validate:"required_if=Method BANK"` // IBAN ou RIB
+	WalletPhone    string `json:"wallet_phone,omitempty" form:"wallet_phone" validate:"required_if=Method WALLET"`
+	WalletOperator string `json:"wallet_operator,omitempty" form:"wallet_operator" validate:"omitempty"`
+
+	// Calendrier
+	Schedule    string     `json:"schedule" form:"schedule" validate:"required,oneof=DAILY WEEKLY MONTHLY"` // DAILY, WEEKLY, MONTHLY
+	ScheduleDay int        `json:"schedule_day" form:"schedule_day" validate:"gte=0,lte=28"`                // WEEKLY: 0 (dimanche) à 6, MONTHLY: 1 à 28
+	NextRunAt   *time.Time `json:"next_run_at,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	// Montants
+	MinAmount      float64 `json:"min_amount" form:"min_amount" validate:"gte=0"`                   // Versement minimum, reporté en dessous
+	ReservePercent float64 `json:"reserve_percent" form:"reserve_percent" validate:"gte=0,lte=100"` // Part du solde conservée en réserve
+	FeeFixed       float64 `json:"fee_fixed" form:"fee_fixed" validate:"gte=0"`                     // Frais fixes par versement
+	FeePercent     float64 `json:"fee_percent" form:"fee_percent" validate:"gte=0,lte=100"`         // Frais proportionnels au montant versé
+}
+
+// SettlementModel versement du solde d'une boutique
+type SettlementModel struct {
+	Model
+
+	// Service
+	ServiceId string        `json:"service_id" form:"-" validate:"-" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
+
+	State     string    `json:"state" form:"-" validate:"-" gorm:"index"` // PENDING, PROCESSING, PAID, FAILED
+	Currency  string    `json:"currency" form:"-" validate:"-"`
+	PeriodEnd time.Time `json:"period_end" form:"-" validate:"-"` // Transactions incluses jusqu'à cette date
+
+	BalanceAmount      float64 `json:"balance_amount" form:"-" validate:"-"` // Solde au moment du calcul
+	ReserveAmount      float64 `json:"reserve_amount" form:"-" validate:"-"` // Conservé sur le solde
+	FeeAmount          float64 `json:"fee_amount" form:"-" validate:"-"`
+	NetAmount          float64 `json:"net_amount" form:"-" validate:"-"` // Versé à la boutique
+	TransactionsCount  int64   `json:"transactions_count" form:"-" validate:"-"`
+	TransactionsAmount float64 `json:"transactions_amount" form:"-" validate:"-"`
+
+	// Destination au moment du calcul
+	Method      string `json:"method" form:"-" validate:"-"`
+	Destination string `json:"destination" form:"-" validate:"-"`
+
+	PaymentReference string     `json:"payment_reference,omitempty" form:"-" validate:"-"`
+	FailureReason    string     `json:"failure_reason,omitempty" form:"-" validate:"-"`
+	PaidAt           *time.Time `json:"paid_at,omitempty" form:"-" validate:"-"`
+
+	Items []SettlementItemModel `json:"items,omitempty" form:"-" gorm:"foreignKey:SettlementId;constraint:OnDelete:CASCADE;"`
+}
+
+// SettlementItemModel transaction incluse dans un règlement
+type SettlementItemModel struct {
+	Model
+
+	SettlementId  string  `json:"settlement_id" form:"-" validate:"-" gorm:"index"`
+	TransactionId string  `json:"transaction_id" form:"-" validate:"-" gorm:"index"`
+	ReferenceId   string  `json:"reference_id" form:"-" validate:"-"`
+	OperationMode string  `json:"operation_mode" form:"-" validate:"-"`
+	Amount        float64 `json:"amount" form:"-" validate:"-"` // Montant signé dans la devise du règlement
+	Currency      string  `json:"currency" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (SettlementConfigModel) TableName() string {
+	return "settlements_configs"
+}
+
+// TableName changement du nom de la table
+func (SettlementModel) TableName() string {
+	return "settlements"
+}
+
+// TableName changement du nom de la table
+func (SettlementItemModel) TableName() string {
+	return "settlements_items"
+}
+
+func (s *SettlementConfigModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	s.ID = uuid.String()
+
+	return
+}
+
+func (s *SettlementModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	s.ID = uuid.String()
+
+	if s.State == "" {
+		s.State = SETTLEMENT_PENDING
+	}
+
+	return
+}
+
+func (s *SettlementItemModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	s.ID = uuid.String()
+
+	return
+}
+
+// NextRun prochaine date de règlement après from, à minuit
+func (s *SettlementConfigModel) NextRun(from time.Time) time.Time {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	switch s.Schedule {
+	case SETTLEMENT_WEEKLY:
+		next := day.AddDate(0, 0, (s.ScheduleDay-int(day.Weekday())+7)%7)
+		if !next.After(from) {
+			next = next.AddDate(0, 0, 7)
+		}
+
+		return next
+
+	case SETTLEMENT_MONTHLY:
+		monthDay := s.ScheduleDay
+		if monthDay < 1 {
+			monthDay = 1
+		}
+
+		next := time.Date(day.Year(), day.Month(), monthDay, 0, 0, 0, 0, day.Location())
+		if !next.After(from) {
+			next = next.AddDate(0, 1, 0)
+		}
+
+		return next
+
+	default:
+		return day.AddDate(0, 0, 1)
+	}
+}
+
+// Destination libellé du compte de versement
+func (s *SettlementConfigModel) Destination() string {
+	if s.Method == SETTLEMENT_METHOD_WALLET {
+		return fmt.Sprintf("%v %v (%v)", s.WalletOperator, s.WalletPhone, s.AccountName)
+	}
+
+	return fmt.Sprintf("%v %v (%v)", s.BankName, s.AccountNumber, s.AccountName)
+}
+
+// IsFinal le règlement est confirmé ou rejeté
+func (s *SettlementModel) IsFinal() bool {
+	return s.State == SETTLEMENT_PAID || s.State == SETTLEMENT_FAILED
+}
+
+// CreateSettlement calcul du versement du solde de la boutique et retrait du relevé,
+// les transactions live réussies non encore réglées y sont rattachées.
+// Aucun règlement n'est créé (nil) si le montant versé est inférieur au minimum.
+// Doit être appelé dans une transaction de base de donnée
+func CreateSettlement(tx *gorm.DB, config SettlementConfigModel, now time.Time) (*SettlementModel, error) {
+	// Verrou sur la boutique le temps du calcul
+	result := tx.Model(&ServiceModel{}).Where("id = ?", config.ServiceId).UpdateColumn("updated_at", gorm.Expr("updated_at"))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	service := ServiceModel{}
+	result = tx.Where("id = ?", config.ServiceId).Limit(1).Find(&service)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("boutique introuvable")
+	}
+
+	if service.SettlementCurrency == "" {
+		return nil, fmt.Errorf("devise de règlement de la boutique non définie")
+	}
+
+	var count int64
+	result = tx.Model(&SettlementModel{}).
+		Where("service_id = ? AND state IN (?)", service.ID, []string{SETTLEMENT_PENDING, SETTLEMENT_PROCESSING}).
+		Count(&count)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if count > 0 {
+		return nil, fmt.Errorf("un règlement est déjà en cours pour cette boutique")
+	}
+
+	settlement := SettlementModel{
+		ServiceId:     service.ID,
+		State:         SETTLEMENT_PENDING,
+		Currency:      service.SettlementCurrency,
+		PeriodEnd:     now,
+		BalanceAmount: service.CurrentAmount,
+		Method:        config.Method,
+		Destination:   config.Destination(),
+	}
+
+	// La réserve reste sur le solde et couvre les contestations et annulations
+	settlement.ReserveAmount = RoundAmount(settlement.BalanceAmount * config.ReservePercent / 100)
+
+	payable := settlement.BalanceAmount - settlement.ReserveAmount
+	settlement.FeeAmount = RoundAmount(config.FeeFixed + payable*config.FeePercent/100)
+	settlement.NetAmount = RoundAmount(payable - settlement.FeeAmount)
+
+	if settlement.NetAmount <= 0 || settlement.NetAmount < config.MinAmount {
+		return nil, nil
+	}
+
+	if err := tx.Omit("Items").Create(&settlement).Error; err != nil {
+		return nil, err
+	}
+
+	// Transactions réussies non encore réglées
+	transactions := []TransactionModel{}
+	result = tx.Model(&TransactionModel{}).
+		Where("service_id = ? AND mode_live = ? AND operation_state = ? AND created_at <= ?", service.ID, true, TRANSACTION_SUCCESS, now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM settlements_items
+			JOIN settlements ON settlements.id = settlements_items.settlement_id
+			WHERE settlements_items.transaction_id = transactions.id AND settlements.state <> ?
+		)`, SETTLEMENT_FAILED).
+		FindInBatches(&transactions, 500, func(batchTx *gorm.DB, batch int) error {
+			items := make([]SettlementItemModel, 0, len(transactions))
+			for _, transaction := range transactions {
+				amount, currency := transaction.InSettlement(transaction.Amount)
+				if transaction.OperationMode == OPERATION_DEBIT {
+					amount = -amount
+				}
+
+				items = append(items, SettlementItemModel{
+					SettlementId:  settlement.ID,
+					TransactionId: transaction.ID,
+					ReferenceId:   transaction.ReferenceId,
+					OperationMode: transaction.OperationMode,
+					Amount:        amount,
+					Currency:      currency,
+				})
+
+				settlement.TransactionsCount++
+				settlement.TransactionsAmount += amount
+			}
+
+			return tx.Create(&items).Error
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	settlement.TransactionsAmount = RoundAmount(settlement.TransactionsAmount)
+
+	result = tx.Model(&SettlementModel{}).
+		Where("id = ?", settlement.ID).
+		Updates(map[string]interface{}{
+			"transactions_count":  settlement.TransactionsCount,
+			"transactions_amount": settlement.TransactionsAmount,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := settlement.postLedger(tx, LEDGER_SETTLEMENT, -settlement.NetAmount); err != nil {
+		return nil, err
+	}
+
+	if err := settlement.postLedger(tx, LEDGER_SETTLEMENT_FEE, -settlement.FeeAmount); err != nil {
+		return nil, err
+	}
+
+	if err := PublishEvent(tx, settlement.ServiceId, EVENT_SETTLEMENT_CREATED, "settlement", settlement.ID, settlement); err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// SetState avancement du versement, un versement rejeté rend le montant au solde,
+// ses transactions sont reprises au prochain règlement
+func (s *SettlementModel) SetState(tx *gorm.DB, state string, reference string, reason string, now time.Time) error {
+	allowed := false
+	for _, next := range settlementTransitions[s.State] {
+		if next == state {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("passage de %v à %v non autorisé", s.State, state)
+	}
+
+	updates := map[string]interface{}{
+		"state": state,
+	}
+
+	if reference != "" {
+		updates["payment_reference"] = reference
+	}
+
+	switch state {
+	case SETTLEMENT_PAID:
+		updates["paid_at"] = now
+
+	case SETTLEMENT_FAILED:
+		if reason == "" {
+			return fmt.Errorf("motif du rejet obligatoire")
+		}
+
+		updates["failure_reason"] = reason
+	}
+
+	// Le changement d'état conditionnel évite un double traitement
+	result := tx.Model(&SettlementModel{}).
+		Where("id = ? AND state = ?", s.ID, s.State).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("règlement modifié entre temps")
+	}
+
+	s.State = state
+	if reference != "" {
+		s.PaymentReference = reference
+	}
+
+	eventType := EVENT_SETTLEMENT_PROCESSING
+
+	switch state {
+	case SETTLEMENT_PAID:
+		eventType = EVENT_SETTLEMENT_PAID
+		s.PaidAt = &now
+
+	case SETTLEMENT_FAILED:
+		eventType = EVENT_SETTLEMENT_FAILED
+		s.FailureReason = reason
+
+		if err := s.postLedger(tx, LEDGER_SETTLEMENT_REVERSAL, s.NetAmount+s.FeeAmount); err != nil {
+			return err
+		}
+	}
+
+	return PublishEvent(tx, s.ServiceId, eventType, "settlement", s.ID, s)
+}
+
+// postLedger mouvement sur le solde de la boutique
+func (s *SettlementModel) postLedger(tx *gorm.DB, kind string, amount float64) error {
+	if amount == 0 {
+		return nil
+	}
+
+	return PostLedger(tx, &LedgerEntryModel{
+		ServiceId: s.ServiceId,
+		Kind:      kind,
+		Label:     fmt.Sprintf("Règlement %v", s.ID),
+		Amount:    amount,
+		Currency:  s.Currency,
+	})
+}
@@ -0,0 +1,292 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Type de part
+const (
+	SPLIT_FIXED   = "FIXED"   // Montant fixe dans la devise de la transaction
+	SPLIT_PERCENT = "PERCENT" // Pourcentage du montant de la transaction
+)
+
+// Mouvements du relevé liés aux parts
+const (
+	LEDGER_SPLIT          = "SPLIT"
+	LEDGER_SPLIT_REVERSAL = "SPLIT_REVERSAL"
+)
+
+// TransactionSplitModel part d'un paiement reversée à une autre boutique (commission d'un courtier par exemple),
+// le reste du montant revient à la boutique de la transaction
+type TransactionSplitModel struct {
+	Model
+
+	TransactionId string `json:"transaction_id" form:"-" validate:"-" gorm:"index"`
+
+	// Boutique bénéficiaire
+	ServiceId string        `json:"service_id" form:"service_id" validate:"required" gorm:"index"`
+	Service   *ServiceModel `json:"service,omitempty" form:"-" validate:"-"`
+
+	Kind        string  `json:"kind" form:"kind" validate:"required,oneof=FIXED PERCENT"` // FIXED, PERCENT
+	Value       float64 `json:"value" form:"value" validate:"required,gt=0"`
+	Description string  `json:"description,omitempty" form:"description" validate:"omitempty"`
+
+	Amount             float64 `json:"amount" form:"-" validate:"-"` // Part dans la devise de la transaction
+	SettlementAmount   float64 `json:"settlement_amount" form:"-" validate:"-"`
+	SettlementCurrency string  `json:"settlement_currency" form:"-" validate:"-"`
+	RefundedAmount     float64 `json:"refunded_amount" form:"-" validate:"-"` // Part déjà reprise, devise de la transaction
+}
+
+// TableName changement du nom de la table
+func (TransactionSplitModel) TableName() string {
+	return "transactions_splits"
+}
+
+func (s *TransactionSplitModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	s.ID = uuid.String()
+
+	return
+}
+
+// SplitsAmount total des parts dans la devise de la transaction
+func SplitsAmount(splits []TransactionSplitModel) float64 {
+	total := 0.0
+	for _, split := range splits {
+		total += split.Amount
+	}
+
+	return RoundAmount(total)
+}
+
+// computeSplitAmounts calcul des parts sur le montant de la transaction, le total ne peut dépasser le montant
+func computeSplitAmounts(splits []TransactionSplitModel, amount float64) error {
+	for i := range splits {
+		if splits[i].Kind == SPLIT_PERCENT {
+			splits[i].Amount = RoundAmount(amount * splits[i].Value / 100)
+		} else {
+			splits[i].Amount = splits[i].Value
+		}
+	}
+
+	if total := SplitsAmount(splits); total > amount {
+		return ResErrorAPI{ErrorAPI{
+			Code:    "SPLIT_EXCEEDS_AMOUNT",
+			Message: fmt.Sprintf("total des parts (%.2f) supérieur au montant de la transaction (%.2f)", total, amount),
+		}}
+	}
+
+	return nil
+}
+
+// prepareSplits contrôle des parts et conversion dans la devise de règlement de chaque bénéficiaire
+func prepareSplits(tx *gorm.DB, t *TransactionModel) error {
+	if len(t.Splits) == 0 {
+		return nil
+	}
+
+	if t.OperationMode != OPERATION_CREDIT {
+		return fmt.Errorf("seul un paiement (CREDIT) peut être partagé")
+	}
+
+	percent := 0.0
+	seen := map[string]bool{}
+
+	for i := range t.Splits {
+		split := &t.Splits[i]
+
+		if split.ServiceId == t.ServiceId {
+			return fmt.Errorf("la boutique de la transaction ne peut être bénéficiaire d'une part")
+		}
+
+		if seen[split.ServiceId] {
+			return fmt.Errorf("boutique %v présente dans plusieurs parts", split.ServiceId)
+		}
+
+		seen[split.ServiceId] = true
+
+		if split.Value <= 0 {
+			return fmt.Errorf("valeur de part invalide %v", split.Value)
+		}
+
+		switch split.Kind {
+		case SPLIT_PERCENT:
+			percent += split.Value
+
+		case SPLIT_FIXED:
+
+		default:
+			return fmt.Errorf("type de part inconnu %v, FIXED ou PERCENT attendu", split.Kind)
+		}
+
+		beneficiary := ServiceModel{}
+		result := tx.Model(&ServiceModel{}).Select("id", "settlement_currency").Where("id = ?", split.ServiceId).Limit(1).Find(&beneficiary)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("boutique bénéficiaire introuvable %v", split.ServiceId)
+		}
+
+		split.SettlementCurrency = beneficiary.SettlementCurrency
+		if split.SettlementCurrency == "" {
+			split.SettlementCurrency = t.Currency
+		}
+	}
+
+	if percent > 100 {
+		return fmt.Errorf("total des pourcentages supérieur à 100")
+	}
+
+	if err := computeSplitAmounts(t.Splits, t.Amount); err != nil {
+		return err
+	}
+
+	return convertSplits(tx, t, t.Splits)
+}
+
+// convertSplits montant de chaque part dans la devise de règlement de son bénéficiaire
+func convertSplits(tx *gorm.DB, t *TransactionModel, splits []TransactionSplitModel) error {
+	for i := range splits {
+		rate, err := FindExchangeRate(tx, t.Currency, splits[i].SettlementCurrency, time.Now())
+		if err != nil {
+			return err
+		}
+
+		splits[i].SettlementAmount = RoundAmount(splits[i].Amount * rate)
+	}
+
+	return nil
+}
+
+// transactionSplits parts enregistrées d'une transaction
+func transactionSplits(tx *gorm.DB, transactionId string) ([]TransactionSplitModel, error) {
+	splits := []TransactionSplitModel{}
+	result := tx.Where("transaction_id = ?", transactionId).Order("created_at asc").Find(&splits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return splits, nil
+}
+
+// resizeSplits nouveau calcul des parts après une capture partielle
+func resizeSplits(tx *gorm.DB, t *TransactionModel, amount float64) error {
+	splits, err := transactionSplits(tx, t.ID)
+	if err != nil || len(splits) == 0 {
+		return err
+	}
+
+	if err := computeSplitAmounts(splits, amount); err != nil {
+		return err
+	}
+
+	if err := convertSplits(tx, t, splits); err != nil {
+		return err
+	}
+
+	for _, split := range splits {
+		result := tx.Model(&TransactionSplitModel{}).
+			Where("id = ?", split.ID).
+			Updates(map[string]interface{}{
+				"amount":            split.Amount,
+				"settlement_amount": split.SettlementAmount,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	t.Splits = splits
+
+	return nil
+}
+
+// shareAmount part de la transaction revenant à sa boutique, hors parts des bénéficiaires
+func shareAmount(tx *gorm.DB, t *TransactionModel) (float64, error) {
+	splits, err := transactionSplits(tx, t.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Amount - SplitsAmount(splits), nil
+}
+
+// creditSplits versement des parts aux bénéficiaires au passage en SUCCESS
+func creditSplits(tx *gorm.DB, t *TransactionModel) error {
+	splits, err := transactionSplits(tx, t.ID)
+	if err != nil || len(splits) == 0 {
+		return err
+	}
+
+	for _, split := range splits {
+		err := PostLedger(tx, &LedgerEntryModel{
+			ServiceId:     split.ServiceId,
+			TransactionId: t.ID,
+			Kind:          LEDGER_SPLIT,
+			Label:         t.ReferenceId,
+			Amount:        split.SettlementAmount,
+			Currency:      split.SettlementCurrency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&TransactionSplitModel{}).Where("transaction_id = ?", t.ID).Update("refunded_amount", 0).Error
+}
+
+// RefundSplits reprise proportionnelle des parts pour un remboursement de amount (devise de la transaction),
+// renvoie la part du remboursement à la charge de la boutique de la transaction.
+// Doit être appelé dans une transaction de base de donnée
+func RefundSplits(tx *gorm.DB, t *TransactionModel, amount float64) (float64, error) {
+	splits, err := transactionSplits(tx, t.ID)
+	if err != nil || len(splits) == 0 || t.Amount <= 0 {
+		return amount, err
+	}
+
+	ratio := math.Min(amount/t.Amount, 1)
+	refunded := 0.0
+
+	for _, split := range splits {
+		refund := math.Min(RoundAmount(split.Amount*ratio), split.Amount-split.RefundedAmount)
+		if refund <= 0 {
+			continue
+		}
+
+		refunded += refund
+
+		if t.ModeLive {
+			err := PostLedger(tx, &LedgerEntryModel{
+				ServiceId:     split.ServiceId,
+				TransactionId: t.ID,
+				Kind:          LEDGER_SPLIT_REVERSAL,
+				Label:         t.ReferenceId,
+				Amount:        -RoundAmount(split.SettlementAmount * refund / split.Amount),
+				Currency:      split.SettlementCurrency,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		result := tx.Model(&TransactionSplitModel{}).
+			Where("id = ?", split.ID).
+			Update("refunded_amount", gorm.Expr("refunded_amount + ?", refund))
+		if result.Error != nil {
+			return 0, result.Error
+		}
+	}
+
+	return RoundAmount(amount - refunded), nil
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Périodicité d'un plan
+const (
+	INTERVAL_DAY   = "DAY"
+	INTERVAL_WEEK  = "WEEK"
+	INTERVAL_MONTH = "MONTH"
+	INTERVAL_YEAR  = "YEAR"
+)
+
+// Etat d'un abonnement
+const (
+	SUBSCRIPTION_ACTIVE   = "ACTIVE"
+	SUBSCRIPTION_PAUSED   = "PAUSED"
+	SUBSCRIPTION_PAST_DUE = "PAST_DUE" // Echec de paiement, nouvelle tentative programmée
+	SUBSCRIPTION_UNPAID   = "UNPAID"   // Tentatives épuisées, facturation suspendue
+	SUBSCRIPTION_CANCELED = "CANCELED"
+)
+
+// PlanModel plan d'abonnement d'une boutique
+type PlanModel struct {
+	Model
+
+	// Service
+	ServiceId string `json:"service_id" form:"service_id" validate:"required" gorm:"index"`
+
+	Name          string  `json:"name" form:"name" validate:"required"`
+	Amount        float64 `json:"amount" form:"amount" validate:"required,gt=0"`
+	Currency      string  `json:"currency" form:"currency" validate:"required"`
+	Interval      string  `json:"interval" form:"interval" validate:"required,oneof=DAY WEEK MONTH YEAR"`
+	IntervalCount int     `json:"interval_count" form:"interval_count" validate:"omitempty,gte=1"` // Nombre d'intervalles entre deux échéances
+	ModeLive      bool    `json:"mode_live" form:"mode_live" validate:"-"`
+	Active        bool    `json:"active" form:"active" validate:"-"`
+
+	// Relance en cas d'échec
+	MaxRetries         int `json:"max_retries" form:"max_retries" validate:"gte=0"`
+	RetryIntervalHours int `json:"retry_interval_hours" form:"retry_interval_hours" validate:"gte=0"`
+}
+
+// TableName changement du nom de la table
+func (PlanModel) TableName() string {
+	return "subscriptions_plans"
+}
+
+// NextDate échéance suivante
+func (p *PlanModel) NextDate(from time.Time) time.Time {
+	count := p.IntervalCount
+	if count < 1 {
+		count = 1
+	}
+
+	switch p.Interval {
+	case INTERVAL_DAY:
+		return from.AddDate(0, 0, count)
+	case INTERVAL_WEEK:
+		return from.AddDate(0, 0, 7*count)
+	case INTERVAL_YEAR:
+		return from.AddDate(count, 0, 0)
+	default:
+		return from.AddDate(0, count, 0)
+	}
+}
+
+// RetryDelay délai avant une nouvelle tentative
+func (p *PlanModel) RetryDelay() time.Duration {
+	if p.RetryIntervalHours <= 0 {
+		return time.Hour * 24
+	}
+
+	return time.Hour * time.Duration(p.RetryIntervalHours)
+}
+
+// SubscriptionModel abonnement d'un payeur à un plan
+type SubscriptionModel struct {
+	Model
+
+	// Service
+	ServiceId string `json:"service_id" form:"-" validate:"-" gorm:"index"`
+
+	// Plan
+	PlanId string     `json:"plan_id" form:"plan_id" validate:"required" gorm:"index"`
+	Plan   *PlanModel `json:"plan,omitempty" form:"-" validate:"-"`
+
+	// Provider
+	ProviderId string         `json:"provider_id" form:"provider_id" validate:"required"`
+	Provider   *ProviderModel `json:"provider,omitempty" form:"-" validate:"-"`
+
+	PayerPhone  string `json:"payer_phone" form:"payer_phone" validate:"required" gorm:"index"`
+	CustomerId  string `json:"customer_id,omitempty" form:"-" validate:"-" gorm:"index"`
+	ReferenceId string `json:"reference_id" form:"reference_id" validate:"omitempty" gorm:"index"` // Réference du contrat chez le marchand
+
+	State         string     `json:"state" form:"-" validate:"-" gorm:"index"`
+	NextBillingAt time.Time  `json:"next_billing_at" form:"-" validate:"-" gorm:"index"`
+	RetryCount    int        `json:"retry_count" form:"-" validate:"-"`
+	NextRetryAt   *time.Time `json:"next_retry_at,omitempty" form:"-" validate:"-"`
+
+	PendingTransactionId string `json:"pending_transaction_id,omitempty" form:"-" validate:"-"` // Prélèvement en cours
+	LastTransactionId    string `json:"last_transaction_id,omitempty" form:"-" validate:"-"`
+
+	PausedAt   *time.Time `json:"paused_at,omitempty" form:"-" validate:"-"`
+	CanceledAt *time.Time `json:"canceled_at,omitempty" form:"-" validate:"-"`
+
+	CreatedById string `json:"created_by_id" form:"-" validate:"-"`
+}
+
+// TableName changement du nom de la table
+func (SubscriptionModel) TableName() string {
+	return "subscriptions"
+}
+
+// CheckTransition contrôle du changement d'état demandé par la boutique
+func (s *SubscriptionModel) CheckTransition(state string) error {
+	switch state {
+	case SUBSCRIPTION_PAUSED:
+		if s.State != SUBSCRIPTION_ACTIVE && s.State != SUBSCRIPTION_PAST_DUE {
+			return fmt.Errorf("seul un abonnement actif peut être suspendu")
+		}
+
+	case SUBSCRIPTION_ACTIVE:
+		if s.State != SUBSCRIPTION_PAUSED && s.State != SUBSCRIPTION_UNPAID {
+			return fmt.Errorf("seul un abonnement suspendu ou impayé peut être repris")
+		}
+
+	case SUBSCRIPTION_CANCELED:
+		if s.State == SUBSCRIPTION_CANCELED {
+			return fmt.Errorf("abonnement déjà résilié")
+		}
+
+	default:
+		return fmt.Errorf("état d'abonnement invalide")
+	}
+
+	return nil
+}
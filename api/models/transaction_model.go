@@ -1,5 +1,32 @@
 package models
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Mode d'opération
+const (
+	OPERATION_CREDIT = "CREDIT"
+	OPERATION_DEBIT  = "DEBIT"
+)
+
+// Etat de la transaction
+const (
+	TRANSACTION_PENDING    = "PENDING"
+	TRANSACTION_SUCCESS    = "SUCCESS"
+	TRANSACTION_CANCEL     = "CANCEL"
+	TRANSACTION_FAIL       = "FAIL"
+	TRANSACTION_AUTHORIZED = "AUTHORIZED" // Fonds réservés, en attente de capture
+	TRANSACTION_VOID       = "VOID"       // Autorisation libérée sans capture
+)
+
+// Etats engageant le montant de la transaction (plafonds, échéanciers)
+var committedStates = []string{TRANSACTION_PENDING, TRANSACTION_AUTHORIZED, TRANSACTION_SUCCESS}
+
 type TransactionModel struct {
 	Model
 
@@ -8,12 +35,13 @@ type TransactionModel struct {
 	AmountWithFee float64 `json:"amount_with_fee" form:"amount_with_fee" validate:"required"`
 
 	OperationMode  string `json:"operation_mode" form:"operation_mode" validate:"required"`   // CREDIT, DEBIT
-	OperationState string `json:"operation_state" form:"operation_state" validate:"required"` // PENDING, SUCCESS, CANCEL, FAIL
+	OperationState string `json:"operation_state" form:"operation_state" validate:"required"` // PENDING, AUTHORIZED, SUCCESS, CANCEL, FAIL, VOID
 	OperationMsg   string `json:"operation_msg" form:"operation_msg" validate:"-"`
 
 	// Provider
-	ProviderId string        `json:"provider_id" form:"provider_id" validate:"required" gorm:"index"`
-	Provider   ProviderModel `json:"provider" form:"provider" validate:"required"`
+	ProviderId        string        `json:"provider_id" form:"provider_id" validate:"required" gorm:"index"`
+	Provider          ProviderModel `json:"provider" form:"provider" validate:"required"`
+	ProviderReference string        `json:"provider_reference" form:"-" validate:"-" gorm:"index"` // Réference de l'opérateur
 
 	// Service
 	ServiceId string       `json:"service_id" form:"service_id" validate:"required" gorm:"index"`
@@ -21,10 +49,307 @@ type TransactionModel struct {
 
 	// Réference
 	ReferenceId string `json:"reference_id" form:"reference_id" validate:"required"` // Id facture
-	Currency    string `json:"currency" form:"currency" validate:"required"`
+	Currency    string `json:"currency" form:"currency" validate:"required"`         // Code ISO-4217
+
+	// Règlement dans la devise de la boutique, taux fixé à la création
+	SettlementAmount   float64 `json:"settlement_amount" form:"-" validate:"-"`
+	SettlementCurrency string  `json:"settlement_currency,omitempty" form:"-" validate:"-"`
+	ExchangeRate       float64 `json:"exchange_rate,omitempty" form:"-" validate:"-"` // 1 Currency = ExchangeRate SettlementCurrency
+
+	// Payeur
+	PayerPhone string         `json:"payer_phone,omitempty" form:"payer_phone" validate:"omitempty"`
+	PayerIp    string         `json:"payer_ip,omitempty" form:"payer_ip" validate:"omitempty,ip"`
+	CustomerId string         `json:"customer_id,omitempty" form:"-" validate:"-" gorm:"index"`
+	Customer   *CustomerModel `json:"customer,omitempty" form:"customer" validate:"-"`
+
+	// Autorisation puis capture
+	AuthorizeOnly          bool       `json:"authorize_only" form:"-" validate:"-"`   // Création avec capture=false
+	CaptureEmulated        bool       `json:"capture_emulated" form:"-" validate:"-"` // Réservation gérée par la plateforme, le provider ne supporte pas la capture
+	AuthorizedAmount       float64    `json:"authorized_amount,omitempty" form:"-" validate:"-"`
+	CapturedAmount         float64    `json:"captured_amount,omitempty" form:"-" validate:"-"`
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	// Total remboursé à l'initiative de la boutique
+	RefundedAmount float64 `json:"refunded_amount,omitempty" form:"-" validate:"-"`
+
+	// Contrôle anti-fraude
+	FraudScore   int      `json:"fraud_score" form:"-" validate:"-"`
+	FraudAction  string   `json:"fraud_action,omitempty" form:"-" validate:"-" gorm:"index"` // ALLOW, REVIEW, BLOCK (rejet de la revue)
+	fraudReasons []string // Règles déclenchées, reprises dans la revue
+
+	// Lien de paiement à l'origine de la transaction
+	PaymentLinkId string `json:"payment_link_id,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	// Abonnement à l'origine de la transaction
+	SubscriptionId string `json:"subscription_id,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	// Echéancier payé par la transaction
+	InstallmentPlanId string `json:"installment_plan_id,omitempty" form:"-" validate:"-" gorm:"index"`
+
+	// Parts reversées à d'autres boutiques (commissions)
+	Splits []TransactionSplitModel `json:"splits,omitempty" form:"splits" validate:"omitempty,dive" gorm:"foreignKey:TransactionId;constraint:OnDelete:CASCADE;"`
 }
 
 // TableName changement du nom de la table
 func (TransactionModel) TableName() string {
 	return "transactions"
 }
+
+func (t *TransactionModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	t.ID = uuid.String()
+
+	if t.OperationState == "" {
+		t.OperationState = TRANSACTION_PENDING
+	}
+
+	if err := checkOnboarding(tx, t); err != nil {
+		return err
+	}
+
+	if err := convertSettlement(tx, t); err != nil {
+		return err
+	}
+
+	if err := prepareSplits(tx, t); err != nil {
+		return err
+	}
+
+	if err := checkLimits(tx, t); err != nil {
+		return err
+	}
+
+	if err := checkFraud(tx, t); err != nil {
+		return err
+	}
+
+	return attachInstallment(tx, t)
+}
+
+func (t *TransactionModel) AfterCreate(tx *gorm.DB) (err error) {
+	return openFraudReview(tx, t)
+}
+
+// InSettlement montant converti dans la devise de règlement de la boutique,
+// une transaction sans taux enregistré reste dans sa devise
+func (t *TransactionModel) InSettlement(amount float64) (float64, string) {
+	if t.SettlementCurrency == "" || t.ExchangeRate == 0 || t.SettlementCurrency == t.Currency {
+		return amount, t.Currency
+	}
+
+	return RoundAmount(amount * t.ExchangeRate), t.SettlementCurrency
+}
+
+// IsHeld la transaction attend la décision de la revue anti-fraude
+func (t *TransactionModel) IsHeld() bool {
+	return t.FraudAction == FRAUD_REVIEW
+}
+
+// IsFinal la transaction n'est plus en attente, une autorisation attend sa capture
+func (t *TransactionModel) IsFinal() bool {
+	return t.OperationState != TRANSACTION_PENDING && t.OperationState != TRANSACTION_AUTHORIZED
+}
+
+// IsAuthorized les fonds sont réservés et peuvent être capturés ou libérés
+func (t *TransactionModel) IsAuthorized() bool {
+	return t.OperationState == TRANSACTION_AUTHORIZED
+}
+
+// Authorize passage en AUTHORIZED, le montant est réservé jusqu'à l'expiration de l'autorisation
+func (t *TransactionModel) Authorize(tx *gorm.DB, expiresAt time.Time, msg string) error {
+	t.AuthorizedAmount = t.Amount
+	t.AuthorizationExpiresAt = &expiresAt
+
+	result := tx.Model(&TransactionModel{}).
+		Where("id = ?", t.ID).
+		Updates(map[string]interface{}{
+			"authorized_amount":        t.AuthorizedAmount,
+			"authorization_expires_at": t.AuthorizationExpiresAt,
+			"capture_emulated":         t.CaptureEmulated,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return t.SetState(tx, TRANSACTION_AUTHORIZED, msg)
+}
+
+// ClaimCapture réservation de la capture d'une autorisation, le montant de la transaction devient le montant capturé.
+// Doit être appelé dans une transaction de base de donnée: la réservation est faite avant le calcul des parts
+func (t *TransactionModel) ClaimCapture(tx *gorm.DB, amount float64, now time.Time) error {
+	if !t.IsAuthorized() || t.CapturedAmount > 0 {
+		return fmt.Errorf("seule une transaction autorisée peut être capturée")
+	}
+
+	if t.AuthorizationExpiresAt != nil && now.After(*t.AuthorizationExpiresAt) {
+		return fmt.Errorf("autorisation expirée")
+	}
+
+	if amount <= 0 || amount > t.AuthorizedAmount {
+		return fmt.Errorf("montant de capture invalide, maximum %v", t.AuthorizedAmount)
+	}
+
+	amountWithFee := t.AmountWithFee - (t.AuthorizedAmount - amount)
+	settlementAmount, _ := t.InSettlement(amount)
+
+	result := tx.Model(&TransactionModel{}).
+		Where("id = ? AND operation_state = ? AND captured_amount = ?", t.ID, TRANSACTION_AUTHORIZED, 0).
+		Updates(map[string]interface{}{
+			"captured_amount":   amount,
+			"amount":            amount,
+			"amount_with_fee":   amountWithFee,
+			"settlement_amount": settlementAmount,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("capture déjà effectuée ou autorisation libérée")
+	}
+
+	t.CapturedAmount = amount
+	t.Amount = amount
+	t.AmountWithFee = amountWithFee
+	t.SettlementAmount = settlementAmount
+
+	// Les parts sont calculées sur le montant capturé
+	return resizeSplits(tx, t, amount)
+}
+
+// ReleaseCapture annulation d'une capture refusée par le provider, l'autorisation peut être capturée à nouveau.
+// Doit être appelé dans une transaction de base de donnée
+func (t *TransactionModel) ReleaseCapture(tx *gorm.DB) error {
+	t.AmountWithFee = t.AmountWithFee + (t.AuthorizedAmount - t.Amount)
+	t.Amount = t.AuthorizedAmount
+	t.CapturedAmount = 0
+	t.SettlementAmount, _ = t.InSettlement(t.Amount)
+
+	if err := resizeSplits(tx, t, t.Amount); err != nil {
+		return err
+	}
+
+	return tx.Model(&TransactionModel{}).
+		Where("id = ?", t.ID).
+		Updates(map[string]interface{}{
+			"captured_amount":   t.CapturedAmount,
+			"amount":            t.Amount,
+			"amount_with_fee":   t.AmountWithFee,
+			"settlement_amount": t.SettlementAmount,
+		}).Error
+}
+
+// Void libération d'une autorisation non capturée
+func (t *TransactionModel) Void(tx *gorm.DB, msg string) error {
+	result := tx.Model(&TransactionModel{}).
+		Where("id = ? AND operation_state = ? AND captured_amount = ?", t.ID, TRANSACTION_AUTHORIZED, 0).
+		Updates(map[string]interface{}{
+			"operation_state": TRANSACTION_VOID,
+			"operation_msg":   msg,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("seule une autorisation non capturée peut être libérée")
+	}
+
+	t.OperationState = TRANSACTION_VOID
+	t.OperationMsg = msg
+
+	return nil
+}
+
+// SetState changement de l'état de la transaction, le solde de la boutique et de l'échéancier
+// sont mis à jour à l'entrée et à la sortie de l'état SUCCESS (solde boutique: transaction live uniquement).
+// Le changement est conditionnel à l'état lu: si un autre traitement l'a déjà modifié,
+// la transaction est relue et aucun mouvement n'est passé
+func (t *TransactionModel) SetState(tx *gorm.DB, state string, msg string) error {
+	previousState := t.OperationState
+
+	result := tx.Model(&TransactionModel{}).
+		Where("id = ? AND operation_state = ?", t.ID, previousState).
+		Updates(map[string]interface{}{
+			"operation_state":    state,
+			"operation_msg":      msg,
+			"provider_reference": t.ProviderReference,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return tx.Model(&TransactionModel{}).
+			Select("operation_state", "operation_msg", "provider_reference").
+			Where("id = ?", t.ID).
+			Take(t).Error
+	}
+
+	t.OperationState = state
+	t.OperationMsg = msg
+
+	if (previousState == TRANSACTION_SUCCESS) == (state == TRANSACTION_SUCCESS) {
+		return nil
+	}
+
+	// Solde de l'échéancier
+	if err := applyInstallment(tx, t, state == TRANSACTION_SUCCESS); err != nil {
+		return err
+	}
+
+	if !t.ModeLive {
+		return nil
+	}
+
+	// Part de la boutique, les parts des bénéficiaires sont portées à leur solde
+	share, err := shareAmount(tx, t)
+	if err != nil {
+		return err
+	}
+
+	// Le solde de la boutique est tenu dans sa devise de règlement
+	amount, currency := t.InSettlement(share)
+	if t.OperationMode == OPERATION_DEBIT {
+		amount = -amount
+	}
+
+	// Annulation du mouvement d'une transaction qui n'est plus en SUCCESS, seules les parts
+	// et le montant encore portés aux soldes sont repris (rétrocession d'une contestation déjà passée)
+	if previousState == TRANSACTION_SUCCESS {
+		if _, err := RefundSplits(tx, t, t.Amount); err != nil {
+			return err
+		}
+
+		remaining, err := ledgerRemaining(tx, t.ServiceId, t.ID, currency)
+		if err != nil || remaining == 0 {
+			return err
+		}
+
+		return PostLedger(tx, &LedgerEntryModel{
+			ServiceId:     t.ServiceId,
+			TransactionId: t.ID,
+			Kind:          LEDGER_REVERSAL,
+			Label:         t.ReferenceId,
+			Amount:        -remaining,
+			Currency:      currency,
+		})
+	}
+
+	if err := creditSplits(tx, t); err != nil {
+		return err
+	}
+
+	return PostLedger(tx, &LedgerEntryModel{
+		ServiceId:     t.ServiceId,
+		TransactionId: t.ID,
+		Kind:          LEDGER_TRANSACTION,
+		Label:         t.ReferenceId,
+		Amount:        amount,
+		Currency:      currency,
+	})
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Mouvements du relevé liés aux virements internes
+const (
+	LEDGER_TRANSFER_OUT = "TRANSFER_OUT"
+	LEDGER_TRANSFER_IN  = "TRANSFER_IN"
+)
+
+// Evénement d'un virement interne, émis vers les deux boutiques
+const EVENT_TRANSFER_COMPLETED = "transfer.completed"
+
+// TransferModel virement de fonds entre deux boutiques de la plateforme,
+// le montant est débité dans la devise de règlement de la source et crédité dans celle de la destination
+type TransferModel struct {
+	Model
+
+	SourceServiceId string        `json:"source_service_id" form:"-" validate:"-" gorm:"index"`
+	SourceService   *ServiceModel `json:"source_service,omitempty" form:"-" validate:"-" gorm:"foreignKey:SourceServiceId"`
+
+	DestinationServiceId string        `json:"destination_service_id" form:"destination_service_id" validate:"required" gorm:"index"`
+	DestinationService   *ServiceModel `json:"destination_service,omitempty" form:"-" validate:"-" gorm:"foreignKey:DestinationServiceId"`
+
+	Amount   float64 `json:"amount" form:"amount" validate:"required,gt=0"` // Devise de règlement de la source
+	Currency string  `json:"currency" form:"-" validate:"-"`
+	Label    string  `json:"label" form:"label" validate:"omitempty"`
+
+	// Crédit de la destination, taux fixé à l'exécution
+	DestinationAmount   float64 `json:"destination_amount" form:"-" validate:"-"`
+	DestinationCurrency string  `json:"destination_currency" form:"-" validate:"-"`
+	ExchangeRate        float64 `json:"exchange_rate" form:"-" validate:"-"`
+
+	RequestedBy string `json:"requested_by" form:"-" validate:"-"`                       // Utilisateur à l'origine du virement
+	ApprovalId  string `json:"approval_id,omitempty" form:"-" validate:"-" gorm:"index"` // Demande d'approbation, selon la politique
+}
+
+// TableName changement du nom de la table
+func (TransferModel) TableName() string {
+	return "services_transfers"
+}
+
+func (t *TransferModel) BeforeCreate(tx *gorm.DB) (err error) {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	t.ID = uuid.String()
+
+	return
+}
+
+// CheckTransfer contrôle des boutiques et conversion du montant crédité à la destination
+func CheckTransfer(tx *gorm.DB, transfer *TransferModel, now time.Time) error {
+	if transfer.SourceServiceId == transfer.DestinationServiceId {
+		return fmt.Errorf("la boutique de destination doit être différente de la source")
+	}
+
+	if transfer.Amount <= 0 {
+		return fmt.Errorf("montant du virement invalide")
+	}
+
+	source := ServiceModel{}
+	result := tx.Where("id = ?", transfer.SourceServiceId).Limit(1).Find(&source)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	destination := ServiceModel{}
+	result = tx.Where("id = ?", transfer.DestinationServiceId).Limit(1).Find(&destination)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if source.ID == "" || destination.ID == "" {
+		return fmt.Errorf("boutique inexistant")
+	}
+
+	if source.SettlementCurrency == "" || destination.SettlementCurrency == "" {
+		return fmt.Errorf("devise de règlement de la boutique non définie")
+	}
+
+	rate, err := FindExchangeRate(tx, source.SettlementCurrency, destination.SettlementCurrency, now)
+	if err != nil {
+		return err
+	}
+
+	transfer.Amount = RoundAmount(transfer.Amount)
+	transfer.Currency = source.SettlementCurrency
+	transfer.DestinationCurrency = destination.SettlementCurrency
+	transfer.ExchangeRate = rate
+	transfer.DestinationAmount = RoundAmount(transfer.Amount * rate)
+
+	if transfer.Amount > source.CurrentAmount {
+		return insufficientBalance(source.CurrentAmount, source.SettlementCurrency)
+	}
+
+	return nil
+}
+
+// CreateTransfer exécution du virement: débit de la source et crédit de la destination,
+// le solde de la source ne peut devenir négatif. Doit être appelé dans une transaction de base de donnée
+func CreateTransfer(tx *gorm.DB, transfer *TransferModel, now time.Time) error {
+	if err := CheckTransfer(tx, transfer, now); err != nil {
+		return err
+	}
+
+	if err := tx.Create(transfer).Error; err != nil {
+		return err
+	}
+
+	label := transfer.Label
+	if label == "" {
+		label = fmt.Sprintf("Virement %v", transfer.ID)
+	}
+
+	debit := LedgerEntryModel{
+		ServiceId:  transfer.SourceServiceId,
+		TransferId: transfer.ID,
+		Kind:       LEDGER_TRANSFER_OUT,
+		Label:      label,
+		Amount:     -transfer.Amount,
+		Currency:   transfer.Currency,
+	}
+	if err := PostLedger(tx, &debit); err != nil {
+		return err
+	}
+
+	// Solde modifié entre le contrôle et le débit
+	if debit.BalanceAfter < 0 {
+		return insufficientBalance(debit.BalanceAfter+transfer.Amount, transfer.Currency)
+	}
+
+	err := PostLedger(tx, &LedgerEntryModel{
+		ServiceId:  transfer.DestinationServiceId,
+		TransferId: transfer.ID,
+		Kind:       LEDGER_TRANSFER_IN,
+		Label:      label,
+		Amount:     transfer.DestinationAmount,
+		Currency:   transfer.DestinationCurrency,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := PublishEvent(tx, transfer.SourceServiceId, EVENT_TRANSFER_COMPLETED, "transfer", transfer.ID, transfer); err != nil {
+		return err
+	}
+
+	return PublishEvent(tx, transfer.DestinationServiceId, EVENT_TRANSFER_COMPLETED, "transfer", transfer.ID, transfer)
+}
+
+func insufficientBalance(available float64, currency string) error {
+	return ResErrorAPI{ErrorAPI{
+		Code:    "INSUFFICIENT_BALANCE",
+		Message: fmt.Sprintf("solde insuffisant, disponible %.2f %v", available, currency),
+	}}
+}
@@ -60,23 +60,3 @@ func (u *UserModel) IsGrant(role UserRole) bool {
 
 	return false
 }
-
-func (u *UserModel) IsServiceGrant(serviceId string, role ServiceRole) bool {
-	if u.Role == USER_ADMIN {
-		return true
-	}
-
-	for _, perm := range u.ServicePermissions {
-		if serviceId == perm.ServiceId {
-			if perm.Role == SERVICE_ADMIN || perm.Role == role {
-				return true
-			}
-
-			if role == SERVICE_MANAGER && perm.Role == SERVICE_DEV {
-				return true
-			}
-		}
-	}
-
-	return false
-}
@@ -9,8 +9,15 @@ type CustomValidator struct {
 }
 
 func NewCustomValidator() *CustomValidator {
+	validate := validator.New()
+
+	// Code devise ISO-4217, majuscules ou minuscules
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return IsCurrency(fl.Field().String())
+	})
+
 	return &CustomValidator{
-		validator: validator.New(),
+		validator: validate,
 	}
 }
 
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"spay/models"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditActor auteur de la requête: utilisateur connecté, adresse IP et identifiant de requête
+func AuditActor(c echo.Context) models.AuditActor {
+	actor := models.AuditActor{
+		Ip:        c.RealIP(),
+		RequestId: c.Response().Header().Get(echo.HeaderXRequestID),
+	}
+
+	if actor.RequestId == "" {
+		actor.RequestId = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+
+	if claims, ok := c.Get("JWT_CLAIMS").(jwt.MapClaims); ok {
+		actor.ActorId, _ = claims["sub"].(string)
+	}
+
+	return actor
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"fmt"
+	"spay/models"
+	"strings"
+)
+
+// CheckoutUrl adresse publique de la page de paiement d'un lien
+func CheckoutUrl(code string) string {
+	baseUrl := ""
+
+	config, err := models.LoadConfig()
+	if err == nil {
+		baseUrl = strings.TrimRight(config.PublicUrl, "/")
+	}
+
+	return fmt.Sprintf("%v/checkout/%v", baseUrl, code)
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"spay/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SaveCustomer validation du téléphone puis création ou mise à jour du payeur de la boutique,
+// le payeur est identifié par son numéro au format E.164
+func SaveCustomer(tx *gorm.DB, service models.ServiceModel, customer models.CustomerModel) (*models.CustomerModel, error) {
+	country := customer.Country
+	if country == "" {
+		country = service.Country
+	}
+
+	number, err := ParsePayerPhone(country, customer.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := models.CustomerModel{}
+	result := tx.Where("service_id = ? AND phone = ?", service.ID, number.E164).Limit(1).Find(&saved)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		saved = models.CustomerModel{
+			ServiceId:   service.ID,
+			Name:        customer.Name,
+			Email:       customer.Email,
+			Country:     number.Country,
+			PhonePrefix: number.DialCode,
+			PhoneNumber: number.National,
+			Phone:       number.E164,
+			Operator:    number.Operator,
+		}
+
+		if err := tx.Create(&saved).Error; err != nil {
+			return nil, err
+		}
+
+		return &saved, nil
+	}
+
+	// Les informations fournies complètent la fiche existante
+	updates := map[string]interface{}{}
+	if customer.Name != "" && customer.Name != saved.Name {
+		updates["name"] = customer.Name
+	}
+
+	if customer.Email != "" && customer.Email != saved.Email {
+		updates["email"] = customer.Email
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&saved).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &saved, nil
+}
+
+// SuggestProviders providers du pays correspondant à l'opérateur du numéro,
+// tous les providers du pays sont retournés si l'opérateur n'est pas reconnu
+func SuggestProviders(db *gorm.DB, number PhoneNumber) ([]models.ProviderModel, error) {
+	providers := []models.ProviderModel{}
+	result := db.Order("name asc").Find(&providers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	suggested := []models.ProviderModel{}
+	for _, provider := range providers {
+		if number.Country != "" && !provider.SupportCountryCode(number.Country) {
+			continue
+		}
+
+		if number.Operator != "" && !strings.EqualFold(provider.Operator, number.Operator) {
+			continue
+		}
+
+		suggested = append(suggested, provider)
+	}
+
+	return suggested, nil
+}
+
+// CheckProviderOperator le provider choisi correspond à l'opérateur du numéro du payeur
+func CheckProviderOperator(provider models.ProviderModel, number PhoneNumber) error {
+	if provider.Operator == "" || number.Operator == "" {
+		return nil
+	}
+
+	if !strings.EqualFold(provider.Operator, number.Operator) {
+		return fmt.Errorf("le numéro %v n'est pas un numéro %v", number.E164, provider.Operator)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Durée laissée à la boutique pour fournir ses pièces si DISPUTE_EVIDENCE_DAYS n'est pas configuré
+const DEFAULT_DISPUTE_EVIDENCE_DAYS = 7
+
+var disputeSubjects = map[string]string{
+	models.DISPUTE_OPENED:            "Nouvelle contestation de paiement",
+	models.DISPUTE_EVIDENCE_REQUIRED: "Pièces justificatives demandées pour une contestation",
+	models.DISPUTE_WON:               "Contestation gagnée",
+	models.DISPUTE_LOST:              "Contestation perdue",
+}
+
+// DisputeEvidenceDays délai de dépôt des pièces justificatives
+func DisputeEvidenceDays() int {
+	config, err := models.LoadConfig()
+	if err != nil || config.DisputeEvidenceDays <= 0 {
+		return DEFAULT_DISPUTE_EVIDENCE_DAYS
+	}
+
+	return config.DisputeEvidenceDays
+}
+
+// NotifyDispute notification des gestionnaires de la boutique à chaque étape de la contestation,
+// l'envoi est fait en arrière plan et un échec est seulement journalisé
+func NotifyDispute(db *gorm.DB, dispute models.DisputeModel) {
+	subject, ok := disputeSubjects[dispute.State]
+	if !ok {
+		return
+	}
+
+	body := fmt.Sprintf("Contestation %v\nTransaction: %v\nMontant: %.2f %v\nMotif: %v\nEtat: %v\n",
+		dispute.ID, dispute.TransactionId, dispute.Amount, dispute.Currency, dispute.Reason, dispute.State)
+
+	if dispute.EvidenceDueAt != nil && dispute.State == models.DISPUTE_EVIDENCE_REQUIRED {
+		body += fmt.Sprintf("Date limite de dépôt des pièces: %v\n", dispute.EvidenceDueAt.Format("02/01/2006 15:04"))
+	}
+
+	if dispute.Resolution != "" {
+		body += fmt.Sprintf("Décision: %v\n", dispute.Resolution)
+	}
+
+	go func() {
+		if err := NotifyServiceManagers(db, dispute.ServiceId, subject, body); err != nil {
+			log.Error().Err(err).Str("dispute", dispute.ID).Msgf("")
+		}
+	}()
+}
@@ -0,0 +1,317 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"spay/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Nombre de lignes entre deux envois au client
+const exportFlushEvery = 500
+
+// Caractères de tête qui font évaluer une cellule comme une formule
+const exportFormulaChars = "=+-@\t\r"
+
+const (
+	EXPORT_CSV  = "csv"
+	EXPORT_XLSX = "xlsx"
+)
+
+// Devise des montants d'un export de transactions
+const (
+	EXPORT_CURRENCY_TRANSACTION = "transaction" // Devise du paiement
+	EXPORT_CURRENCY_SETTLEMENT  = "settlement"  // Devise de règlement de la boutique
+)
+
+// ExportWriter écriture ligne par ligne d'un export
+type ExportWriter interface {
+	WriteRow(values []interface{}) error
+	Flush() error
+	Close() error
+}
+
+// NewExportWriter création d'un export au format csv ou xlsx
+func NewExportWriter(format string, w io.Writer, sheetName string) (ExportWriter, error) {
+	switch strings.ToLower(format) {
+	case EXPORT_CSV:
+		return &csvExportWriter{writer: csv.NewWriter(w)}, nil
+
+	case EXPORT_XLSX:
+		return NewXlsxWriter(w, sheetName)
+
+	default:
+		return nil, fmt.Errorf("format d'export inconnu %v, csv ou xlsx attendu", format)
+	}
+}
+
+// ExportContentType type mime du format d'export
+func ExportContentType(format string) string {
+	if strings.ToLower(format) == EXPORT_XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// ExportString représentation texte d'une valeur exportée
+func ExportString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		// Neutralise les formules interprétées par les tableurs
+		if strings.IndexAny(v, exportFormulaChars) == 0 {
+			return "'" + v
+		}
+
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = ExportString(value)
+	}
+
+	return w.writer.Write(record)
+}
+
+func (w *csvExportWriter) Flush() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+func (w *csvExportWriter) Close() error {
+	return w.Flush()
+}
+
+// TransactionExporter export des transactions avec les montants dans la devise demandée
+func TransactionExporter(currency string) (func(db *gorm.DB, reqDb *gorm.DB, writer ExportWriter) (int64, error), error) {
+	switch strings.ToLower(currency) {
+	case "", EXPORT_CURRENCY_TRANSACTION:
+		return ExportTransactions, nil
+
+	case EXPORT_CURRENCY_SETTLEMENT:
+		return ExportTransactionsSettlement, nil
+
+	default:
+		return nil, fmt.Errorf("devise d'export inconnue %v, transaction ou settlement attendu", currency)
+	}
+}
+
+// ExportTransactions écriture en flux des transactions sélectionnées par reqDb
+func ExportTransactions(db *gorm.DB, reqDb *gorm.DB, writer ExportWriter) (int64, error) {
+	return exportTransactions(db, reqDb, writer, false)
+}
+
+// ExportTransactionsSettlement export des transactions converties dans la devise de règlement de la boutique
+func ExportTransactionsSettlement(db *gorm.DB, reqDb *gorm.DB, writer ExportWriter) (int64, error) {
+	return exportTransactions(db, reqDb, writer, true)
+}
+
+func exportTransactions(db *gorm.DB, reqDb *gorm.DB, writer ExportWriter, settlement bool) (int64, error) {
+	// Noms des providers
+	providers := []models.ProviderModel{}
+	if result := db.Model(&models.ProviderModel{}).Select("id", "name").Find(&providers); result.Error != nil {
+		return 0, result.Error
+	}
+
+	providerNames := map[string]string{}
+	for _, provider := range providers {
+		providerNames[provider.ID] = provider.Name
+	}
+
+	err := writer.WriteRow([]interface{}{
+		"id", "created_at", "updated_at", "service_id", "provider", "reference_id", "provider_reference",
+		"mode_live", "operation_mode", "operation_state", "amount", "amount_with_fee", "currency", "exchange_rate", "operation_msg",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := reqDb.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		transaction := models.TransactionModel{}
+		if err := db.ScanRows(rows, &transaction); err != nil {
+			return count, err
+		}
+
+		amount, amountWithFee, currency := transaction.Amount, transaction.AmountWithFee, transaction.Currency
+		if settlement {
+			amount, currency = transaction.InSettlement(transaction.Amount)
+			amountWithFee, _ = transaction.InSettlement(transaction.AmountWithFee)
+		}
+
+		err := writer.WriteRow([]interface{}{
+			transaction.ID,
+			transaction.CreatedAt,
+			transaction.UpdatedAt,
+			transaction.ServiceId,
+			providerNames[transaction.ProviderId],
+			transaction.ReferenceId,
+			transaction.ProviderReference,
+			transaction.ModeLive,
+			transaction.OperationMode,
+			transaction.OperationState,
+			amount,
+			amountWithFee,
+			currency,
+			transaction.ExchangeRate,
+			transaction.OperationMsg,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, rows.Err()
+}
+
+// ExportLedger écriture en flux du relevé sélectionné par reqDb
+func ExportLedger(db *gorm.DB, reqDb *gorm.DB, writer ExportWriter) (int64, error) {
+	err := writer.WriteRow([]interface{}{
+		"id", "created_at", "service_id", "transaction_id", "kind", "label", "amount", "balance_after", "currency",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := reqDb.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		entry := models.LedgerEntryModel{}
+		if err := db.ScanRows(rows, &entry); err != nil {
+			return count, err
+		}
+
+		err := writer.WriteRow([]interface{}{
+			entry.ID,
+			entry.CreatedAt,
+			entry.ServiceId,
+			entry.TransactionId,
+			entry.Kind,
+			entry.Label,
+			entry.Amount,
+			entry.BalanceAfter,
+			entry.Currency,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, rows.Err()
+}
+
+// ExportSettlement relevé d'un règlement: récapitulatif puis transactions incluses
+func ExportSettlement(db *gorm.DB, settlement models.SettlementModel, writer ExportWriter) (int64, error) {
+	summary := [][]interface{}{
+		{"settlement_id", settlement.ID},
+		{"service_id", settlement.ServiceId},
+		{"state", settlement.State},
+		{"period_end", settlement.PeriodEnd},
+		{"destination", settlement.Destination},
+		{"currency", settlement.Currency},
+		{"balance_amount", settlement.BalanceAmount},
+		{"reserve_amount", settlement.ReserveAmount},
+		{"fee_amount", settlement.FeeAmount},
+		{"net_amount", settlement.NetAmount},
+		{"transactions_count", settlement.TransactionsCount},
+		{"transactions_amount", settlement.TransactionsAmount},
+		{"payment_reference", settlement.PaymentReference},
+		{},
+		{"transaction_id", "reference_id", "operation_mode", "amount", "currency"},
+	}
+
+	for _, row := range summary {
+		if err := writer.WriteRow(row); err != nil {
+			return 0, err
+		}
+	}
+
+	rows, err := db.Model(&models.SettlementItemModel{}).
+		Where("settlement_id = ?", settlement.ID).
+		Order("created_at asc").
+		Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		item := models.SettlementItemModel{}
+		if err := db.ScanRows(rows, &item); err != nil {
+			return count, err
+		}
+
+		err := writer.WriteRow([]interface{}{
+			item.TransactionId,
+			item.ReferenceId,
+			item.OperationMode,
+			item.Amount,
+			item.Currency,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, rows.Err()
+}
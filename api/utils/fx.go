@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"spay/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Formats de fichier de taux acceptés
+const (
+	RATES_CSV  = "csv"
+	RATES_JSON = "json"
+)
+
+// ratesDocument format json des taux: {"base":"EUR","date":"2024-01-31","rates":{"XOF":655.957}}
+type ratesDocument struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// ParseRatesCSV lecture des lignes base,quote,rate[,date], la ligne d'entête est facultative
+func ParseRatesCSV(r io.Reader, source string) ([]models.ExchangeRateModel, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := []models.ExchangeRateModel{}
+	for i, record := range records {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("ligne %v: base, quote et rate attendus", i+1)
+		}
+
+		rate, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[2]), ",", "."), 64)
+		if err != nil {
+			// Entête
+			if i == 0 {
+				continue
+			}
+
+			return nil, fmt.Errorf("ligne %v: taux invalide %v", i+1, record[2])
+		}
+
+		item := models.ExchangeRateModel{
+			BaseCurrency:  strings.TrimSpace(record[0]),
+			QuoteCurrency: strings.TrimSpace(record[1]),
+			Rate:          rate,
+			Source:        source,
+		}
+
+		if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+			item.EffectiveAt, err = parseRateDate(record[3])
+			if err != nil {
+				return nil, fmt.Errorf("ligne %v: %v", i+1, err)
+			}
+		}
+
+		rates = append(rates, item)
+	}
+
+	return rates, nil
+}
+
+// ParseRatesJSON lecture d'un document {"base","date","rates"}
+func ParseRatesJSON(r io.Reader, source string) ([]models.ExchangeRateModel, error) {
+	document := ratesDocument{}
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	if document.Base == "" {
+		return nil, fmt.Errorf("devise de base absente du document de taux")
+	}
+
+	effectiveAt := time.Time{}
+	if document.Date != "" {
+		var err error
+		effectiveAt, err = parseRateDate(document.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rates := []models.ExchangeRateModel{}
+	for quote, rate := range document.Rates {
+		// La devise de base figure parfois dans la liste avec un taux de 1
+		if strings.EqualFold(quote, document.Base) {
+			continue
+		}
+
+		rates = append(rates, models.ExchangeRateModel{
+			BaseCurrency:  document.Base,
+			QuoteCurrency: quote,
+			Rate:          rate,
+			EffectiveAt:   effectiveAt,
+			Source:        source,
+		})
+	}
+
+	return rates, nil
+}
+
+// ParseRates lecture d'un fichier de taux csv ou json
+func ParseRates(r io.Reader, format string, source string) ([]models.ExchangeRateModel, error) {
+	switch strings.ToLower(format) {
+	case RATES_CSV:
+		return ParseRatesCSV(r, source)
+
+	case RATES_JSON:
+		return ParseRatesJSON(r, source)
+
+	default:
+		return nil, fmt.Errorf("format de taux inconnu %v, csv ou json attendu", format)
+	}
+}
+
+// LoadRatesFile chargement des taux du fichier FX_RATES_FILE, le format est déduit de l'extension
+func LoadRatesFile(db *gorm.DB, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	rates, err := ParseRates(file, strings.TrimPrefix(filepath.Ext(path), "."), models.RATE_SOURCE_FILE)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rates), models.SaveExchangeRates(db, rates)
+}
+
+// FetchRatesAPI chargement des taux depuis l'api FX_RATES_URL (document json)
+func FetchRatesAPI(db *gorm.DB, url string) (int, error) {
+	ctx, ctxCancelFunc := context.WithTimeout(context.Background(), models.ConnectTimeout)
+	defer ctxCancelFunc()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return 0, fmt.Errorf("api de taux en échec (%v)", res.StatusCode)
+	}
+
+	rates, err := ParseRatesJSON(res.Body, models.RATE_SOURCE_API)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rates), models.SaveExchangeRates(db, rates)
+}
+
+func parseRateDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date de taux invalide %v", value)
+	}
+
+	return date, nil
+}
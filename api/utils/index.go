@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"io"
 	"mime/multipart"
 	"net/http"
 	"time"
@@ -12,14 +13,14 @@ func GetFileContentType(ouput multipart.File) (string, error) {
 
 	buf := make([]byte, 512)
 
-	_, err := ouput.Read(buf)
+	n, err := io.ReadFull(ouput, buf)
 
-	if err != nil {
+	if err != nil && err != io.ErrUnexpectedEOF {
 		return "", err
 	}
 
 	// the function that actually does the trick
-	contentType := http.DetectContentType(buf)
+	contentType := http.DetectContentType(buf[:n])
 
 	return contentType, nil
 }
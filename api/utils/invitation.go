@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"spay/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// InvitationTtl durée de validité d'une invitation
+func InvitationTtl() time.Duration {
+	config, err := models.LoadConfig()
+	if err != nil || config.InvitationTtl == "" {
+		return models.DEFAULT_INVITATION_TTL
+	}
+
+	ttl, err := time.ParseDuration(config.InvitationTtl)
+	if err != nil || ttl <= 0 {
+		return models.DEFAULT_INVITATION_TTL
+	}
+
+	return ttl
+}
+
+// SendInvitation envoi du jeton à l'adresse invitée, l'envoi est fait en arrière plan et un échec est seulement journalisé
+func SendInvitation(invitation models.ServiceInvitationModel, serviceName string, token string) {
+	body := fmt.Sprintf("Vous êtes invité à rejoindre la boutique %v avec le rôle %v.\n", serviceName, invitation.Role.String())
+
+	config, err := models.LoadConfig()
+	if err == nil && config.InvitationUrl != "" {
+		body += fmt.Sprintf("Pour accepter l'invitation: %v?token=%v\n", config.InvitationUrl, url.QueryEscape(token))
+	} else {
+		body += fmt.Sprintf("Jeton d'invitation: %v\n", token)
+	}
+
+	body += fmt.Sprintf("Cette invitation expire le %v.\n", invitation.ExpiresAt.Format("02/01/2006 15:04"))
+
+	go func() {
+		if err := SendMail([]string{invitation.Email}, "Invitation à rejoindre "+serviceName, body); err != nil {
+			log.Error().Err(err).Str("invitation", invitation.ID).Msgf("")
+		}
+	}()
+}
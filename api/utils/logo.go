@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"path"
+	"spay/models"
+)
+
+// Côté maximum de la miniature d'un logo, en pixels
+const LOGO_THUMBNAIL_SIZE = 128
+
+// Nombre de pixels maximum d'un logo, évite le décodage d'images démesurées
+const MAX_LOGO_PIXELS = 4096 * 4096
+
+// SaveLogo enregistrement du logo d'une boutique et de sa miniature png sous services/id/logo
+func SaveLogo(file *multipart.FileHeader, serviceId string, name string) (*UploadedFile, *UploadedFile, error) {
+	dir := path.Join("services", serviceId, "logo")
+
+	logo, err := saveUpload(file, dir, name, LogoContentTypes, MAX_LOGO_SIZE)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thumbnail, err := saveThumbnail(logo, dir, name+"_thumb")
+	if err != nil {
+		RemoveUpload(logo.Path)
+		return nil, nil, err
+	}
+
+	return logo, thumbnail, nil
+}
+
+func saveThumbnail(logo *UploadedFile, dir string, name string) (*UploadedFile, error) {
+	storage, err := GetStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := storage.Open(logo.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(src); err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("image illisible")
+	}
+
+	if config.Width*config.Height > MAX_LOGO_PIXELS {
+		return nil, fmt.Errorf("image trop grande, %v pixels maximum", MAX_LOGO_PIXELS)
+	}
+
+	img, _, err := image.Decode(buf)
+	if err != nil {
+		return nil, fmt.Errorf("image illisible")
+	}
+
+	out := new(bytes.Buffer)
+	if err := png.Encode(out, resize(img, LOGO_THUMBNAIL_SIZE)); err != nil {
+		return nil, err
+	}
+
+	relative := path.Join(dir, name+".png")
+
+	size, err := storage.Put(relative, out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadedFile{
+		Path:        relative,
+		FileName:    "thumbnail.png",
+		ContentType: "image/png",
+		Size:        size,
+	}, nil
+}
+
+// resize réduction de l'image pour tenir dans un carré de size pixels, par moyenne des pixels couverts
+func resize(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= size && height <= size {
+		return img
+	}
+
+	newWidth, newHeight := size, height*size/width
+	if height > width {
+		newWidth, newHeight = width*size/height, size
+	}
+
+	newWidth, newHeight = max(newWidth, 1), max(newHeight, 1)
+
+	dst := image.NewRGBA64(image.Rect(0, 0, newWidth, newHeight))
+	for y := 0; y < newHeight; y++ {
+		y0 := bounds.Min.Y + y*height/newHeight
+		y1 := max(bounds.Min.Y+(y+1)*height/newHeight, y0+1)
+
+		for x := 0; x < newWidth; x++ {
+			x0 := bounds.Min.X + x*width/newWidth
+			x1 := max(bounds.Min.X+(x+1)*width/newWidth, x0+1)
+
+			// Moyenne des couleurs prémultipliées, la transparence ne déborde pas sur les bords
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+// SignServiceLogo adresses signées du logo envoyé et de sa miniature, le champ Logo (adresse externe) est conservé
+func SignServiceLogo(service *models.ServiceModel) error {
+	if service == nil || service.LogoPath == "" {
+		return nil
+	}
+
+	logoUrl, _, err := SignedUrl(service.LogoPath, path.Base(service.LogoPath))
+	if err != nil {
+		return err
+	}
+
+	thumbnailUrl, _, err := SignedUrl(service.LogoThumbnailPath, path.Base(service.LogoThumbnailPath))
+	if err != nil {
+		return err
+	}
+
+	service.LogoUrl = logoUrl
+	service.LogoThumbnailUrl = thumbnailUrl
+
+	return nil
+}
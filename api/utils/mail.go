@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"spay/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SendMail envoi d'un email texte, sans effet si le serveur SMTP n'est pas configuré
+func SendMail(to []string, subject string, body string) error {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.SmtpHost == "" || len(to) == 0 {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if config.SmtpUser != "" {
+		auth = smtp.PlainAuth("", config.SmtpUser, config.SmtpPass, config.SmtpHost)
+	}
+
+	message := strings.Join([]string{
+		"From: " + config.SmtpFrom,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(net.JoinHostPort(config.SmtpHost, config.SmtpPort), auth, config.SmtpFrom, to, []byte(message))
+}
+
+// ServiceManagerEmails emails des gestionnaires et administrateurs de la boutique
+func ServiceManagerEmails(db *gorm.DB, serviceId string) ([]string, error) {
+	emails := []string{}
+	result := db.Model(&models.UserModel{}).
+		Joins("JOIN services_permissions ON services_permissions.user_id = users.id").
+		Where("services_permissions.service_id = ? AND services_permissions.role IN (?)", serviceId, []string{models.SERVICE_MANAGER.String(), models.SERVICE_ADMIN.String()}).
+		Where("users.email <> ''").
+		Distinct("users.email").
+		Pluck("users.email", &emails)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return emails, nil
+}
+
+// NotifyServiceManagers envoi d'une notification aux gestionnaires de la boutique
+func NotifyServiceManagers(db *gorm.DB, serviceId string, subject string, body string) error {
+	emails, err := ServiceManagerEmails(db, serviceId)
+	if err != nil {
+		return err
+	}
+
+	if err := SendMail(emails, subject, body); err != nil {
+		return fmt.Errorf("échec de l'envoi de la notification: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var onboardingSubjects = map[string]string{
+	models.ONBOARDING_DRAFT:     "Inscription de la boutique à compléter",
+	models.ONBOARDING_APPROVED:  "Inscription de la boutique approuvée",
+	models.ONBOARDING_SUSPENDED: "Boutique suspendue",
+}
+
+// NotifyOnboarding notification des gestionnaires de la boutique après une décision sur l'inscription,
+// l'envoi est fait en arrière plan et un échec est seulement journalisé
+func NotifyOnboarding(db *gorm.DB, service models.ServiceModel) {
+	subject, ok := onboardingSubjects[service.OnboardingState]
+	if !ok {
+		return
+	}
+
+	body := fmt.Sprintf("Boutique %v (%v)\nEtat: %v\n", service.Name, service.ID, service.OnboardingState)
+
+	if service.OnboardingReason != "" {
+		body += fmt.Sprintf("Motif: %v\n", service.OnboardingReason)
+	}
+
+	if service.OnboardingState == models.ONBOARDING_APPROVED {
+		body += "Les transactions live sont désormais acceptées.\n"
+	} else {
+		body += "Seules les transactions sandbox sont acceptées.\n"
+	}
+
+	go func() {
+		if err := NotifyServiceManagers(db, service.ID, subject, body); err != nil {
+			log.Error().Err(err).Str("service", service.ID).Msgf("")
+		}
+	}()
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Opérateurs mobiles
+const (
+	OPERATOR_ORANGE   = "ORANGE"
+	OPERATOR_MTN      = "MTN"
+	OPERATOR_MOOV     = "MOOV"
+	OPERATOR_FREE     = "FREE"
+	OPERATOR_EXPRESSO = "EXPRESSO"
+)
+
+var phoneRegex = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// phoneCountry règles de numérotation d'un pays
+type phoneCountry struct {
+	DialCode       string              // Indicatif sans le +
+	NationalLength int                 // Nombre de chiffres du numéro national
+	Operators      map[string][]string // Préfixes du numéro national par opérateur
+}
+
+// Plans de numérotation par code pays ISO 3166-1 alpha-3
+var phoneCountries = map[string]phoneCountry{
+	"CIV": {
+		DialCode:       "225",
+		NationalLength: 10,
+		Operators: map[string][]string{
+			OPERATOR_ORANGE: {"07"},
+			OPERATOR_MTN:    {"05"},
+			OPERATOR_MOOV:   {"01"},
+		},
+	},
+	"SEN": {
+		DialCode:       "221",
+		NationalLength: 9,
+		Operators: map[string][]string{
+			OPERATOR_ORANGE:   {"77", "78"},
+			OPERATOR_FREE:     {"76"},
+			OPERATOR_EXPRESSO: {"70"},
+		},
+	},
+	"CMR": {
+		DialCode:       "237",
+		NationalLength: 9,
+		Operators: map[string][]string{
+			OPERATOR_MTN:    {"67", "68", "650", "651", "652", "653", "654"},
+			OPERATOR_ORANGE: {"69", "655", "656", "657", "658", "659"},
+		},
+	},
+}
+
+// PhoneNumber numéro de téléphone analysé
+type PhoneNumber struct {
+	Country  string `json:"country"`            // Code pays (ex: CIV)
+	DialCode string `json:"dial_code"`          // Indicatif (ex: +225)
+	National string `json:"national"`           // Numéro national (ex: 0700000000)
+	E164     string `json:"e164"`               // Format international (ex: +2250700000000)
+	Operator string `json:"operator,omitempty"` // Opérateur détecté (ex: ORANGE)
+}
+
+// NormalizePhone suppression des séparateurs et contrôle du numéro de téléphone
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+
+	if !phoneRegex.MatchString(phone) {
+		return "", fmt.Errorf("numéro de téléphone invalide")
+	}
+
+	return phone, nil
+}
+
+// IsPhoneCountrySupported le pays dispose de règles de numérotation
+func IsPhoneCountrySupported(country string) bool {
+	_, ok := phoneCountries[strings.ToUpper(country)]
+	return ok
+}
+
+// ParsePhone validation du numéro selon le plan de numérotation du pays et détection de l'opérateur,
+// le numéro peut être national (0700000000) ou international (+225 07 00 00 00 00, 00225...)
+func ParsePhone(country string, phone string) (*PhoneNumber, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+
+	rules, ok := phoneCountries[country]
+	if !ok {
+		return nil, fmt.Errorf("pays non supporté pour la validation du téléphone %v", country)
+	}
+
+	phone, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	national := phone
+	switch {
+	case strings.HasPrefix(phone, "+"+rules.DialCode):
+		national = strings.TrimPrefix(phone, "+"+rules.DialCode)
+	case strings.HasPrefix(phone, "00"+rules.DialCode):
+		national = strings.TrimPrefix(phone, "00"+rules.DialCode)
+	case strings.HasPrefix(phone, "+"):
+		return nil, fmt.Errorf("indicatif attendu +%v", rules.DialCode)
+	case len(phone) == len(rules.DialCode)+rules.NationalLength && strings.HasPrefix(phone, rules.DialCode):
+		national = strings.TrimPrefix(phone, rules.DialCode)
+	}
+
+	if len(national) != rules.NationalLength {
+		return nil, fmt.Errorf("numéro de téléphone invalide, %v chiffres attendus", rules.NationalLength)
+	}
+
+	number := PhoneNumber{
+		Country:  country,
+		DialCode: "+" + rules.DialCode,
+		National: national,
+		E164:     "+" + rules.DialCode + national,
+	}
+
+	// Le préfixe le plus long l'emporte
+	prefixLength := 0
+	for operator, prefixes := range rules.Operators {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(national, prefix) && len(prefix) > prefixLength {
+				number.Operator = operator
+				prefixLength = len(prefix)
+			}
+		}
+	}
+
+	return &number, nil
+}
+
+// ParsePayerPhone analyse du numéro du payeur selon le pays de la boutique,
+// seul le format est contrôlé si le pays n'a pas de plan de numérotation
+func ParsePayerPhone(country string, phone string) (*PhoneNumber, error) {
+	if IsPhoneCountrySupported(country) {
+		return ParsePhone(country, phone)
+	}
+
+	phone, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PhoneNumber{
+		Country:  strings.ToUpper(country),
+		National: phone,
+		E164:     phone,
+	}, nil
+}
@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"spay/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PROVIDER_CANCEL_UNSUPPORTED = "annulation non supportée par le provider"
+	PROVIDER_QR_UNSUPPORTED     = "QR code marchand non supporté par le provider"
+)
+
+// Validité d'une autorisation si AUTHORIZATION_EXPIRY n'est pas configuré
+const DEFAULT_AUTHORIZATION_EXPIRY = 7 * 24 * time.Hour
+
+// ProviderPayTransaction demande de paiement d'une transaction auprès du provider
+func ProviderPayTransaction(provider models.ProviderModel, transaction models.TransactionModel) (*models.ProviderResponse, error) {
+	return providerCall(provider.PayUrl, transaction)
+}
+
+// ProviderStartTransaction envoi d'une transaction créée au provider, un refus du provider passe la transaction en FAIL,
+// une transaction retenue par l'anti-fraude n'est envoyée qu'après approbation de la revue
+func ProviderStartTransaction(db *gorm.DB, provider models.ProviderModel, transaction *models.TransactionModel) error {
+	if transaction.IsHeld() {
+		return nil
+	}
+
+	// Sans capture chez le provider, la réservation est tenue par la plateforme
+	// et le paiement n'est demandé qu'à la capture
+	if transaction.AuthorizeOnly && transaction.CapturedAmount == 0 && !provider.SupportCapture() {
+		transaction.CaptureEmulated = true
+
+		return db.Transaction(func(tx *gorm.DB) error {
+			return transaction.Authorize(tx, time.Now().Add(AuthorizationExpiry()), "autorisation émulée")
+		})
+	}
+
+	providerRes, err := ProviderPayTransaction(provider, *transaction)
+	if err != nil {
+		errState := db.Transaction(func(tx *gorm.DB) error {
+			return transaction.SetState(tx, models.TRANSACTION_FAIL, err.Error())
+		})
+		if errState != nil {
+			return errState
+		}
+
+		return err
+	}
+
+	return applyProviderResponse(db, transaction, providerRes)
+}
+
+// ProviderSyncTransaction mise à jour d'une transaction en attente depuis l'état du provider
+func ProviderSyncTransaction(db *gorm.DB, transaction *models.TransactionModel) error {
+	// Une transaction finalisée ou autorisée n'est plus vérifiée, une transaction retenue
+	// par l'anti-fraude n'a pas été envoyée au provider et attend la décision de la revue
+	if transaction.IsFinal() || transaction.IsAuthorized() || transaction.IsHeld() {
+		return nil
+	}
+
+	providerRes, err := ProviderCheckTransaction(transaction.Provider, *transaction)
+	if err != nil {
+		return err
+	}
+
+	return applyProviderResponse(db, transaction, providerRes)
+}
+
+// ProviderCheckTransaction vérification de l'état d'une transaction chez le provider
+func ProviderCheckTransaction(provider models.ProviderModel, transaction models.TransactionModel) (*models.ProviderResponse, error) {
+	return providerCall(provider.PayCheckUrl, transaction)
+}
+
+// ProviderCancelTransaction demande d'annulation d'une transaction chez le provider
+func ProviderCancelTransaction(provider models.ProviderModel, transaction models.TransactionModel) (*models.ProviderResponse, error) {
+	if !provider.SupportCancel() {
+		return nil, fmt.Errorf(PROVIDER_CANCEL_UNSUPPORTED)
+	}
+
+	return providerCall(provider.CancelUrl, transaction)
+}
+
+// ProviderCaptureTransaction capture totale ou partielle d'une autorisation,
+// le paiement est demandé au provider si l'autorisation est émulée
+func ProviderCaptureTransaction(db *gorm.DB, provider models.ProviderModel, transaction *models.TransactionModel, amount float64) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return transaction.ClaimCapture(tx, amount, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	var providerRes *models.ProviderResponse
+	if transaction.CaptureEmulated {
+		providerRes, err = ProviderPayTransaction(provider, *transaction)
+	} else {
+		providerRes, err = providerCall(provider.CaptureUrl, *transaction)
+	}
+
+	if err != nil {
+		// L'autorisation reste capturable
+		errRelease := db.Transaction(func(tx *gorm.DB) error {
+			return transaction.ReleaseCapture(tx)
+		})
+		if errRelease != nil {
+			return errRelease
+		}
+
+		return err
+	}
+
+	return applyProviderResponse(db, transaction, providerRes)
+}
+
+// ProviderVoidTransaction libération d'une autorisation non capturée
+func ProviderVoidTransaction(db *gorm.DB, provider models.ProviderModel, transaction *models.TransactionModel, msg string) error {
+	if !transaction.IsAuthorized() || transaction.CapturedAmount > 0 {
+		return fmt.Errorf("seule une autorisation non capturée peut être libérée")
+	}
+
+	if !transaction.CaptureEmulated {
+		providerRes, err := providerCall(provider.VoidUrl, *transaction)
+		if err != nil {
+			return err
+		}
+
+		if providerRes.State != models.TRANSACTION_CANCEL && providerRes.State != models.TRANSACTION_SUCCESS {
+			return fmt.Errorf("libération refusée par le provider: %v", providerRes.Message)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return transaction.Void(tx, msg)
+	})
+}
+
+// AuthorizationExpiry durée de validité d'une autorisation
+func AuthorizationExpiry() time.Duration {
+	config, err := models.LoadConfig()
+	if err != nil || config.AuthorizationExpiry == "" {
+		return DEFAULT_AUTHORIZATION_EXPIRY
+	}
+
+	expiry, err := time.ParseDuration(config.AuthorizationExpiry)
+	if err != nil || expiry <= 0 {
+		return DEFAULT_AUTHORIZATION_EXPIRY
+	}
+
+	return expiry
+}
+
+// ProviderQrPayload récuperation du contenu du QR code marchand natif du provider
+func ProviderQrPayload(provider models.ProviderModel, transaction models.TransactionModel) (string, error) {
+	if !provider.SupportQr() {
+		return "", fmt.Errorf(PROVIDER_QR_UNSUPPORTED)
+	}
+
+	providerRes, err := providerPost(provider.QrUrl, transaction)
+	if err != nil {
+		return "", err
+	}
+
+	if providerRes.QrPayload == "" {
+		return "", fmt.Errorf("QR code marchand vide")
+	}
+
+	return providerRes.QrPayload, nil
+}
+
+func providerCall(url string, transaction models.TransactionModel) (*models.ProviderResponse, error) {
+	providerRes, err := providerPost(url, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalisation de l'état
+	providerRes.State = strings.ToUpper(strings.Trim(providerRes.State, " "))
+	switch providerRes.State {
+	case models.TRANSACTION_PENDING, models.TRANSACTION_SUCCESS, models.TRANSACTION_CANCEL, models.TRANSACTION_FAIL:
+	default:
+		return nil, fmt.Errorf("état provider inconnu %v", providerRes.State)
+	}
+
+	return providerRes, nil
+}
+
+func providerPost(url string, transaction models.TransactionModel) (*models.ProviderResponse, error) {
+	ctx, ctxCancelFunc := context.WithTimeout(context.Background(), models.ConnectTimeout)
+	defer ctxCancelFunc()
+
+	request := models.ProviderRequest{
+		TransactionId:     transaction.ID,
+		ReferenceId:       transaction.ReferenceId,
+		ProviderReference: transaction.ProviderReference,
+		Amount:            transaction.Amount,
+		Currency:          transaction.Currency,
+		OperationMode:     transaction.OperationMode,
+		ModeLive:          transaction.ModeLive,
+		PayerPhone:        transaction.PayerPhone,
+	}
+
+	// Demande de réservation tant que l'autorisation n'est pas capturée
+	if transaction.AuthorizeOnly && !transaction.CaptureEmulated && transaction.CapturedAmount == 0 {
+		capture := false
+		request.Capture = &capture
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("provider indisponible (%v)", res.StatusCode)
+	}
+
+	providerRes := models.ProviderResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&providerRes); err != nil {
+		return nil, err
+	}
+
+	return &providerRes, nil
+}
+
+func applyProviderResponse(db *gorm.DB, transaction *models.TransactionModel, providerRes *models.ProviderResponse) error {
+	if providerRes.State == transaction.OperationState && providerRes.Reference == "" {
+		return nil
+	}
+
+	if providerRes.Reference != "" {
+		transaction.ProviderReference = providerRes.Reference
+	}
+
+	// Une réservation aboutie chez le provider est une autorisation
+	if providerRes.State == models.TRANSACTION_SUCCESS && transaction.AuthorizeOnly && transaction.CapturedAmount == 0 {
+		return db.Transaction(func(tx *gorm.DB) error {
+			return transaction.Authorize(tx, time.Now().Add(AuthorizationExpiry()), providerRes.Message)
+		})
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return transaction.SetState(tx, providerRes.State, providerRes.Message)
+	})
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	QR_PNG = "png"
+	QR_SVG = "svg"
+
+	QR_PAYLOAD_CHECKOUT = "checkout" // Adresse de la page de paiement
+	QR_PAYLOAD_PROVIDER = "provider" // QR marchand natif du provider
+
+	QR_MIN_SIZE     = 64
+	QR_MAX_SIZE     = 1024
+	QR_DEFAULT_SIZE = 256
+)
+
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// QrOptions options de génération d'un QR code
+type QrOptions struct {
+	Format  string // png, svg
+	Size    int    // Taille en pixel
+	Level   string // Correction d'erreur L, M, Q, H
+	Payload string // checkout, provider
+}
+
+// QrOptionsFromQuery lecture des paramètres format, size, level et payload
+func QrOptionsFromQuery(c echo.Context) (QrOptions, error) {
+	options := QrOptions{
+		Format:  strings.ToLower(c.QueryParam("format")),
+		Size:    QR_DEFAULT_SIZE,
+		Level:   strings.ToUpper(c.QueryParam("level")),
+		Payload: strings.ToLower(c.QueryParam("payload")),
+	}
+
+	if options.Format == "" {
+		options.Format = QR_PNG
+	}
+
+	if options.Format != QR_PNG && options.Format != QR_SVG {
+		return options, fmt.Errorf("format de QR code invalide %v", options.Format)
+	}
+
+	if size := c.QueryParam("size"); size != "" {
+		value, err := strconv.Atoi(size)
+		if err != nil || value < QR_MIN_SIZE || value > QR_MAX_SIZE {
+			return options, fmt.Errorf("taille de QR code invalide, entre %v et %v attendu", QR_MIN_SIZE, QR_MAX_SIZE)
+		}
+
+		options.Size = value
+	}
+
+	if options.Level == "" {
+		options.Level = "M"
+	}
+
+	if _, ok := qrLevels[options.Level]; !ok {
+		return options, fmt.Errorf("niveau de correction invalide %v", options.Level)
+	}
+
+	if options.Payload == "" {
+		options.Payload = QR_PAYLOAD_CHECKOUT
+	}
+
+	if options.Payload != QR_PAYLOAD_CHECKOUT && options.Payload != QR_PAYLOAD_PROVIDER {
+		return options, fmt.Errorf("contenu de QR code invalide %v", options.Payload)
+	}
+
+	return options, nil
+}
+
+// QrCode génération du QR code, retourne l'image et son type de contenu
+func QrCode(content string, options QrOptions) ([]byte, string, error) {
+	qr, err := qrcode.New(content, qrLevels[options.Level])
+	if err != nil {
+		return nil, "", err
+	}
+
+	if options.Format == QR_SVG {
+		return qrSvg(qr.Bitmap(), options.Size), "image/svg+xml", nil
+	}
+
+	png, err := qr.PNG(options.Size)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return png, "image/png", nil
+}
+
+// qrSvg rendu vectoriel des modules du QR code
+func qrSvg(bitmap [][]bool, size int) []byte {
+	buf := new(bytes.Buffer)
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" shape-rendering="crispEdges">`, size, size, len(bitmap), len(bitmap))
+	fmt.Fprintf(buf, `<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="`)
+
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(buf, "M%v %vh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
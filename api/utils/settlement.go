@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"spay/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var settlementSubjects = map[string]string{
+	models.SETTLEMENT_PENDING:    "Nouveau versement en préparation",
+	models.SETTLEMENT_PROCESSING: "Versement émis",
+	models.SETTLEMENT_PAID:       "Versement effectué",
+	models.SETTLEMENT_FAILED:     "Versement rejeté",
+}
+
+// NotifySettlement notification des gestionnaires de la boutique à chaque étape du versement,
+// l'envoi est fait en arrière plan et un échec est seulement journalisé
+func NotifySettlement(db *gorm.DB, settlement models.SettlementModel) {
+	subject, ok := settlementSubjects[settlement.State]
+	if !ok {
+		return
+	}
+
+	body := fmt.Sprintf("Versement %v\nDestination: %v\nSolde: %.2f %v\nRéserve: %.2f %v\nFrais: %.2f %v\nMontant versé: %.2f %v\nTransactions incluses: %v\n",
+		settlement.ID, settlement.Destination,
+		settlement.BalanceAmount, settlement.Currency,
+		settlement.ReserveAmount, settlement.Currency,
+		settlement.FeeAmount, settlement.Currency,
+		settlement.NetAmount, settlement.Currency,
+		settlement.TransactionsCount)
+
+	if settlement.PaymentReference != "" {
+		body += fmt.Sprintf("Référence: %v\n", settlement.PaymentReference)
+	}
+
+	if settlement.FailureReason != "" {
+		body += fmt.Sprintf("Motif du rejet: %v\n", settlement.FailureReason)
+	}
+
+	go func() {
+		if err := NotifyServiceManagers(db, settlement.ServiceId, subject, body); err != nil {
+			log.Error().Err(err).Str("settlement", settlement.ID).Msgf("")
+		}
+	}()
+}
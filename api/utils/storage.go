@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"spay/models"
+	"strconv"
+	"time"
+)
+
+// Pilotes de stockage des fichiers envoyés
+const (
+	STORAGE_LOCAL = "local"
+	STORAGE_S3    = "s3"
+)
+
+// Durée de validité d'une adresse signée si STORAGE_URL_TTL n'est pas configuré
+const DEFAULT_STORAGE_URL_TTL = 15 * time.Minute
+
+// Préfixe des adresses signées, servies sans authentification
+const STORAGE_URL_PREFIX = "/api/files/"
+
+// Storage stockage des fichiers envoyés, les chemins sont relatifs à la racine du stockage
+type Storage interface {
+	Put(relative string, src io.Reader) (int64, error)
+	Open(relative string) (io.ReadCloser, error)
+	Remove(relative string) error
+}
+
+// LocalStorage stockage sur disque sous UPLOAD_DIR
+type LocalStorage struct{}
+
+func (LocalStorage) Put(relative string, src io.Reader) (int64, error) {
+	path, err := UploadPath(relative)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, err
+	}
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o640)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	size, err := io.Copy(dst, src)
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func (LocalStorage) Open(relative string) (io.ReadCloser, error) {
+	path, err := UploadPath(relative)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (LocalStorage) Remove(relative string) error {
+	path, err := UploadPath(relative)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// GetStorage stockage configuré par STORAGE_DRIVER, local par défaut
+func GetStorage() (Storage, error) {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.StorageDriver {
+	case "", STORAGE_LOCAL:
+		return LocalStorage{}, nil
+
+	case STORAGE_S3:
+		return nil, fmt.Errorf("stockage s3 non disponible")
+	}
+
+	return nil, fmt.Errorf("pilote de stockage inconnu %v", config.StorageDriver)
+}
+
+// RemoveUpload suppression d'un fichier du stockage, un échec est retourné sans interrompre l'appelant
+func RemoveUpload(relative string) error {
+	if relative == "" {
+		return nil
+	}
+
+	storage, err := GetStorage()
+	if err != nil {
+		return err
+	}
+
+	return storage.Remove(relative)
+}
+
+// SignedUrl adresse de téléchargement d'un fichier du stockage, valable STORAGE_URL_TTL
+func SignedUrl(relative string, fileName string) (string, time.Time, error) {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if config.StorageSignKey == "" {
+		return "", time.Time{}, fmt.Errorf("clé de signature du stockage non configurée")
+	}
+
+	ttl := DEFAULT_STORAGE_URL_TTL
+	if config.StorageUrlTtl != "" {
+		if ttl, err = time.ParseDuration(config.StorageUrlTtl); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("name", fileName)
+	query.Set("expires", expires)
+	query.Set("signature", signUpload(config.StorageSignKey, relative, fileName, expires))
+
+	return config.PublicUrl + STORAGE_URL_PREFIX + filepath.ToSlash(relative) + "?" + query.Encode(), expiresAt, nil
+}
+
+// CheckSignedUrl contrôle de la signature et de l'expiration d'une adresse de téléchargement
+func CheckSignedUrl(relative string, fileName string, expires string, signature string) error {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.StorageSignKey == "" {
+		return fmt.Errorf("clé de signature du stockage non configurée")
+	}
+
+	expected := signUpload(config.StorageSignKey, relative, fileName, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("signature invalide")
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return fmt.Errorf("adresse expirée")
+	}
+
+	return nil
+}
+
+func signUpload(key string, relative string, fileName string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(filepath.ToSlash(relative) + "\n" + fileName + "\n" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
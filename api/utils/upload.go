@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"spay/models"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Taille maximum d'un fichier envoyé
+const MAX_UPLOAD_SIZE = 10 << 20
+
+// Taille maximum d'un logo
+const MAX_LOGO_SIZE = 2 << 20
+
+// Types de pièces justificatives acceptés
+var DocumentContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// Types de logos acceptés
+var LogoContentTypes = []string{"image/jpeg", "image/png"}
+
+// Extension enregistrée pour chaque type accepté, déduite du contenu
+var uploadExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// UploadedFile fichier enregistré dans le stockage
+type UploadedFile struct {
+	Path        string // Chemin relatif à la racine du stockage
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+// SaveUpload contrôle du type et de la taille puis enregistrement du fichier sous dir/name
+func SaveUpload(file *multipart.FileHeader, dir string, name string, contentTypes []string) (*UploadedFile, error) {
+	return saveUpload(file, dir, name, contentTypes, MAX_UPLOAD_SIZE)
+}
+
+func saveUpload(file *multipart.FileHeader, dir string, name string, contentTypes []string, maxSize int64) (*UploadedFile, error) {
+	if file.Size > maxSize {
+		return nil, fmt.Errorf("fichier trop volumineux, %v Ko maximum", maxSize>>10)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// Le type est déduit du contenu et non de l'extension
+	contentType, err := GetFileContentType(src)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType = strings.Split(contentType, ";")[0]
+	if !slices.Contains(contentTypes, contentType) {
+		return nil, fmt.Errorf("type de fichier non accepté %v", contentType)
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	storage, err := GetStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	// L'extension fournie par le client n'est pas fiable
+	relative := filepath.ToSlash(filepath.Join(dir, name+uploadExtensions[contentType]))
+
+	// La taille annoncée par le client n'est pas fiable
+	size, err := storage.Put(relative, io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxSize {
+		storage.Remove(relative)
+		return nil, fmt.Errorf("fichier trop volumineux, %v Ko maximum", maxSize>>10)
+	}
+
+	return &UploadedFile{
+		Path:        relative,
+		FileName:    filepath.Base(file.Filename),
+		ContentType: contentType,
+		Size:        size,
+	}, nil
+}
+
+// SendUpload envoi d'un fichier du stockage, en pièce jointe ou affiché dans le navigateur
+func SendUpload(c echo.Context, relative string, fileName string, contentType string, inline bool) error {
+	storage, err := GetStorage()
+	if err != nil {
+		return err
+	}
+
+	src, err := storage.Open(relative)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("%v; filename=%q", disposition, fileName))
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+
+	return c.Stream(http.StatusOK, contentType, src)
+}
+
+// UploadContentType type d'un fichier du stockage d'après son extension,
+// vide si le type ne fait pas partie des types acceptés à l'envoi
+func UploadContentType(relative string) string {
+	contentType := strings.Split(mime.TypeByExtension(strings.ToLower(path.Ext(relative))), ";")[0]
+	if _, ok := uploadExtensions[contentType]; !ok {
+		return ""
+	}
+
+	return contentType
+}
+
+// UploadPath chemin absolu d'un fichier du dossier d'upload
+func UploadPath(relative string) (string, error) {
+	config, err := models.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if config.UploadDir == "" {
+		return "", fmt.Errorf("dossier d'upload non configuré")
+	}
+
+	path := filepath.Join(config.UploadDir, filepath.Clean("/"+relative))
+
+	return path, nil
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%v" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+// XlsxWriter écriture en flux d'un classeur excel d'une seule feuille,
+// les lignes ne sont jamais conservées en mémoire
+type XlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func NewXlsxWriter(w io.Writer, sheetName string) (*XlsxWriter, error) {
+	zw := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xlsxEscape(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, part := range parts {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// La feuille est la dernière entrée de l'archive
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(sw)
+	_, err = sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XlsxWriter{
+		zw:    zw,
+		sheet: sheet,
+	}, nil
+}
+
+// WriteRow ajout d'une ligne, les nombres sont écrits en cellules numériques
+func (x *XlsxWriter) WriteRow(values []interface{}) error {
+	x.row++
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`<row r="%v">`, x.row))
+
+	for i, value := range values {
+		ref := xlsxColumn(i) + strconv.Itoa(x.row)
+
+		switch v := value.(type) {
+		case float64:
+			b.WriteString(fmt.Sprintf(`<c r="%v"><v>%v</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64)))
+		case int, int64:
+			b.WriteString(fmt.Sprintf(`<c r="%v"><v>%v</v></c>`, ref, v))
+		default:
+			b.WriteString(fmt.Sprintf(`<c r="%v" t="inlineStr"><is><t>%v</t></is></c>`, ref, xlsxEscape(ExportString(v))))
+		}
+	}
+
+	b.WriteString(`</row>`)
+
+	_, err := x.sheet.WriteString(b.String())
+
+	return err
+}
+
+func (x *XlsxWriter) Flush() error {
+	return x.sheet.Flush()
+}
+
+// Close fin de la feuille et de l'archive
+func (x *XlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return x.zw.Close()
+}
+
+// xlsxColumn nom de colonne excel (0 => A, 26 => AA)
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
+
+func xlsxEscape(value string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+
+	return b.String()
+}